@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-3dprint/messages"
+	"strings"
+
+	"github.com/ninja-software/terror"
+)
+
+// HostActionPrefix starts every host action command sent by the firmware
+const HostActionPrefix = "//action:"
+
+// HostAction is a //action: line, sent when someone uses the printer's display
+type HostAction struct {
+	Name   string
+	Params string
+}
+
+// ParseHostAction parses "//action:prompt_begin Filament runout" into its name and params
+func ParseHostAction(line string) (*HostAction, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, HostActionPrefix) {
+		return nil, false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(line, HostActionPrefix), " ", 2)
+	action := &HostAction{Name: strings.TrimSpace(parts[0])}
+	if len(parts) == 2 {
+		action.Params = strings.TrimSpace(parts[1])
+	}
+	return action, true
+}
+
+// ErrNoPrompt is returned when answering a prompt that isn't showing
+var ErrNoPrompt = errors.New("printer is not showing a prompt")
+
+// handleHostAction turns a button press on the printer into the matching agent action
+func (a *Agent) handleHostAction(action *HostAction) {
+	log.Infow("Host action", "name", action.Name, "params", action.Params)
+	switch action.Name {
+	case "pause", "paused":
+		// paused means the firmware has stopped by itself, either way nothing more gets sent
+		if action.Params == "filament_runout" {
+			a.Pause(messages.PauseReasonFilamentRunout)
+			return
+		}
+		a.Pause(messages.PauseReasonPrinter)
+	case "out_of_filament":
+		a.Pause(messages.PauseReasonFilamentRunout)
+	case "resume", "resumed":
+		a.resumed()
+	case "cancel":
+		a.Cancel()
+	case "prompt_begin":
+		a.Lock()
+		a.pendingPrompt = &messages.Prompt{Message: action.Params, Choices: []string{}}
+		a.Unlock()
+	case "prompt_choice", "prompt_button":
+		a.Lock()
+		if a.pendingPrompt != nil {
+			a.pendingPrompt.Choices = append(a.pendingPrompt.Choices, action.Params)
+		}
+		a.Unlock()
+	case "prompt_show":
+		a.Lock()
+		a.Prompt = a.pendingPrompt
+		a.Unlock()
+		a.sendStatus(context.Background())
+	case "prompt_end":
+		a.Lock()
+		a.Prompt = nil
+		a.pendingPrompt = nil
+		a.Unlock()
+		a.sendStatus(context.Background())
+	case "notification":
+		log.Infow("Printer notification", "message", action.Params)
+	default:
+		log.Warnw("Unhandled host action", "name", action.Name)
+	}
+}
+
+// AnswerPrompt sends the chosen button back to the firmware.
+// The firmware is usually blocked waiting for the answer, so it skips the job and goes straight to the port.
+func (a *Agent) AnswerPrompt(choice int) error {
+	a.Lock()
+	prompt := a.Prompt
+	a.Unlock()
+	if prompt == nil {
+		return ErrNoPrompt
+	}
+	if choice < 0 || (len(prompt.Choices) > 0 && choice >= len(prompt.Choices)) {
+		return fmt.Errorf("choice %d out of range", choice)
+	}
+	err := a.writeDirect(fmt.Sprintf("M876 S%d", choice), true)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	a.Lock()
+	a.Prompt = nil
+	a.Unlock()
+	return nil
+}
@@ -0,0 +1,34 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHostAction(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   *HostAction
+		wantOK bool
+	}{
+		{"//action:pause", &HostAction{Name: "pause"}, true},
+		{"//action:paused filament_runout", &HostAction{Name: "paused", Params: "filament_runout"}, true},
+		{"//action:prompt_begin Filament runout detected ", &HostAction{Name: "prompt_begin", Params: "Filament runout detected"}, true},
+		{"  //action:cancel\r\n", &HostAction{Name: "cancel"}, true},
+		{"//action:", &HostAction{}, true},
+		{"echo://action:pause", nil, false},
+		{"ok", nil, false},
+		{"", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := ParseHostAction(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,18 +3,17 @@ package agent
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"go-3dprint/codec"
+	"go-3dprint/gcodefile"
 	"go-3dprint/messages"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/gofrs/uuid"
 	"github.com/ninja-software/terror"
 	"go.bug.st/serial"
 	"go.uber.org/zap"
@@ -30,36 +29,83 @@ func init() {
 	log = logger.Sugar()
 }
 
+// Version of the agent, reported to the server during the handshake
+const Version = "0.1.0"
+
+// ErrRejected is returned when the server refuses the agent's handshake
+var ErrRejected = errors.New("agent rejected by server")
+
+// ErrBusy is returned when a job is already running
+var ErrBusy = errors.New("agent is busy, try again later")
+
+// ErrHalted is returned when the printer is latched in a halted state and needs a reset
+var ErrHalted = errors.New("printer is halted, reset it first")
+
 // Agent holds state of the printer
 type Agent struct {
-	Conn       *websocket.Conn
-	Serial     serial.Port
-	LoadedFile []byte
-	Busy       bool                 // No print commands allowed
-	Status     messages.AgentStatus // What printer is currently doing
-	*sync.Mutex
-	WebsocketHost string
-	WebsocketPort string
-}
-
-// New agent
-func New(ctx context.Context, serialConn serial.Port, wsConn *websocket.Conn, wshost, wsport string) *Agent {
+	Name          string // Printer name from the config, to tell printers on one agent apart
+	SerialDevice  string // Port the printer is on, or auto
+	Token         string // Identifies the printer to the server
+	Conn          *websocket.Conn
+	Serial        serial.Port // Nil while the printer isn't connected
+	LoadedFile    []byte
+	Busy          bool                   // No print commands allowed
+	Status        messages.AgentStatus   // What printer is currently doing
+	*sync.Mutex                          // Guards the printer's state, which the serial reader, jobs and the server all change
+	ServerURL     string                 // Websocket URL of the server
+	client        *http.Client           // Trusts the server's certificate and presents ours, if configured
+	SessionID     string                 // Assigned by the server on handshake
+	Firmware      *messages.FirmwareInfo // Reported by M115
+	Prompt        *messages.Prompt       // Question showing on the printer's display
+	PauseReason   messages.PauseReason   // Why the job is paused
+	job           *job                   // Currently running job, nil when idle
+	pendingPrompt *messages.Prompt       // Prompt being built from //action:prompt_ lines
+	Flow          FlowSettings           // How jobs are streamed to the printer
+	Temperatures  map[string]messages.Temperature
+	ErrorReason   string        // Firmware message that put the agent in the error state
+	errorSeverity ErrorSeverity // How the error state has to be cleared
+	reader        *serialReader // Everything the printer says goes through here, replaced along with Serial on reconnect
+	openSerial    SerialOpener
+	outbox        *outbox    // Messages for the server, kept while it can't be reached
+	panics        chan error // A goroutine of the agent's panicked, Run gives up
+}
+
+// New agent for the printer. The serial port is opened in the background and reopened
+// whenever it goes away, until ctx is done. Run connects it to the server.
+func New(ctx context.Context, printer *PrinterConfig, server *ServerConfig) (*Agent, error) {
+	serverURL, err := server.WebsocketURL()
+	if err != nil {
+		return nil, err
+	}
+	client, err := server.Client()
+	if err != nil {
+		return nil, err
+	}
 	a := &Agent{
-		wsConn,
-		serialConn,
-		[]byte{},
-		false,
-		messages.StatusIdle,
-		&sync.Mutex{},
-		wshost,
-		wsport,
+		Name:         printer.Name,
+		SerialDevice: printer.SerialDevice,
+		Token:        printer.Token,
+		LoadedFile:   []byte{},
+		Busy:         false,
+		Status:       messages.StatusDisconnected,
+		Mutex:        &sync.Mutex{},
+		ServerURL:    serverURL,
+		client:       client,
+		Firmware:     &messages.FirmwareInfo{Capabilities: map[string]bool{}},
+		Flow:         printer.Flow(),
+		Temperatures: map[string]messages.Temperature{},
+		reader:       newSerialReader(),
+		openSerial:   OpenSerial(printer.SerialDevice, printer.BaudRate),
+		outbox:       newOutbox(),
+		panics:       make(chan error, 1),
 	}
-	return a
+	a.goSafe(func() { a.superviseSerial(ctx) })
+	return a, nil
 }
 
 // Reconnect creates a new conn to websocket
 func (a *Agent) Reconnect(ctx context.Context) error {
-	wsconn, _, err := websocket.Dial(ctx, fmt.Sprintf("ws://%s:%s/api/websocket", a.WebsocketHost, a.WebsocketPort), nil)
+	wsconn, _, err := websocket.Dial(ctx, a.ServerURL, &websocket.DialOptions{HTTPClient: a.client})
 	if err != nil {
 		return err
 	}
@@ -67,119 +113,378 @@ func (a *Agent) Reconnect(ctx context.Context) error {
 	return nil
 }
 
-// Subscribe to messages from the server
-func (a *Agent) Subscribe(ctx context.Context) {
-	// Send agent info to server
-	go func() {
-		for {
-			time.Sleep(1 * time.Second)
-			msg := &messages.AsyncCommand{
-				RequestID:   uuid.Must(uuid.NewV4()).String(),
-				MessageType: messages.TypeInfo,
-				RequestType: messages.InfoAgentStatus,
-				Payload:     nil,
-			}
-			b, err := json.Marshal(&messages.AgentInfo{Busy: a.Busy, Status: a.Status})
-			if err != nil {
-				terror.Echo(err)
-				continue
+// Run keeps the agent connected to the server until ctx is done or the server rejects it,
+// reconnecting with backoff. Jobs carry on while the server is away, what they report
+// waits in the outbox. A panic in any of the agent's goroutines ends it with ErrPanicked.
+func (a *Agent) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	a.goSafe(func() { done <- a.run(ctx) })
+	select {
+	case err := <-done:
+		return err
+	case err := <-a.panics:
+		// Whatever panicked may have left the port open, the next agent has to be able to open it
+		port, _ := a.serialPort()
+		if port != nil {
+			port.Close()
+		}
+		return err
+	}
+}
+
+func (a *Agent) run(ctx context.Context) error {
+	delay := ReconnectMinDelay
+	for {
+		err := a.Reconnect(ctx)
+		if err == nil {
+			connected := time.Now()
+			err = a.Subscribe(ctx)
+			a.Conn.Close(websocket.StatusNormalClosure, "")
+			if errors.Is(err, ErrRejected) {
+				return err
 			}
-			msg.Payload = b
-			err = wsjson.Write(ctx, a.Conn, msg)
-			if err != nil {
-				terror.Echo(err)
-				continue
+			if time.Since(connected) > ReconnectMaxDelay {
+				// The connection was good for a while, this is a fresh failure
+				delay = ReconnectMinDelay
 			}
 		}
-	}()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Warnw("Lost connection to server", "err", err, "retry_in", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+		if delay > ReconnectMaxDelay {
+			delay = ReconnectMaxDelay
+		}
+	}
+}
+
+// Hello describes the agent to the server
+func (a *Agent) Hello() *messages.PayloadHello {
+	return &messages.PayloadHello{
+		ProtocolVersion: messages.ProtocolVersion,
+		AgentVersion:    Version,
+		Name:            a.Name,
+		Token:           a.Token,
+		Firmware:        *a.firmware(),
+		Capabilities: []messages.Capability{
+			messages.CapabilityLoad,
+			messages.CapabilityPrint,
+			messages.CapabilityAutoHome,
+			messages.CapabilityEmergencyStop,
+		},
+	}
+}
+
+// Handshake introduces the agent to the server and waits for it to be accepted
+func (a *Agent) Handshake(ctx context.Context) error {
+	msg, err := messages.Encode(messages.TypeInfo, messages.InfoHello, a.Hello())
+	if err != nil {
+		return terror.New(err, "")
+	}
+	err = wsjson.Write(ctx, a.Conn, msg)
+	if err != nil {
+		return terror.New(err, "")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	result := &messages.AsyncCommand{}
+	err = wsjson.Read(ctx, a.Conn, result)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	payload, err := messages.Decode(result)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	switch p := payload.(type) {
+	case *messages.PayloadHelloAck:
+		a.SessionID = p.SessionID
+		log.Infow("Handshake complete", "session_id", p.SessionID, "protocol_version", p.ProtocolVersion)
+		return nil
+	case *messages.PayloadHelloReject:
+		return fmt.Errorf("%w: %s", ErrRejected, p.Reason)
+	}
+	return fmt.Errorf("unexpected handshake response %s", result.RequestType)
+}
+
+// Subscribe to messages from the server until the connection ends.
+// Jobs started here run on ctx, so they outlive the connection.
+func (a *Agent) Subscribe(ctx context.Context) error {
+	err := a.Handshake(ctx)
+	if err != nil {
+		return err
+	}
+	conn := a.Conn
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// A broken write or a missed ping ends the session, closing the conn unblocks the read below
+	drop := func(err error) {
+		if sessionCtx.Err() != nil {
+			return
+		}
+		log.Warnw("Dropping connection to server", "err", err)
+		conn.Close(websocket.StatusGoingAway, "connection lost")
+	}
+	a.goSafe(func() { drop(a.writeLoop(sessionCtx, conn)) })
+	a.goSafe(func() { drop(a.keepalive(sessionCtx, conn)) })
+	a.goSafe(func() { a.reportStatus(sessionCtx) })
+	a.sendStatus(ctx)
 	for {
-		time.Sleep(500 * time.Millisecond)
 		result := &messages.AsyncCommand{}
-		err := wsjson.Read(ctx, a.Conn, result)
+		err := wsjson.Read(ctx, conn, result)
 		if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
-			fmt.Println("websocket closed")
-			return
+			log.Infow("Server closed the connection")
+			return nil
 		}
 		if err != nil {
-			fmt.Println(err)
-			return
+			return terror.New(err, "")
+		}
+		payload, err := messages.Decode(result)
+		if err != nil {
+			log.Errorw("Could not decode message from server", "type", result.RequestType, "err", err)
+			continue
 		}
 		switch result.RequestType {
-		case messages.CommandLoad:
-			fmt.Println("AGENT LOAD RECEIVED ")
-			payload := &messages.PayloadLoadFile{}
-			err = json.Unmarshal(result.Payload, payload)
+		case messages.CommandEmergencyStop:
+			// Handled inline so it never waits behind a job
+			err = a.EmergencyStop()
 			if err != nil {
-				fmt.Println(err)
-				continue
+				terror.Echo(err)
 			}
-			resp, err := http.Get(payload.URL)
+		case messages.CommandPause:
+			a.Pause(messages.PauseReasonUser)
+		case messages.CommandResume:
+			err = a.Resume()
 			if err != nil {
-				fmt.Println(err)
-				continue
+				terror.Echo(err)
 			}
-			if resp.StatusCode != 200 {
-				fmt.Println("non 200 code:", resp.StatusCode)
-				continue
+		case messages.CommandLoadFilament:
+			err = a.runMacro(ctx, GCodeLoadFilament)
+			if err != nil {
+				terror.Echo(err)
 			}
-			b, err := ioutil.ReadAll(resp.Body)
+		case messages.CommandUnloadFilament:
+			err = a.runMacro(ctx, GCodeUnloadFilament)
 			if err != nil {
-				fmt.Println(err)
-				continue
+				terror.Echo(err)
 			}
-			log.Infow("Downloaded gcode", "bytes", len(a.LoadedFile), "url", payload.URL)
-			a.LoadedFile = b
-			a.Status = messages.StatusReady
-
+		case messages.CommandCancel:
+			a.Cancel()
+		case messages.CommandPromptResponse:
+			err = a.AnswerPrompt(payload.(*messages.PayloadPromptResponse).Choice)
+			if err != nil {
+				terror.Echo(err)
+			}
+		case messages.CommandUnlockPrinter:
+			a.goSafe(func() {
+				err := a.Reset(ctx)
+				if err != nil {
+					terror.Echo(err)
+				}
+			})
+		case messages.CommandLoad:
+			// Downloading a big file mustn't hold up the commands behind it, emergency stop least of all
+			payload := payload.(*messages.PayloadLoadFile)
+			a.goSafe(func() {
+				err := a.load(ctx, payload.URL)
+				if err != nil {
+					log.Errorw("Could not load gcode", "url", payload.URL, "err", err)
+				}
+			})
 		case messages.CommandStart:
-			fmt.Println("AGENT START RECEIVED")
-			log.Infow("Loaded gcode", "bytes", len(a.LoadedFile))
-			err = print(ctx, a.Serial, bytes.NewReader(a.LoadedFile))
+			a.Lock()
+			file := a.LoadedFile
+			a.Unlock()
+			log.Infow("Starting job", "bytes", len(file))
+			err = a.runJob(ctx, bytes.NewReader(file))
+			if err != nil {
+				terror.Echo(err)
+			}
+		default:
+			err = a.ProcessMessage(ctx, result)
 			if err != nil {
-				fmt.Println(err)
-				continue
+				terror.Echo(err)
 			}
 		}
 
 	}
 }
 
-// ProcessMessage prints stuff, mutex locked
-func (a *Agent) ProcessMessage(result *messages.AsyncCommand) {
-	if a.Busy {
-		terror.Echo(errors.New("agent is busy, try again later"))
-		return
+// ProcessMessage runs the canned gcode scripts
+func (a *Agent) ProcessMessage(ctx context.Context, result *messages.AsyncCommand) error {
+	switch result.RequestType {
+	case messages.CommandLevelBedTest:
+		return a.runJob(ctx, strings.NewReader(GCodeLevelBedTest))
+	case messages.CommandAutoHome:
+		return a.runJob(ctx, strings.NewReader(GCodeAutoHome))
 	}
+	return fmt.Errorf("unhandled request type %s", result.RequestType)
+}
 
+// load downloads the gcode to print next
+func (a *Agent) load(ctx context.Context, url string) error {
 	a.Lock()
-	ctx := context.Background()
-	a.Busy = true
-	defer func() {
-		a.Busy = false
-		a.Unlock()
-	}()
+	err := a.latched()
+	a.Unlock()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	// Files are only served to logged in users and agents
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	// Ask for the file compressed, the server stores it that way
+	req.Header.Set("Accept-Encoding", codec.Zstd+", "+codec.Gzip)
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server answered %d", resp.StatusCode)
+	}
+	encoding := resp.Header.Get("Content-Encoding")
+	if encoding == "" {
+		encoding = codec.Identity
+	}
+	b, err := codec.Decode(encoding, resp.Body, 0)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	// Binary gcode and 3MF archives are unpacked to text to be sent line by line
+	file, err := gcodefile.Decode(b, 0)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	log.Infow("Downloaded gcode", "bytes", len(file.Gcode), "format", file.Format, "url", url)
+	a.Lock()
+	a.LoadedFile = file.Gcode
+	if !a.Busy {
+		a.Status = a.idleStatus()
+	}
+	a.Unlock()
+	return a.sendStatus(ctx)
+}
 
-	if result.RequestType == messages.CommandLevelBedTest {
-		err := a.Print(ctx, strings.NewReader(GCodeLevelBedTest))
-		if err != nil {
-			terror.Echo(err)
+// runJob streams gcode to the printer in the background. Only one job runs at a time.
+func (a *Agent) runJob(ctx context.Context, r io.Reader) error {
+	a.Lock()
+	err := a.latched()
+	if err == nil && a.Busy {
+		err = ErrBusy
+	}
+	if err != nil {
+		a.Unlock()
+		return err
+	}
+	a.Busy = true
+	a.Status = messages.StatusPrinting
+	jobCtx, cancel := context.WithCancel(ctx)
+	j := newJob(cancel)
+	a.job = j
+	a.Unlock()
+	a.goSafe(func() {
+		defer func() {
+			cancel()
+			a.Lock()
+			a.job = nil
+			a.Busy = false
+			if a.Status == messages.StatusPrinting || a.Status == messages.StatusPaused {
+				a.Status = a.idleStatus()
+				a.PauseReason = ""
+			}
+			a.Unlock()
+		}()
+		err := a.print(jobCtx, r, j, a.Flow)
+		if j.Err() != nil {
+			log.Errorw("Job failed", "err", j.Err())
 			return
 		}
-	}
-	if result.RequestType == messages.CommandAutoHome {
-		err := a.Print(ctx, strings.NewReader(GCodeAutoHome))
 		if err != nil {
 			terror.Echo(err)
-			return
 		}
+		if j.Cancelled() {
+			// Leave the printer cold rather than sitting at temperature
+			err = a.runScript(ctx, GCodeCancel)
+			if err != nil {
+				terror.Echo(err)
+			}
+		}
+	})
+	return nil
+}
+
+// sendStatus pushes the agent info to the server
+func (a *Agent) sendStatus(ctx context.Context) error {
+	a.Lock()
+	info := &messages.AgentInfo{
+		Busy:         a.Busy,
+		Status:       a.Status,
+		PauseReason:  a.PauseReason,
+		Firmware:     a.Firmware,
+		Prompt:       a.Prompt,
+		Temperatures: a.Temperatures,
+		Error:        a.ErrorReason,
+	}
+	a.Unlock()
+	msg, err := messages.Encode(messages.TypeInfo, messages.InfoAgentStatus, info)
+	if err != nil {
+		return terror.New(err, "")
 	}
-	if result.RequestType == messages.CommandUnlockPrinter {
-		a.Busy = false
+	a.send(msg)
+	return nil
+}
+
+// idleStatus is what the agent reports when no job is running, called with the lock held
+func (a *Agent) idleStatus() messages.AgentStatus {
+	if len(a.LoadedFile) > 0 {
+		return messages.StatusReady
 	}
+	return messages.StatusIdle
+}
+
+// firmware is what discovery found on the printer. Discovery runs again on every reconnect and
+// replaces it rather than changing it, so the copy can be used without the lock.
+func (a *Agent) firmware() *messages.FirmwareInfo {
+	a.Lock()
+	defer a.Unlock()
+	return a.Firmware
+}
+
+// serialPort is the open port and the reader of what comes back on it, nil while disconnected.
+// A reconnect replaces both, so they're always taken together.
+func (a *Agent) serialPort() (serial.Port, *serialReader) {
+	a.Lock()
+	defer a.Unlock()
+	return a.Serial, a.reader
+}
+
+// currentJob is the running job, nil when idle
+func (a *Agent) currentJob() *job {
+	a.Lock()
+	defer a.Unlock()
+	return a.job
 }
 
 // Print the gcode
 func (a *Agent) Print(ctx context.Context, r io.Reader) error {
 
-	return print(ctx, a.Serial, r)
+	return a.print(ctx, r, a.currentJob(), a.Flow)
+}
+
+// runScript sends a short canned script one line at a time. Scripts can run in the middle of
+// a paused job, so they stay unnumbered to leave the job's line numbers alone.
+func (a *Agent) runScript(ctx context.Context, script string) error {
+	return a.print(ctx, strings.NewReader(script), nil, FlowSettings{Mode: FlowPingPong})
 }
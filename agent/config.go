@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ninja-software/terror"
+)
+
+// Config describes the printers run by one agent process, read from a JSON file
+type Config struct {
+	WebsocketHost string `json:"websocket_host"` // Used when server_url isn't given
+	WebsocketPort string `json:"websocket_port"`
+	ServerConfig
+	Printers []*PrinterConfig `json:"printers"`
+}
+
+// PrinterConfig is one printer attached to the agent's machine
+type PrinterConfig struct {
+	Name         string   `json:"name"`          // Shown by the server to tell the printers apart
+	SerialDevice string   `json:"serial_device"` // Port the printer is on, or auto
+	Token        string   `json:"token"`         // Issued by the server for this printer
+	BaudRate     int      `json:"baud_rate"`
+	FlowControl  FlowMode `json:"flow_control"`
+	RXBufferSize int      `json:"rx_buffer_size"`
+}
+
+// Flow is the flow control settings for the printer
+func (p *PrinterConfig) Flow() FlowSettings {
+	return FlowSettings{Mode: p.FlowControl, RXBufferSize: p.RXBufferSize}
+}
+
+// LoadConfig reads the config file, filling in defaults and checking the printers don't clash
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	cfg := &Config{}
+	err = json.Unmarshal(b, cfg)
+	if err != nil {
+		return nil, terror.New(fmt.Errorf("%s: %w", path, err), "")
+	}
+	if cfg.WebsocketHost == "" {
+		cfg.WebsocketHost = "localhost"
+	}
+	if cfg.WebsocketPort == "" {
+		cfg.WebsocketPort = "8080"
+	}
+	if cfg.URL == "" {
+		cfg.URL = ServerURL(cfg.WebsocketHost, cfg.WebsocketPort)
+	}
+	_, err = cfg.WebsocketURL()
+	if err != nil {
+		return nil, terror.New(fmt.Errorf("%s: %w", path, err), "")
+	}
+	if len(cfg.Printers) == 0 {
+		return nil, terror.New(fmt.Errorf("%s: no printers configured", path), "")
+	}
+
+	names := map[string]bool{}
+	devices := map[string]bool{}
+	for i, p := range cfg.Printers {
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("printer-%d", i+1)
+		}
+		if p.SerialDevice == "" {
+			return nil, terror.New(fmt.Errorf("%s: printer %s has no serial_device", path, p.Name), "")
+		}
+		if p.Token == "" {
+			return nil, terror.New(fmt.Errorf("%s: printer %s has no token", path, p.Name), "")
+		}
+		if p.BaudRate == 0 {
+			p.BaudRate = 115200
+		}
+		if p.FlowControl == "" {
+			p.FlowControl = FlowPingPong
+		}
+		_, err = ParseFlowMode(string(p.FlowControl))
+		if err != nil {
+			return nil, terror.New(fmt.Errorf("%s: printer %s: %w", path, p.Name, err), "")
+		}
+		if p.RXBufferSize == 0 {
+			p.RXBufferSize = DefaultRXBufferSize
+		}
+		if names[p.Name] {
+			return nil, terror.New(fmt.Errorf("%s: printer name %s is used twice", path, p.Name), "")
+		}
+		names[p.Name] = true
+		// Probing would race between printers and can't tell them apart, so only one may be found automatically
+		if devices[p.SerialDevice] {
+			return nil, terror.New(fmt.Errorf("%s: serial_device %s is used twice", path, p.SerialDevice), "")
+		}
+		devices[p.SerialDevice] = true
+	}
+	return cfg, nil
+}
@@ -0,0 +1,195 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-3dprint/messages"
+	"os"
+	"time"
+
+	"github.com/ninja-software/terror"
+	"go.bug.st/serial"
+)
+
+// ReconnectMinDelay is the first wait before reopening a serial port or reconnecting to the server
+const ReconnectMinDelay = time.Second
+
+// ReconnectMaxDelay caps the backoff between attempts to reconnect
+const ReconnectMaxDelay = 30 * time.Second
+
+// hotplugPollInterval is how often the device is looked for while waiting to reconnect
+const hotplugPollInterval = time.Second
+
+// ErrDisconnected is returned when the printer's serial port isn't open
+var ErrDisconnected = errors.New("printer is disconnected")
+
+// SerialOpener opens the printer's serial port
+type SerialOpener func(ctx context.Context) (serial.Port, error)
+
+// OpenSerial opens the device at the baud rate, looking for the printer first if the device is auto
+func OpenSerial(device string, baudRate int) SerialOpener {
+	return func(ctx context.Context) (serial.Port, error) {
+		d, baud := device, baudRate
+		if device == AutoDevice {
+			var err error
+			d, baud, err = Detect(ctx, ProbeOrder(baudRate))
+			if err != nil {
+				return nil, err
+			}
+		}
+		log.Infow("Opening serial port", "serial_device", d, "baud_rate", baud)
+		port, err := serial.Open(d, &serial.Mode{BaudRate: baud})
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		return port, nil
+	}
+}
+
+// ProbeOrder is the order baud rates are tried in, the preferred one first
+func ProbeOrder(preferred int) []int {
+	result := []int{preferred}
+	for _, b := range BaudRates {
+		if b != preferred {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// superviseSerial keeps the printer connected, reopening the port with backoff whenever it goes away.
+// It never gives up, the printer being unplugged for a day is normal. The websocket is left alone.
+func (a *Agent) superviseSerial(ctx context.Context) {
+	delay := ReconnectMinDelay
+	for {
+		port, err := a.openSerial(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnw("Could not open serial port", "serial_device", a.SerialDevice, "err", err, "retry_in", delay)
+			err = a.waitToReconnect(ctx, delay)
+			if err != nil {
+				return
+			}
+			delay *= 2
+			if delay > ReconnectMaxDelay {
+				delay = ReconnectMaxDelay
+			}
+			continue
+		}
+		delay = ReconnectMinDelay
+
+		sr := a.connected(ctx, port)
+		select {
+		case <-sr.done:
+			a.disconnected(ctx, port, sr)
+		case <-ctx.Done():
+			port.Close()
+			return
+		}
+	}
+}
+
+// connected starts reading the newly opened port and finds out what's on the other end
+func (a *Agent) connected(ctx context.Context, port serial.Port) *serialReader {
+	sr := a.attach(port)
+
+	err := a.DiscoverFirmware(ctx)
+	if err != nil {
+		// Carry on with the safe defaults, printers without M115 still print
+		log.Warnw("Could not discover firmware", "err", err)
+	}
+	// Temperatures are otherwise only known once the firmware's autoreport comes round
+	_, err = a.query(ctx, "M105")
+	if err != nil {
+		log.Warnw("Could not read temperatures", "err", err)
+	}
+
+	// Opening the port resets the board, anything latched before is gone with it
+	a.Lock()
+	a.ErrorReason = ""
+	a.errorSeverity = SeverityRecoverable
+	a.Status = a.idleStatus()
+	a.Unlock()
+	log.Infow("Printer connected", "serial_device", a.SerialDevice)
+	a.sendStatus(ctx)
+	return sr
+}
+
+// attach makes port the one commands are written to, with a new reader for what comes back on it
+func (a *Agent) attach(port serial.Port) *serialReader {
+	sr := newSerialReader()
+	a.Lock()
+	a.Serial = port
+	a.reader = sr
+	a.Unlock()
+	a.goSafe(func() { a.readSerial(port, sr) })
+	return sr
+}
+
+// disconnected fails the running job and reports the printer as gone until it's back
+func (a *Agent) disconnected(ctx context.Context, port serial.Port, sr *serialReader) {
+	sr.Lock()
+	err := sr.err
+	sr.Unlock()
+	log.Warnw("Printer disconnected", "serial_device", a.SerialDevice, "err", err)
+	port.Close()
+
+	a.Lock()
+	if a.Serial == port {
+		a.Serial = nil
+		a.reader = nil
+	}
+	a.Status = messages.StatusDisconnected
+	a.PauseReason = ""
+	a.Prompt = nil
+	a.Temperatures = map[string]messages.Temperature{}
+	j := a.job
+	a.Unlock()
+	j.Fail(fmt.Errorf("%w: %v", ErrDisconnected, err))
+	a.sendStatus(ctx)
+}
+
+// waitToReconnect sleeps for the backoff delay, cut short when the device shows up again
+func (a *Agent) waitToReconnect(ctx context.Context, delay time.Duration) error {
+	present := a.devicePresent()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	ticker := time.NewTicker(hotplugPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+			now := a.devicePresent()
+			if now && !present {
+				log.Infow("Serial device plugged in", "serial_device", a.SerialDevice)
+				return nil
+			}
+			present = now
+		}
+	}
+}
+
+// devicePresent reports whether the printer's device exists, or any likely port when it's found automatically
+func (a *Agent) devicePresent() bool {
+	if a.SerialDevice != AutoDevice {
+		_, err := os.Stat(a.SerialDevice)
+		return err == nil
+	}
+	ports, err := ListPorts()
+	if err != nil {
+		return false
+	}
+	for _, p := range ports {
+		if p.Candidate() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"go-3dprint/messages"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// fakePrinter is a serial port with a printer on the other end that answers every line it's sent
+type fakePrinter struct {
+	out    *io.PipeReader
+	in     *io.PipeWriter
+	answer string
+}
+
+func newFakePrinter(answer string) *fakePrinter {
+	r, w := io.Pipe()
+	return &fakePrinter{out: r, in: w, answer: answer}
+}
+
+func (p *fakePrinter) Read(b []byte) (int, error) { return p.out.Read(b) }
+
+func (p *fakePrinter) Write(b []byte) (int, error) {
+	_, err := p.in.Write([]byte(p.answer))
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close unplugs the printer, the reader sees the end of the port
+func (p *fakePrinter) Close() error { return p.in.Close() }
+
+func (p *fakePrinter) SetMode(*serial.Mode) error { return nil }
+func (p *fakePrinter) ResetInputBuffer() error    { return nil }
+func (p *fakePrinter) ResetOutputBuffer() error   { return nil }
+func (p *fakePrinter) SetDTR(bool) error          { return nil }
+func (p *fakePrinter) SetRTS(bool) error          { return nil }
+func (p *fakePrinter) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{}, nil
+}
+
+func TestReconnectDuringQuery(t *testing.T) {
+	a := &Agent{Mutex: &sync.Mutex{}, Status: messages.StatusIdle, outbox: newOutbox()}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The port keeps dropping and being reopened while commands are being sent
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			port := newFakePrinter("echo:reconnect\nok\n")
+			sr := a.attach(port)
+			time.Sleep(time.Millisecond)
+			port.Close()
+			<-sr.done
+			a.disconnected(ctx, port, sr)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_, err := a.query(ctx, "M105")
+			if err != nil && !errors.Is(err, ErrDisconnected) && !errors.Is(err, ErrSerialClosed) && !errors.Is(err, io.ErrClosedPipe) {
+				t.Errorf("query %d: %v", i, err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	_, err := a.query(ctx, "M105")
+	if !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("got %v querying an unplugged printer, want %v", err, ErrDisconnected)
+	}
+
+	// Once it's back, commands go to the new port and its answers come back
+	port := newFakePrinter("FIRMWARE_NAME:Marlin 2.1.2\nok\n")
+	a.attach(port)
+	defer port.Close()
+	lines, err := a.query(ctx, "M115")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "FIRMWARE_NAME:Marlin") {
+		t.Errorf("got %q from the reconnected printer", lines)
+	}
+}
@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"go-3dprint/messages"
+	"time"
+
+	"github.com/ninja-software/terror"
+)
+
+// EmergencyStop halts the printer straight away.
+// It writes to the serial port directly instead of going through the job, which may be
+// waiting on the printer, and latches the agent until Reset is called.
+func (a *Agent) EmergencyStop() error {
+	err := a.writeDirect("M112", false)
+	if errors.Is(err, ErrDisconnected) {
+		return err
+	}
+	log.Warnw("Emergency stop")
+	a.Lock()
+	a.Status = messages.StatusHalted
+	j := a.job
+	a.Unlock()
+	j.Stop()
+	if err != nil {
+		return terror.New(err, "")
+	}
+	if !a.firmware().Supports(messages.FirmwareCapEmergencyParser) {
+		// Without the emergency parser M112 sits behind whatever is already queued,
+		// resetting the controller stops it now
+		log.Warnw("Firmware has no emergency parser, resetting controller")
+		return a.pulseDTR()
+	}
+	return nil
+}
+
+// Pause holds the running job before its next line
+func (a *Agent) Pause(reason messages.PauseReason) {
+	a.Lock()
+	if a.PauseReason == messages.PauseReasonFilamentChange {
+		// The firmware reports its own M600 as paused, it is still waiting on the spool
+		reason = a.PauseReason
+	}
+	paused := a.job.Pause()
+	if paused {
+		a.Status = messages.StatusPaused
+		a.PauseReason = reason
+	}
+	a.Unlock()
+	if paused {
+		log.Infow("Job paused", "reason", reason)
+		a.sendStatus(context.Background())
+	}
+}
+
+// Resume carries on with a paused job. If the firmware itself is waiting for someone to
+// press continue, M108 is sent to release it, which only works with the emergency parser.
+func (a *Agent) Resume() error {
+	a.Lock()
+	held := a.firmwareHeld()
+	firmware := a.Firmware
+	j := a.job
+	a.Unlock()
+	if held {
+		if !firmware.Supports(messages.FirmwareCapEmergencyParser) {
+			return ErrResumeOnPrinter
+		}
+		err := a.writeDirect("M108", true)
+		if err != nil {
+			return terror.New(err, "")
+		}
+	}
+	if j.Macro() {
+		return ErrBusy
+	}
+	a.resumed()
+	return nil
+}
+
+// resumed marks the job as running again, used directly when the firmware says it has resumed.
+// A filament change is over too, whether or not the firmware paused the job for it.
+func (a *Agent) resumed() {
+	a.Lock()
+	changed := a.job.Resume() || a.PauseReason == messages.PauseReasonFilamentChange
+	if changed {
+		a.Status = messages.StatusPrinting
+		a.PauseReason = ""
+	}
+	a.Unlock()
+	if changed {
+		log.Infow("Job resumed")
+		a.sendStatus(context.Background())
+	}
+}
+
+// Cancel stops the running job, the cooldown script is run once it has stopped
+func (a *Agent) Cancel() {
+	j := a.currentJob()
+	if j == nil {
+		return
+	}
+	log.Infow("Job cancelled")
+	j.Cancel()
+}
+
+// Reset clears a latched halt or firmware error. A stopped firmware comes back with M999,
+// Marlin can't leave kill() without a reset of the controller, so the board is restarted
+// and the firmware discovered again.
+func (a *Agent) Reset(ctx context.Context) error {
+	a.Lock()
+	status, severity := a.Status, a.errorSeverity
+	a.Unlock()
+	switch {
+	case status == messages.StatusHalted, status == messages.StatusError && severity == SeverityKilled:
+		log.Infow("Resetting printer")
+		err := a.pulseDTR()
+		if err != nil {
+			return terror.New(err, "")
+		}
+		err = a.DiscoverFirmware(ctx)
+		if err != nil {
+			return terror.New(err, "")
+		}
+	case status == messages.StatusError:
+		log.Infow("Restarting firmware with M999")
+		_, err := a.query(ctx, "M999")
+		if err != nil {
+			return terror.New(err, "")
+		}
+	default:
+		return nil
+	}
+	a.Lock()
+	a.Status = a.idleStatus()
+	a.ErrorReason = ""
+	a.errorSeverity = SeverityRecoverable
+	a.Unlock()
+	return a.sendStatus(ctx)
+}
+
+// pulseDTR toggles DTR which resets most Arduino based controllers
+func (a *Agent) pulseDTR() error {
+	a.Lock()
+	port := a.Serial
+	a.Unlock()
+	if port == nil {
+		return ErrDisconnected
+	}
+	err := port.SetDTR(false)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	time.Sleep(100 * time.Millisecond)
+	err = port.SetDTR(true)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
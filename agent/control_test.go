@@ -0,0 +1,18 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"go-3dprint/messages"
+	"sync"
+	"testing"
+)
+
+func TestResetDisconnected(t *testing.T) {
+	// The port went away after the printer halted, there's nothing to pulse DTR on
+	a := &Agent{Mutex: &sync.Mutex{}, Status: messages.StatusHalted}
+	err := a.Reset(context.Background())
+	if !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("got %v, want %v", err, ErrDisconnected)
+	}
+}
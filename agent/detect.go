@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ninja-software/terror"
+	"go.bug.st/serial"
+	"go.bug.st/serial/enumerator"
+)
+
+// AutoDevice is given as the serial device to have the agent find the printer itself
+const AutoDevice = "auto"
+
+// BaudRates are tried in order when looking for a printer, the common Marlin settings
+var BaudRates = []int{250000, 115200, 57600}
+
+// ProbeTimeout is how long a port gets to answer at one baud rate, including the boot after it's opened
+const ProbeTimeout = 6 * time.Second
+
+// probeInterval is how often the probe commands are repeated while waiting for an answer
+const probeInterval = time.Second
+
+// ErrNoPrinter is returned when none of the ports answered like a printer
+var ErrNoPrinter = errors.New("no printer found on any serial port")
+
+// errNoAnswer is a single port not answering at a baud rate
+var errNoAnswer = errors.New("no answer from port")
+
+// printerVendors are USB vendors found on printer boards, by VID
+var printerVendors = map[string]string{
+	"2341": "Arduino",
+	"2A03": "Arduino",
+	"1A86": "QinHeng CH340",
+	"0403": "FTDI",
+	"10C4": "Silicon Labs CP210x",
+	"2C99": "Prusa Research",
+	"1D50": "OpenMoko (Marlin, Smoothieware)",
+	"0483": "STMicroelectronics",
+	"16C0": "Van Ooijen (Teensy, Printrboard)",
+	"27B1": "Ultimaker",
+}
+
+// PortInfo describes a serial port that might have a printer on it
+type PortInfo struct {
+	Name         string
+	USB          bool
+	VID          string
+	PID          string
+	SerialNumber string
+	Product      string
+	Vendor       string // Known printer board vendor, empty if the VID isn't one
+}
+
+// Candidate reports whether the port is worth probing for a printer
+func (p *PortInfo) Candidate() bool {
+	return p.USB
+}
+
+// ListPorts lists the serial ports, the ones most likely to be printers first
+func ListPorts() ([]*PortInfo, error) {
+	details, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	result := []*PortInfo{}
+	for _, d := range details {
+		if d.Name == "" {
+			continue
+		}
+		result = append(result, &PortInfo{
+			Name:         d.Name,
+			USB:          d.IsUSB,
+			VID:          strings.ToUpper(d.VID),
+			PID:          strings.ToUpper(d.PID),
+			SerialNumber: d.SerialNumber,
+			Product:      d.Product,
+			Vendor:       printerVendors[strings.ToUpper(d.VID)],
+		})
+	}
+	rank := func(p *PortInfo) int {
+		switch {
+		case p.Vendor != "":
+			return 0
+		case p.USB:
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return rank(result[i]) < rank(result[j])
+	})
+	return result, nil
+}
+
+// Probe opens the port at the baud rate and sends M110 N0 and M115 until the printer answers.
+// Returns the firmware name, empty if the printer answered without one.
+func Probe(ctx context.Context, device string, baudRate int) (string, error) {
+	port, err := serial.Open(device, &serial.Mode{BaudRate: baudRate})
+	if err != nil {
+		return "", terror.New(err, "")
+	}
+	// Closing the port is the only way to stop the reader below
+	defer port.Close()
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+
+	lines := make(chan string)
+	goSafe(func() {
+		defer close(lines)
+		brdr := bufio.NewReader(port)
+		for {
+			line, err := brdr.ReadString('\n')
+			if err != nil {
+				return
+			}
+			select {
+			case lines <- strings.TrimSpace(line):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, func(err error) {
+		// lines is closed on the way out, so the probe carries on as if the printer went quiet
+		log.Errorw("Probe reader panicked", "serial_device", device, "err", err)
+	})
+
+	// Opening the port resets most boards, give the bootloader time before talking to it
+	timer := time.NewTimer(BootDelay)
+	defer timer.Stop()
+	oks := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return "", errNoAnswer
+		case <-timer.C:
+			_, err := port.Write([]byte("M110 N0\nM115\n"))
+			if err != nil {
+				return "", terror.New(err, "")
+			}
+			timer.Reset(probeInterval)
+		case line, ok := <-lines:
+			if !ok {
+				return "", errNoAnswer
+			}
+			if strings.HasPrefix(line, "FIRMWARE_NAME:") {
+				return ParseFirmwareInfo([]string{line}).Name, nil
+			}
+			if _, isOK := ParseOK(line); isOK {
+				oks++
+			}
+			if oks >= 2 {
+				// Both commands acknowledged, but M115 didn't say what it is
+				return "", nil
+			}
+		}
+	}
+}
+
+// Detect looks for a printer on the candidate ports, trying each baud rate in turn
+func Detect(ctx context.Context, baudRates []int) (string, int, error) {
+	ports, err := ListPorts()
+	if err != nil {
+		return "", 0, err
+	}
+	for _, p := range ports {
+		if !p.Candidate() {
+			continue
+		}
+		for _, baud := range baudRates {
+			log.Infow("Probing serial port", "port", p.Name, "baud_rate", baud)
+			firmware, err := Probe(ctx, p.Name, baud)
+			if err != nil {
+				if ctx.Err() != nil {
+					return "", 0, ctx.Err()
+				}
+				log.Debugw("No printer", "port", p.Name, "baud_rate", baud, "err", err)
+				continue
+			}
+			log.Infow("Found printer", "port", p.Name, "baud_rate", baud, "firmware", firmware)
+			return p.Name, baud, nil
+		}
+	}
+	return "", 0, ErrNoPrinter
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-3dprint/messages"
+	"strings"
+)
+
+// ErrFirmware is wrapped around fatal Error: lines reported by the firmware
+var ErrFirmware = errors.New("firmware error")
+
+// ErrorSeverity says how bad an Error: line from the firmware is
+type ErrorSeverity int
+
+const (
+	// SeverityRecoverable errors are sorted out by a resend or can be ignored
+	SeverityRecoverable ErrorSeverity = iota
+	// SeverityStopped means the firmware has stopped and M999 brings it back
+	SeverityStopped
+	// SeverityKilled means the firmware called kill() and the controller has to be reset
+	SeverityKilled
+)
+
+// killedErrors are fragments of errors Marlin follows with kill()
+var killedErrors = []string{
+	"kill() called",
+	"printer halted",
+	"mintemp",
+	"maxtemp",
+	"thermal runaway",
+	"heating failed",
+	"thermal malfunction",
+}
+
+// stoppedErrors are fragments of errors Marlin recovers from with M999
+var stoppedErrors = []string{
+	"stopped due to errors",
+	"printer stopped",
+}
+
+// ClassifyError decides how bad the text of an Error: line is
+func ClassifyError(text string) ErrorSeverity {
+	lower := strings.ToLower(text)
+	for _, s := range killedErrors {
+		if strings.Contains(lower, s) {
+			return SeverityKilled
+		}
+	}
+	for _, s := range stoppedErrors {
+		if strings.Contains(lower, s) {
+			return SeverityStopped
+		}
+	}
+	return SeverityRecoverable
+}
+
+// handleError deals with an Error: line from the printer
+func (a *Agent) handleError(text string) {
+	severity := ClassifyError(text)
+	if severity == SeverityRecoverable {
+		log.Warnw("Printer error", "message", text)
+		return
+	}
+	log.Errorw("Fatal printer error", "message", text)
+	a.fail(text, severity)
+}
+
+// fail latches the agent in the error state and fails the running job with the firmware's message
+func (a *Agent) fail(reason string, severity ErrorSeverity) {
+	a.Lock()
+	if a.Status == messages.StatusHalted {
+		// An emergency stop makes the firmware complain too, it's already latched
+		a.Unlock()
+		return
+	}
+	if a.Status != messages.StatusError {
+		// Marlin tends to follow the real reason with "Printer halted", keep the first
+		a.ErrorReason = reason
+	}
+	if severity > a.errorSeverity {
+		a.errorSeverity = severity
+	}
+	a.Status = messages.StatusError
+	j := a.job
+	a.Unlock()
+	j.Fail(fmt.Errorf("%w: %s", ErrFirmware, reason))
+	a.sendStatus(context.Background())
+}
+
+// latched reports whether the agent is stuck in a state only Reset clears, called with the lock held
+func (a *Agent) latched() error {
+	switch a.Status {
+	case messages.StatusHalted:
+		return ErrHalted
+	case messages.StatusError:
+		return fmt.Errorf("%w: %s, reset it first", ErrFirmware, a.ErrorReason)
+	case messages.StatusDisconnected:
+		return ErrDisconnected
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package agent
+
+import "testing"
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		text string
+		want ErrorSeverity
+	}{
+		{"Printer halted. kill() called!", SeverityKilled},
+		{"MINTEMP triggered, system stopped! Heater_ID: 0", SeverityKilled},
+		{"MAXTEMP triggered, system stopped! Heater_ID: bed", SeverityKilled},
+		{"Thermal Runaway, system stopped! Heater_ID: 0", SeverityKilled},
+		{"Heating failed, system stopped! Heater_ID: 0", SeverityKilled},
+		{"Printer stopped due to errors. Fix the error and use M999 to restart. (Temperature is reset. Set it after restarting)", SeverityStopped},
+		{"Printer stopped", SeverityStopped},
+		{"Line Number is not Last Line Number+1, Last Line: 9", SeverityRecoverable},
+		{"checksum mismatch, Last Line: 12", SeverityRecoverable},
+		{"No Checksum with line number, Last Line: 3", SeverityRecoverable},
+		{"", SeverityRecoverable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := ClassifyError(tt.text)
+			if got != tt.want {
+				t.Errorf("got severity %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
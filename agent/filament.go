@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"go-3dprint/messages"
+	"strings"
+
+	"github.com/ninja-software/terror"
+)
+
+// ErrResumeOnPrinter is returned when the firmware is waiting for a button press we can't send
+var ErrResumeOnPrinter = errors.New("firmware has no emergency parser, press continue on the printer")
+
+// handleSent watches what goes out to the printer.
+// M600 blocks the firmware until the spool has been changed, so the job shows as paused while it runs.
+func (a *Agent) handleSent(line string) {
+	if isFilamentChange(line) {
+		log.Infow("Filament change")
+		a.Lock()
+		a.Status = messages.StatusPaused
+		a.PauseReason = messages.PauseReasonFilamentChange
+		a.Unlock()
+		a.sendStatus(context.Background())
+	}
+}
+
+// handleAcked watches what the printer has finished with. The ok for an M600 only comes once the
+// spool has been changed, so the filament change is over.
+func (a *Agent) handleAcked(line *sentLine) {
+	if line == nil || !isFilamentChange(line.text) {
+		return
+	}
+	a.Lock()
+	changing := a.PauseReason == messages.PauseReasonFilamentChange
+	a.Unlock()
+	if changing {
+		log.Infow("Filament change finished")
+		a.resumed()
+	}
+}
+
+// isFilamentChange reports whether the gcode line is an M600, numbered or not
+func isFilamentChange(line string) bool {
+	return commandCode(line) == "M600"
+}
+
+// firmwareHeld reports whether the firmware has paused itself and is waiting for the user, called with the lock held
+func (a *Agent) firmwareHeld() bool {
+	return a.Status == messages.StatusPaused &&
+		(a.PauseReason == messages.PauseReasonFilamentRunout || a.PauseReason == messages.PauseReasonFilamentChange)
+}
+
+// runMacro sends a canned script. With nothing running it becomes a job of its own,
+// with a job paused by the host it uses the serial port until the job is resumed.
+func (a *Agent) runMacro(ctx context.Context, script string) error {
+	a.Lock()
+	err := a.latched()
+	busy, held, j := a.Busy, a.firmwareHeld(), a.job
+	a.Unlock()
+	if err != nil {
+		return err
+	}
+	if !busy {
+		return a.runJob(ctx, strings.NewReader(script))
+	}
+	if held {
+		// The firmware won't take anything else until it's released with resume
+		return ErrBusy
+	}
+	if !j.StartMacro() {
+		return ErrBusy
+	}
+	a.goSafe(func() {
+		defer j.EndMacro()
+		err := a.runScript(ctx, script)
+		if err != nil {
+			terror.Echo(err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"go-3dprint/messages"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ninja-software/terror"
+)
+
+// BootDelay is how long to wait for the printer to come out of reset after the port is opened
+const BootDelay = 2 * time.Second
+
+// TemperatureReportInterval is how often the firmware pushes temperatures when it supports M155
+const TemperatureReportInterval = 2 * time.Second
+
+// m115Key matches the KEY: markers on the FIRMWARE_NAME line, values may contain spaces
+var m115Key = regexp.MustCompile(`(?:^|\s)([A-Z_]+):`)
+
+// ParseFirmwareInfo parses the lines returned by M115
+func ParseFirmwareInfo(lines []string) *messages.FirmwareInfo {
+	info := &messages.FirmwareInfo{Capabilities: map[string]bool{}}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Cap:") {
+			parts := strings.SplitN(strings.TrimPrefix(line, "Cap:"), ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			info.Capabilities[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1]) == "1"
+			continue
+		}
+		if !strings.HasPrefix(line, "FIRMWARE_NAME:") {
+			continue
+		}
+		for key, value := range splitM115(line) {
+			switch key {
+			case "FIRMWARE_NAME":
+				info.Name = value
+			case "SOURCE_CODE_URL":
+				info.SourceCodeURL = value
+			case "PROTOCOL_VERSION":
+				info.ProtocolVersion = value
+			case "MACHINE_TYPE":
+				info.MachineType = value
+			case "EXTRUDER_COUNT":
+				info.ExtruderCount, _ = strconv.Atoi(value)
+			case "UUID":
+				info.UUID = value
+			}
+		}
+	}
+	return info
+}
+
+// splitM115 turns "FIRMWARE_NAME:Marlin 2.0 (Github) MACHINE_TYPE:Ender-3" into a map
+func splitM115(line string) map[string]string {
+	result := map[string]string{}
+	idx := m115Key.FindAllStringSubmatchIndex(line, -1)
+	for i, m := range idx {
+		end := len(line)
+		if i+1 < len(idx) {
+			end = idx[i+1][0]
+		}
+		result[line[m[2]:m[3]]] = strings.TrimSpace(line[m[1]:end])
+	}
+	return result
+}
+
+// DiscoverFirmware asks the printer what it is with M115 and adapts the agent to it
+func (a *Agent) DiscoverFirmware(ctx context.Context) error {
+	time.Sleep(BootDelay)
+	lines, err := a.query(ctx, "M115")
+	if err != nil {
+		return terror.New(err, "")
+	}
+	firmware := ParseFirmwareInfo(lines)
+	a.Lock()
+	a.Firmware = firmware
+	a.Unlock()
+	log.Infow("Discovered firmware",
+		"name", firmware.Name,
+		"machine_type", firmware.MachineType,
+		"extruder_count", firmware.ExtruderCount,
+		"autoreport_temp", firmware.Supports(messages.FirmwareCapAutoreportTemp),
+		"emergency_parser", firmware.Supports(messages.FirmwareCapEmergencyParser),
+		"host_action_commands", firmware.Supports(messages.FirmwareCapHostActionCommands),
+	)
+
+	if firmware.Supports(messages.FirmwareCapPromptSupport) {
+		// Tell the firmware a host is around to answer //action:prompt_ questions
+		_, err = a.query(ctx, "M876 P1")
+		if err != nil {
+			return terror.New(err, "")
+		}
+	}
+	if firmware.Supports(messages.FirmwareCapAutoreportTemp) {
+		_, err = a.query(ctx, fmt.Sprintf("M155 S%d", int(TemperatureReportInterval.Seconds())))
+		if err != nil {
+			return terror.New(err, "")
+		}
+	}
+	return nil
+}
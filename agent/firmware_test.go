@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"go-3dprint/messages"
+	"reflect"
+	"testing"
+)
+
+func TestParseFirmwareInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  messages.FirmwareInfo
+	}{
+		{
+			"marlin",
+			[]string{
+				"FIRMWARE_NAME:Marlin 2.1.2 (Github) SOURCE_CODE_URL:github.com/MarlinFirmware/Marlin PROTOCOL_VERSION:1.0 MACHINE_TYPE:Ender-3 V2 EXTRUDER_COUNT:1 UUID:cede2a2f-41a2-4748-9b12-c55c62f367ff",
+				"Cap:SERIAL_XON_XOFF:0",
+				"Cap:AUTOREPORT_TEMP:1",
+				"ok",
+			},
+			messages.FirmwareInfo{
+				Name:            "Marlin 2.1.2 (Github)",
+				SourceCodeURL:   "github.com/MarlinFirmware/Marlin",
+				ProtocolVersion: "1.0",
+				MachineType:     "Ender-3 V2",
+				ExtruderCount:   1,
+				UUID:            "cede2a2f-41a2-4748-9b12-c55c62f367ff",
+				Capabilities:    map[string]bool{"SERIAL_XON_XOFF": false, "AUTOREPORT_TEMP": true},
+			},
+		},
+		{
+			"name between echo lines",
+			[]string{"echo:busy: processing", "  FIRMWARE_NAME:Prusa-Firmware 3.13.0  ", "ok"},
+			messages.FirmwareInfo{Name: "Prusa-Firmware 3.13.0", Capabilities: map[string]bool{}},
+		},
+		{
+			"extruder count that isn't a number",
+			[]string{"FIRMWARE_NAME:Klipper EXTRUDER_COUNT:lots"},
+			messages.FirmwareInfo{Name: "Klipper", Capabilities: map[string]bool{}},
+		},
+		{
+			"capability without a value",
+			[]string{"Cap:NO_VALUE"},
+			messages.FirmwareInfo{Capabilities: map[string]bool{}},
+		},
+		{"no answer", nil, messages.FirmwareInfo{Capabilities: map[string]bool{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseFirmwareInfo(tt.lines)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
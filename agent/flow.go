@@ -0,0 +1,208 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FlowMode selects how many commands are kept in flight
+type FlowMode string
+
+// FlowPingPong sends one line and waits for its ok, safe on every firmware
+const FlowPingPong FlowMode = "ping-pong"
+
+// FlowAdvanced keeps several lines in flight, using Marlin's ADVANCED_OK reports when available
+// and counting bytes against the firmware's receive buffer otherwise
+const FlowAdvanced FlowMode = "advanced"
+
+// DefaultRXBufferSize is Marlin's default RX_BUFFER_SIZE
+const DefaultRXBufferSize = 128
+
+// DefaultCommandBufferSize is Marlin's default BUFSIZE, the number of queued commands
+const DefaultCommandBufferSize = 4
+
+// FlowSettings configures the flow control used when streaming gcode
+type FlowSettings struct {
+	Mode         FlowMode
+	RXBufferSize int // Bytes the firmware can buffer before it starts dropping them
+}
+
+// ParseFlowMode checks the flow mode given on the command line
+func ParseFlowMode(s string) (FlowMode, error) {
+	switch FlowMode(s) {
+	case FlowPingPong, FlowAdvanced:
+		return FlowMode(s), nil
+	}
+	return "", fmt.Errorf("unknown flow control mode %q, use %s or %s", s, FlowPingPong, FlowAdvanced)
+}
+
+// OKResponse is an ok from the printer, with the ADVANCED_OK fields if the firmware sent them.
+// Fields the firmware didn't send are -1.
+type OKResponse struct {
+	Line    int // N, last line number processed
+	Planner int // P, free slots in the planner
+	Buffer  int // B, free slots in the command buffer
+}
+
+// ParseOK parses "ok", "ok N12 P15 B3" and "ok T:210.0 /210.0" style lines
+func ParseOK(line string) (*OKResponse, bool) {
+	line = strings.TrimSpace(line)
+	if line != "ok" && !strings.HasPrefix(line, "ok ") {
+		return nil, false
+	}
+	ok := &OKResponse{Line: -1, Planner: -1, Buffer: -1}
+	for _, field := range strings.Fields(line)[1:] {
+		if len(field) < 2 {
+			continue
+		}
+		v, err := strconv.Atoi(field[1:])
+		if err != nil {
+			continue
+		}
+		switch field[0] {
+		case 'N':
+			ok.Line = v
+		case 'P':
+			ok.Planner = v
+		case 'B':
+			ok.Buffer = v
+		}
+	}
+	return ok, true
+}
+
+// unnumbered is the line number of a line sent without one
+const unnumbered = -1
+
+// sentLine is a line written to the printer that hasn't been acknowledged yet
+type sentLine struct {
+	text    string
+	line    int           // Line number, unnumbered if it was sent without one
+	timeout time.Duration // How long it may take, 0 for no limit
+}
+
+// window tracks the lines sent to the printer that haven't been acknowledged yet
+type window struct {
+	settings FlowSettings
+	inFlight []*sentLine // Unacknowledged lines, oldest first
+	bytes    int         // Total length of inFlight
+	free     int         // Command buffer slots free, from the last B in an ok, -1 until one is seen
+	rejected int         // oks still to come for lines the firmware threw away, which free nothing
+}
+
+func newWindow(settings FlowSettings) *window {
+	if settings.RXBufferSize <= 0 {
+		settings.RXBufferSize = DefaultRXBufferSize
+	}
+	return &window{settings: settings, free: -1}
+}
+
+// CanSend reports whether a line of n bytes can go out without overrunning the firmware
+func (w *window) CanSend(n int) bool {
+	if len(w.inFlight) == 0 {
+		return true
+	}
+	if w.settings.Mode != FlowAdvanced {
+		return false
+	}
+	if w.bytes+n > w.settings.RXBufferSize {
+		return false
+	}
+	if w.free >= 0 {
+		return w.free > 0
+	}
+	return len(w.inFlight) < DefaultCommandBufferSize
+}
+
+// Sent records a line going out
+func (w *window) Sent(out string, line int) {
+	w.inFlight = append(w.inFlight, &sentLine{text: out, line: line, timeout: commandTimeout(out)})
+	w.bytes += len(out)
+	if w.free > 0 {
+		w.free--
+	}
+}
+
+// Ack records an ok, freeing the oldest line and returning it, nil if the ok freed nothing
+func (w *window) Ack(ok *OKResponse) *sentLine {
+	if ok.Buffer >= 0 {
+		w.free = ok.Buffer
+	}
+	if w.rejected > 0 {
+		w.rejected--
+		return nil
+	}
+	if len(w.inFlight) == 0 {
+		return nil
+	}
+	acked := w.inFlight[0]
+	w.bytes -= len(acked.text)
+	w.inFlight = w.inFlight[1:]
+	return acked
+}
+
+// AckAll frees every line in flight, returning them oldest first. Used once the printer has
+// answered something sent after them, the oks it owed for them were lost on the way.
+func (w *window) AckAll() []*sentLine {
+	acked := w.inFlight
+	w.inFlight = nil
+	w.bytes = 0
+	w.rejected = 0
+	return acked
+}
+
+// Rejected records the firmware throwing a line away. It still sends an ok for it, straight after
+// asking for the resend, and that ok mustn't free a line that's still in the firmware's buffer.
+func (w *window) Rejected() {
+	w.rejected++
+}
+
+// Rewind forgets the lines from line on, which the firmware has thrown away and will get again.
+// Unnumbered lines count as coming before every numbered one, rewinding to unnumbered forgets everything.
+func (w *window) Rewind(line int) {
+	kept := []*sentLine{}
+	for _, l := range w.inFlight {
+		if l.line < line {
+			kept = append(kept, l)
+			continue
+		}
+		w.bytes -= len(l.text)
+	}
+	w.inFlight = kept
+}
+
+// Timeout is how long to wait for the next ok, the longest of the lines in flight, 0 for no limit
+func (w *window) Timeout() time.Duration {
+	longest := CommandTimeout
+	for _, l := range w.inFlight {
+		if l.timeout == 0 {
+			return 0
+		}
+		if l.timeout > longest {
+			longest = l.timeout
+		}
+	}
+	return longest
+}
+
+// Empty reports whether every line has been acknowledged
+func (w *window) Empty() bool {
+	return len(w.inFlight) == 0
+}
+
+// checksum is the XOR of every byte, as Marlin expects after the *
+func checksum(s string) int {
+	c := 0
+	for i := 0; i < len(s); i++ {
+		c ^= int(s[i])
+	}
+	return c
+}
+
+// numberLine adds the line number and checksum so the firmware can spot corrupted lines
+func numberLine(n int, cmd string) string {
+	s := fmt.Sprintf("N%d %s", n, cmd)
+	return fmt.Sprintf("%s*%d\n", s, checksum(s))
+}
@@ -0,0 +1,93 @@
+package agent
+
+import "testing"
+
+func TestWindowResend(t *testing.T) {
+	w := newWindow(FlowSettings{Mode: FlowAdvanced, RXBufferSize: 64})
+	ok := &OKResponse{Line: -1, Planner: -1, Buffer: -1}
+	for n := 1; n <= 3; n++ {
+		w.Sent(numberLine(n, "G1 X1"), n)
+	}
+
+	// Line 2 arrives corrupted, the firmware throws away 2 and 3 and asks for 2
+	w.Rejected()
+	w.Rewind(2)
+	if len(w.inFlight) != 1 || w.inFlight[0].line != 1 {
+		t.Fatalf("kept %d lines in flight, want line 1", len(w.inFlight))
+	}
+	if want := len(numberLine(1, "G1 X1")); w.bytes != want {
+		t.Errorf("window holds %d bytes, want %d", w.bytes, want)
+	}
+	w.Sent(numberLine(2, "G1 X1"), 2)
+
+	// The ok straight after the resend request is for the rejected line
+	if acked := w.Ack(ok); acked != nil {
+		t.Fatalf("ok for the rejected line freed line %d", acked.line)
+	}
+	if acked := w.Ack(ok); acked == nil || acked.line != 1 {
+		t.Fatalf("got %v, want line 1 acknowledged", acked)
+	}
+	if acked := w.Ack(ok); acked == nil || acked.line != 2 {
+		t.Fatalf("got %v, want the resent line 2 acknowledged", acked)
+	}
+	if !w.Empty() || w.bytes != 0 {
+		t.Errorf("window not empty, %d lines and %d bytes in flight", len(w.inFlight), w.bytes)
+	}
+}
+
+func TestWindowRewindUnnumbered(t *testing.T) {
+	w := newWindow(FlowSettings{Mode: FlowAdvanced})
+	w.Sent("M110 N0\n", unnumbered)
+	w.Sent(numberLine(1, "G28"), 1)
+
+	w.Rewind(1)
+	if len(w.inFlight) != 1 || w.inFlight[0].line != unnumbered {
+		t.Fatalf("rewinding to line 1 should keep the M110 ahead of it")
+	}
+	w.Rewind(unnumbered)
+	if !w.Empty() || w.bytes != 0 {
+		t.Errorf("rewinding to unnumbered should forget everything")
+	}
+}
+
+func TestWindowAckAll(t *testing.T) {
+	w := newWindow(FlowSettings{Mode: FlowAdvanced})
+	w.Sent(numberLine(1, "G1 X1"), 1)
+	w.Sent(numberLine(2, "G1 X2"), 2)
+	w.Rejected()
+	acked := w.AckAll()
+	if len(acked) != 2 || acked[0].line != 1 || acked[1].line != 2 {
+		t.Fatalf("got %d lines acknowledged, want lines 1 and 2", len(acked))
+	}
+	if !w.Empty() || w.bytes != 0 || w.rejected != 0 {
+		t.Error("window not empty after acknowledging everything")
+	}
+}
+
+func TestParseOK(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   OKResponse
+		wantOK bool
+	}{
+		{"ok", OKResponse{Line: -1, Planner: -1, Buffer: -1}, true},
+		{"ok N12 P15 B3", OKResponse{Line: 12, Planner: 15, Buffer: 3}, true},
+		{"ok P0 B0\r", OKResponse{Line: -1, Planner: 0, Buffer: 0}, true},
+		{"ok T:210.0 /210.0 B:60.0 /60.0", OKResponse{Line: -1, Planner: -1, Buffer: -1}, true},
+		{"ok Nx P B", OKResponse{Line: -1, Planner: -1, Buffer: -1}, true},
+		{"okay", OKResponse{}, false},
+		{"echo:ok", OKResponse{}, false},
+		{"", OKResponse{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := ParseOK(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if ok && *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,20 @@ G90 ; use absolute coordinatsces
 M83 ; extruder relative mode
 G28 ; home all`
 
+// GCodeCancel is sent after a job is cancelled to leave the printer safe
+const GCodeCancel = `M104 S0 ; turn off extruder
+M140 S0 ; turn off bed
+M107 ; disable fan
+M84 ; disable motors`
+
+// GCodeLoadFilament feeds filament through to the nozzle, needs FILAMENT_LOAD_UNLOAD_GCODES
+const GCodeLoadFilament = `M83 ; extruder relative mode
+M701 ; load filament`
+
+// GCodeUnloadFilament pulls filament out of the extruder, needs FILAMENT_LOAD_UNLOAD_GCODES
+const GCodeUnloadFilament = `M83 ; extruder relative mode
+M702 ; unload filament`
+
 // GCodeLevelBedTest level bed command
 const GCodeLevelBedTest = `; generated by PrusaSlicer 2.3.0-alpha1+win64 on 2020-11-25 at 14:17:30 UTC
 
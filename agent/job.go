@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"context"
+	"sync"
+)
+
+// job is the gcode currently being streamed to the printer
+type job struct {
+	cancel    context.CancelFunc
+	cancelled bool // Cancelled by someone, as opposed to halted or finished
+	paused    bool
+	macro     bool  // A macro is using the serial port while the job is paused
+	err       error // Why the job failed
+	resume    chan struct{}
+	*sync.Mutex
+}
+
+func newJob(cancel context.CancelFunc) *job {
+	return &job{
+		cancel: cancel,
+		resume: make(chan struct{}),
+		Mutex:  &sync.Mutex{},
+	}
+}
+
+// Pause stops the job before the next line is sent, returns false if there's nothing to pause
+func (j *job) Pause() bool {
+	if j == nil {
+		return false
+	}
+	j.Lock()
+	defer j.Unlock()
+	if j.paused {
+		return false
+	}
+	j.paused = true
+	return true
+}
+
+// Resume carries on sending lines, returns false if the job wasn't paused
+func (j *job) Resume() bool {
+	if j == nil {
+		return false
+	}
+	j.Lock()
+	defer j.Unlock()
+	if !j.paused || j.macro {
+		return false
+	}
+	j.paused = false
+	close(j.resume)
+	j.resume = make(chan struct{})
+	return true
+}
+
+// Cancel stops the job for good
+func (j *job) Cancel() {
+	if j == nil {
+		return
+	}
+	j.Lock()
+	j.cancelled = true
+	j.Unlock()
+	j.cancel()
+}
+
+// Fail stops the job, recording why
+func (j *job) Fail(err error) {
+	if j == nil {
+		return
+	}
+	j.Lock()
+	if j.err == nil {
+		j.err = err
+	}
+	j.Unlock()
+	j.cancel()
+}
+
+// Err is why the job failed, nil if it hasn't
+func (j *job) Err() error {
+	if j == nil {
+		return nil
+	}
+	j.Lock()
+	defer j.Unlock()
+	return j.err
+}
+
+// Stop ends the job without treating it as cancelled, used when the printer is halted
+func (j *job) Stop() {
+	if j == nil {
+		return
+	}
+	j.cancel()
+}
+
+// Cancelled reports whether Cancel was called
+func (j *job) Cancelled() bool {
+	if j == nil {
+		return false
+	}
+	j.Lock()
+	defer j.Unlock()
+	return j.cancelled
+}
+
+// Paused reports whether the job is paused
+func (j *job) Paused() bool {
+	if j == nil {
+		return false
+	}
+	j.Lock()
+	defer j.Unlock()
+	return j.paused
+}
+
+// StartMacro claims the serial port while the job is paused, returns false if it can't be had
+func (j *job) StartMacro() bool {
+	if j == nil {
+		return false
+	}
+	j.Lock()
+	defer j.Unlock()
+	if !j.paused || j.macro {
+		return false
+	}
+	j.macro = true
+	return true
+}
+
+// EndMacro hands the serial port back to the job
+func (j *job) EndMacro() {
+	if j == nil {
+		return
+	}
+	j.Lock()
+	j.macro = false
+	j.Unlock()
+}
+
+// Macro reports whether a macro is running
+func (j *job) Macro() bool {
+	if j == nil {
+		return false
+	}
+	j.Lock()
+	defer j.Unlock()
+	return j.macro
+}
+
+// wait blocks while the job is paused
+func (j *job) wait(ctx context.Context) error {
+	if j == nil {
+		return ctx.Err()
+	}
+	for {
+		j.Lock()
+		paused, resume := j.paused, j.resume
+		j.Unlock()
+		if !paused {
+			return nil
+		}
+		select {
+		case <-resume:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"context"
+	"go-3dprint/messages"
+	"sync"
+	"time"
+
+	"github.com/ninja-software/terror"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// OutboxSize is how many messages are kept for the server while it can't be reached
+const OutboxSize = 256
+
+// PingInterval is how often the server is pinged to check the connection is still alive
+const PingInterval = 15 * time.Second
+
+// PingTimeout is how long the server has to answer a ping before the connection is dropped
+const PingTimeout = 10 * time.Second
+
+// writeTimeout is how long a single message has to be written to the server
+const writeTimeout = 5 * time.Second
+
+// outbox holds messages for the server until they've been written, so nothing is lost across reconnects
+type outbox struct {
+	queue []*messages.AsyncCommand
+	ready chan struct{} // Signalled when a message is queued
+	*sync.Mutex
+}
+
+func newOutbox() *outbox {
+	return &outbox{
+		ready: make(chan struct{}, 1),
+		Mutex: &sync.Mutex{},
+	}
+}
+
+// Push queues a message. A status update replaces an unsent one queued just before it,
+// only the latest matters. When the outbox is full the oldest message is dropped.
+func (o *outbox) Push(msg *messages.AsyncCommand) {
+	o.Lock()
+	last := len(o.queue) - 1
+	switch {
+	case last > 0 && msg.RequestType == messages.InfoAgentStatus && o.queue[last].RequestType == messages.InfoAgentStatus:
+		// The head may be being written right now, so it's never replaced
+		o.queue[last] = msg
+	case len(o.queue) >= OutboxSize:
+		log.Warnw("Outbox full, dropping oldest message", "request_type", o.queue[0].RequestType)
+		o.queue = append(o.queue[1:], msg)
+	default:
+		o.queue = append(o.queue, msg)
+	}
+	o.Unlock()
+	select {
+	case o.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Peek returns the oldest message without removing it, nil if there's nothing to send
+func (o *outbox) Peek() *messages.AsyncCommand {
+	o.Lock()
+	defer o.Unlock()
+	if len(o.queue) == 0 {
+		return nil
+	}
+	return o.queue[0]
+}
+
+// Pop removes the oldest message once it's been written
+func (o *outbox) Pop() {
+	o.Lock()
+	defer o.Unlock()
+	if len(o.queue) > 0 {
+		o.queue = o.queue[1:]
+	}
+}
+
+// send queues a message for the server, it goes out as soon as there's a connection
+func (a *Agent) send(msg *messages.AsyncCommand) {
+	a.outbox.Push(msg)
+}
+
+// writeLoop delivers the outbox to the server. A message is only removed once written,
+// so one that fails goes out again on the next connection.
+func (a *Agent) writeLoop(ctx context.Context, conn *websocket.Conn) error {
+	for {
+		msg := a.outbox.Peek()
+		if msg == nil {
+			select {
+			case <-a.outbox.ready:
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		wctx, cancel := context.WithTimeout(ctx, writeTimeout)
+		err := wsjson.Write(wctx, conn, msg)
+		cancel()
+		if err != nil {
+			return err
+		}
+		a.outbox.Pop()
+	}
+}
+
+// keepalive pings the server, a connection that has silently died otherwise goes unnoticed until the next write
+func (a *Agent) keepalive(ctx context.Context, conn *websocket.Conn) error {
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			pctx, cancel := context.WithTimeout(ctx, PingTimeout)
+			err := conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reportStatus sends the agent info every second for as long as the connection lasts
+func (a *Agent) reportStatus(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := a.sendStatus(ctx)
+			if err != nil {
+				terror.Echo(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"go-3dprint/messages"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ResponseKind classifies a line sent by the printer
+type ResponseKind int
+
+const (
+	// RespKindUnknown is anything unsolicited we don't recognise, like the M115 report
+	RespKindUnknown ResponseKind = iota
+	// RespKindOK acknowledges a command, possibly with trailing data
+	RespKindOK
+	// RespKindBusy is the firmware saying a long command is still running
+	RespKindBusy
+	// RespKindError is an Error: line
+	RespKindError
+	// RespKindResend asks for a line to be sent again
+	RespKindResend
+	// RespKindEcho is an echo: line
+	RespKindEcho
+	// RespKindTemperature is an unsolicited temperature report, from M155
+	RespKindTemperature
+	// RespKindAction is a //action: host action command
+	RespKindAction
+	// RespKindStart is printed by the firmware when it boots
+	RespKindStart
+	// RespKindProbe is the ok for the M105 sent to a quiet printer, never returned by ParseResponse
+	RespKindProbe
+)
+
+func (k ResponseKind) String() string {
+	switch k {
+	case RespKindOK:
+		return "ok"
+	case RespKindBusy:
+		return "busy"
+	case RespKindError:
+		return "error"
+	case RespKindResend:
+		return "resend"
+	case RespKindEcho:
+		return "echo"
+	case RespKindTemperature:
+		return "temperature"
+	case RespKindAction:
+		return "action"
+	case RespKindStart:
+		return "start"
+	case RespKindProbe:
+		return "probe"
+	}
+	return "unknown"
+}
+
+// Response is a classified line from the printer
+type Response struct {
+	Kind         ResponseKind
+	Raw          string                          // Line without the trailing newline
+	Text         string                          // Line without its prefix, e.g. the message after Error:
+	OK           *OKResponse                     // Set for RespKindOK
+	Line         int                             // Line to resend, for RespKindResend
+	Action       *HostAction                     // Set for RespKindAction
+	Temperatures map[string]messages.Temperature // Set for temperature reports, including ok T:...
+}
+
+// temperatureRe matches T:210.0 /210.0, T0:.. /.., B:.. /.. and C:.. /.. in temperature reports
+var temperatureRe = regexp.MustCompile(`\b([TBC]\d*):\s*(-?[\d.]+)\s*/\s*(-?[\d.]+)`)
+
+// ParseTemperatures pulls the heater readings out of a report, nil if there aren't any
+func ParseTemperatures(line string) map[string]messages.Temperature {
+	matches := temperatureRe.FindAllStringSubmatch(line, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	result := map[string]messages.Temperature{}
+	for _, m := range matches {
+		actual, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		target, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			continue
+		}
+		result[m[1]] = messages.Temperature{Actual: actual, Target: target}
+	}
+	return result
+}
+
+// ParseResponse classifies a line from the printer
+func ParseResponse(line string) *Response {
+	raw := strings.TrimRight(line, "\r\n")
+	trimmed := strings.TrimSpace(raw)
+	resp := &Response{Kind: RespKindUnknown, Raw: raw, Text: trimmed}
+
+	if ok, isOK := ParseOK(trimmed); isOK {
+		resp.Kind = RespKindOK
+		resp.OK = ok
+		resp.Text = strings.TrimSpace(strings.TrimPrefix(trimmed, "ok"))
+		resp.Temperatures = ParseTemperatures(resp.Text)
+		return resp
+	}
+	if action, isAction := ParseHostAction(trimmed); isAction {
+		resp.Kind = RespKindAction
+		resp.Action = action
+		resp.Text = strings.TrimPrefix(trimmed, HostActionPrefix)
+		return resp
+	}
+
+	lower := strings.ToLower(trimmed)
+	switch {
+	case trimmed == "start":
+		resp.Kind = RespKindStart
+	case strings.HasPrefix(lower, "echo:busy:"), strings.HasPrefix(lower, "busy:"):
+		resp.Kind = RespKindBusy
+		resp.Text = strings.TrimSpace(trimmed[strings.Index(lower, "busy:")+len("busy:"):])
+	case strings.HasPrefix(lower, "error:"):
+		resp.Kind = RespKindError
+		resp.Text = strings.TrimSpace(trimmed[len("error:"):])
+	case strings.HasPrefix(lower, "resend:"), strings.HasPrefix(lower, "rs:"):
+		resp.Kind = RespKindResend
+		resp.Text = strings.TrimSpace(trimmed[strings.Index(trimmed, ":")+1:])
+		resp.Line, _ = strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(resp.Text, "N"), " "))
+	case strings.HasPrefix(lower, "echo:"):
+		resp.Kind = RespKindEcho
+		resp.Text = strings.TrimSpace(trimmed[len("echo:"):])
+	default:
+		if temps := ParseTemperatures(trimmed); temps != nil && strings.HasPrefix(trimmed, "T") {
+			resp.Kind = RespKindTemperature
+			resp.Temperatures = temps
+		}
+	}
+	return resp
+}
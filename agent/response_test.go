@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"go-3dprint/messages"
+	"reflect"
+	"testing"
+)
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantKind  ResponseKind
+		wantText  string
+		wantLine  int
+		wantTemps map[string]messages.Temperature
+	}{
+		{line: "ok\r\n", wantKind: RespKindOK, wantText: ""},
+		{
+			line:      "ok T:210.0 /210.0 B:60.0 /60.0 @:127 B@:0",
+			wantKind:  RespKindOK,
+			wantText:  "T:210.0 /210.0 B:60.0 /60.0 @:127 B@:0",
+			wantTemps: map[string]messages.Temperature{"T": {Actual: 210, Target: 210}, "B": {Actual: 60, Target: 60}},
+		},
+		{
+			line:      " T:20.1 /0.0 B:19.8 /0.0 T0:20.1 /0.0 T1:-15.0 /0.0",
+			wantKind:  RespKindTemperature,
+			wantText:  "T:20.1 /0.0 B:19.8 /0.0 T0:20.1 /0.0 T1:-15.0 /0.0",
+			wantTemps: map[string]messages.Temperature{"T": {Actual: 20.1}, "B": {Actual: 19.8}, "T0": {Actual: 20.1}, "T1": {Actual: -15}},
+		},
+		{line: "echo:busy: processing", wantKind: RespKindBusy, wantText: "processing"},
+		{line: "busy: paused for user", wantKind: RespKindBusy, wantText: "paused for user"},
+		{line: "Error:Printer halted. kill() called!", wantKind: RespKindError, wantText: "Printer halted. kill() called!"},
+		{line: "Resend: 12", wantKind: RespKindResend, wantText: "12", wantLine: 12},
+		{line: "Resend:N7", wantKind: RespKindResend, wantText: "N7", wantLine: 7},
+		{line: "rs:3", wantKind: RespKindResend, wantText: "3", wantLine: 3},
+		{line: "echo:Unknown command: \"G999\"", wantKind: RespKindEcho, wantText: "Unknown command: \"G999\""},
+		{line: "//action:pause", wantKind: RespKindAction, wantText: "pause"},
+		{line: "start", wantKind: RespKindStart, wantText: "start"},
+		{line: "FIRMWARE_NAME:Marlin 2.1.2", wantKind: RespKindUnknown, wantText: "FIRMWARE_NAME:Marlin 2.1.2"},
+		{line: "X:0.00 Y:0.00 Z:0.00 E:0.00 Count X:0 Y:0 Z:0", wantKind: RespKindUnknown, wantText: "X:0.00 Y:0.00 Z:0.00 E:0.00 Count X:0 Y:0 Z:0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			resp := ParseResponse(tt.line)
+			if resp.Kind != tt.wantKind {
+				t.Fatalf("got kind %s, want %s", resp.Kind, tt.wantKind)
+			}
+			if resp.Text != tt.wantText {
+				t.Errorf("got text %q, want %q", resp.Text, tt.wantText)
+			}
+			if resp.Line != tt.wantLine {
+				t.Errorf("got line %d, want %d", resp.Line, tt.wantLine)
+			}
+			if !reflect.DeepEqual(resp.Temperatures, tt.wantTemps) {
+				t.Errorf("got temperatures %v, want %v", resp.Temperatures, tt.wantTemps)
+			}
+			if resp.Kind == RespKindOK && resp.OK == nil {
+				t.Error("ok without its OKResponse")
+			}
+			if resp.Kind == RespKindAction && resp.Action == nil {
+				t.Error("action without its HostAction")
+			}
+		})
+	}
+}
@@ -3,14 +3,16 @@ package agent
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"go-3dprint/messages"
 	"io"
 	"strings"
+	"sync"
 	"unicode"
 
-	"go.bug.st/serial"
-
 	"github.com/ninja-software/terror"
+	"go.bug.st/serial"
 
 	"github.com/256dpi/gcode"
 )
@@ -26,66 +28,370 @@ G90 ; use absolute coordinatsces
 M83 ; extruder relative mode
 G28 ; home all`
 
-// RespBusy returns from the printer if its busy
-const RespBusy = "echo:busy: processing\n"
+// resendHistory is how many numbered lines are kept around in case the firmware asks for them again
+const resendHistory = 256
 
-// RespOK returns from the printer if its ready for the next command
-const RespOK = "ok\n"
+// ErrSerialClosed is returned when the serial port stops giving us lines
+var ErrSerialClosed = errors.New("serial port closed")
 
-func print(ctx context.Context, s serial.Port, f io.Reader) error {
-	fmt.Println("Start print")
+// serialReader is the one place the serial port is read from
+type serialReader struct {
+	responses  chan *Response // oks, resend requests, busy and errors, for whoever is sending
+	done       chan struct{}  // Closed when the port can't be read any more
+	err        error          // Why the port was closed
+	extraOKs   int            // oks owed to commands written straight to the port, not passed on
+	probes     int            // M105s sent to a quiet printer that haven't been answered
+	probed     chan *Response // The answer to the last of the probes
+	transcript []string       // Unsolicited lines collected for a query, nil when nobody's asking
+	*sync.Mutex
+}
 
-	gfile, err := gcode.ParseFile(f)
-	if err != nil {
-		return terror.New(err, "")
+func newSerialReader() *serialReader {
+	return &serialReader{
+		responses: make(chan *Response, 64),
+		probed:    make(chan *Response, 1),
+		done:      make(chan struct{}),
+		Mutex:     &sync.Mutex{},
 	}
-	fmt.Println("Start sending gcode")
-	for _, l := range gfile.Lines {
-		if strings.HasPrefix(l.String(), ";") {
-			continue
+}
+
+// readSerial reads lines from the printer with a single buffered reader and routes them by kind.
+// Each open of the port gets its own reader, closed when the port stops working.
+func (a *Agent) readSerial(port io.Reader, sr *serialReader) {
+	brdr := bufio.NewReader(port)
+	for {
+		line, err := brdr.ReadString('\n')
+		if err != nil {
+			sr.Lock()
+			sr.err = err
+			sr.Unlock()
+			close(sr.done)
+			log.Warnw("Stopped reading serial port", "err", err)
+			return
 		}
-		if l.String() == "" {
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		if len(l.String()) == 0 {
-			continue
+		fmt.Print("RECV: ", line)
+		a.route(sr, ParseResponse(line))
+	}
+}
+
+// route hands a response read by sr to whatever deals with its kind
+func (a *Agent) route(sr *serialReader, resp *Response) {
+	if resp.Temperatures != nil {
+		a.Lock()
+		a.Temperatures = resp.Temperatures
+		a.Unlock()
+	}
+	switch resp.Kind {
+	case RespKindOK:
+		sr.Lock()
+		if sr.probes > 0 && resp.Temperatures != nil {
+			// An M105 answers with the temperatures, which the oks for ordinary commands don't carry
+			sr.probes--
+			last := sr.probes == 0
+			sr.Unlock()
+			if last {
+				resp.Kind = RespKindProbe
+				select {
+				case sr.probed <- resp:
+				default:
+				}
+			}
+			return
 		}
-		if !unicode.IsLetter(rune(l.String()[0])) {
-			continue
+		if sr.extraOKs > 0 {
+			sr.extraOKs--
+			sr.Unlock()
+			return
 		}
-		fmt.Print("SEND: ", l.String())
-		_, err = s.Write([]byte(l.String()))
-		if err != nil {
-			return terror.New(err, "")
+		sr.Unlock()
+		sr.push(resp)
+	case RespKindResend, RespKindBusy:
+		sr.push(resp)
+	case RespKindError:
+		a.handleError(resp.Text)
+		sr.push(resp)
+	case RespKindAction:
+		a.handleHostAction(resp.Action)
+	case RespKindStart:
+		log.Infow("Printer restarted")
+	case RespKindTemperature:
+	case RespKindEcho, RespKindUnknown:
+		sr.Lock()
+		if sr.transcript != nil {
+			sr.transcript = append(sr.transcript, resp.Raw)
+		}
+		sr.Unlock()
+	}
+}
+
+// push queues a response for the sender without ever blocking the reader
+func (sr *serialReader) push(resp *Response) {
+	select {
+	case sr.responses <- resp:
+	default:
+		log.Warnw("Nobody is listening to the printer, dropping response", "kind", resp.Kind, "line", resp.Raw)
+	}
+}
+
+// next waits for the next response meant for the sender
+func (sr *serialReader) next(ctx context.Context) (*Response, error) {
+	select {
+	case resp := <-sr.responses:
+		return resp, nil
+	case <-sr.done:
+		sr.Lock()
+		defer sr.Unlock()
+		return nil, fmt.Errorf("%w: %v", ErrSerialClosed, sr.err)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// drain throws away responses left over from earlier commands
+func (sr *serialReader) drain() {
+	sr.Lock()
+	sr.probes = 0
+	sr.Unlock()
+	for {
+		select {
+		case <-sr.responses:
+		case <-sr.probed:
+		default:
+			return
 		}
+	}
+}
 
-		brdr := bufio.NewReader(s)
-		result, err := brdr.ReadString('\n')
+// writeDirect writes a command straight to the port, skipping any running job.
+// Commands the firmware also queues get an ok of their own, which mustn't be taken for the job's.
+func (a *Agent) writeDirect(line string, expectOK bool) error {
+	a.Lock()
+	port, sr := a.Serial, a.reader
+	disconnected := port == nil || a.Status == messages.StatusDisconnected
+	a.Unlock()
+	if disconnected {
+		return ErrDisconnected
+	}
+	return writeTo(port, sr, line, expectOK)
+}
+
+// writeTo writes a command to the port. With expectOK its ok is counted off on sr, the port's reader.
+func writeTo(port serial.Port, sr *serialReader, line string, expectOK bool) error {
+	if expectOK {
+		sr.Lock()
+		sr.extraOKs++
+		sr.Unlock()
+	}
+	fmt.Println("SEND:", line)
+	_, err := port.Write([]byte(line + "\n"))
+	if err != nil {
+		if expectOK {
+			sr.Lock()
+			sr.extraOKs--
+			sr.Unlock()
+		}
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+// query sends a single command and returns the unsolicited lines the printer sent before its ok.
+// Only used when nothing else is sending.
+func (a *Agent) query(ctx context.Context, cmd string) ([]string, error) {
+	port, sr := a.serialPort()
+	if port == nil {
+		return nil, ErrDisconnected
+	}
+	sr.drain()
+	sr.Lock()
+	sr.transcript = []string{}
+	sr.Unlock()
+	defer func() {
+		sr.Lock()
+		sr.transcript = nil
+		sr.Unlock()
+	}()
+
+	fmt.Println("SEND:", cmd)
+	_, err := port.Write([]byte(cmd + "\n"))
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	for {
+		resp, err := sr.nextWithin(ctx, commandTimeout(cmd))
+		if errors.Is(err, errTimeout) {
+			return nil, fmt.Errorf("%w: no answer to %s", ErrStalled, cmd)
+		}
 		if err != nil {
-			return terror.New(err, "")
+			return nil, err
 		}
-		fmt.Print("RECV: ", result)
-		if unicode.IsLetter(rune(result[0])) && unicode.IsUpper(rune(result[0])) {
+		if resp.Kind != RespKindOK {
 			continue
 		}
+		sr.Lock()
+		lines := sr.transcript
+		sr.Unlock()
+		return lines, nil
+	}
+}
+
+// print streams the gcode, keeping as many lines in flight as the flow control allows.
+// Lines are held while the job is paused.
+func (a *Agent) print(ctx context.Context, f io.Reader, j *job, flow FlowSettings) error {
+	fmt.Println("Start print")
+
+	gfile, err := gcode.ParseFile(f)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	port, sr := a.serialPort()
+	if port == nil {
+		return ErrDisconnected
+	}
+	sr.drain()
+	w := newWindow(flow)
+	history := map[int]string{} // Numbered lines by number, for resend requests
+	lastSent := ""
+	resendFrom := -1 // Line we've already rewound to, repeat requests for it are ignored
+	replayNext := -1 // Next line to send again after a resend request, -1 when there's nothing to replay
 
-		if result != RespOK {
-			for {
-				brdr := bufio.NewReader(s)
-				result, err := brdr.ReadString('\n')
-				if err != nil {
-					return terror.New(err, "")
+	write := func(out string, line int) error {
+		fmt.Print("SEND: ", out)
+		_, err := port.Write([]byte(out))
+		if err != nil {
+			return terror.New(err, "")
+		}
+		w.Sent(out, line)
+		lastSent = out
+		if line != unnumbered {
+			history[line] = out
+			delete(history, line-resendHistory)
+		}
+		return nil
+	}
+	// handle deals with a response while waiting to send
+	handle := func(resp *Response) error {
+		switch resp.Kind {
+		case RespKindOK:
+			a.handleAcked(w.Ack(resp.OK))
+			if resp.OK.Line >= resendFrom {
+				resendFrom = -1
+			}
+		case RespKindProbe:
+			// The firmware queues the M105 behind everything in flight, so it has all been processed
+			for _, line := range w.AckAll() {
+				a.handleAcked(line)
+			}
+		case RespKindResend:
+			// Every rejected line gets a resend request and an ok of its own, repeats included
+			w.Rejected()
+			if flow.Mode != FlowAdvanced {
+				// Unnumbered lines can only be resent one at a time
+				w.Rewind(unnumbered)
+				return write(lastSent, unnumbered)
+			}
+			if resp.Line == resendFrom {
+				return nil
+			}
+			log.Warnw("Printer asked for a resend", "line", resp.Line)
+			resendFrom = resp.Line
+			// The firmware throws away the bad line and everything after it, the lines before it are still coming back
+			w.Rewind(resp.Line)
+			replayNext = resp.Line
+		}
+		return nil
+	}
+	// pump writes the lines the firmware asked for again and then out, waiting for room and handling
+	// the printer's responses as they come. With no out it waits until every line has been acknowledged.
+	pump := func(out string, line int) error {
+		for {
+			if replayNext >= 0 {
+				replay, ok := history[replayNext]
+				if !ok {
+					replayNext = -1
+					continue
 				}
-				fmt.Print("RECV: ", result)
-				if result == RespOK {
-					break
+				if w.CanSend(len(replay)) {
+					replayNext++
+					err := write(replay, replayNext-1)
+					if err != nil {
+						return err
+					}
+					continue
 				}
-				if unicode.IsLetter(rune(result[0])) && unicode.IsUpper(rune(result[0])) {
-					break
+			} else if out == "" {
+				if w.Empty() {
+					return nil
 				}
+			} else if w.CanSend(len(out)) {
+				return write(out, line)
+			}
+			resp, err := a.await(ctx, port, sr, w.Timeout())
+			if err != nil {
+				return err
 			}
+			err = handle(resp)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	lineNumber := 0
+	if flow.Mode == FlowAdvanced {
+		// Numbered lines let the firmware reject corrupted ones, start counting from zero
+		err = pump("M110 N0\n", unnumbered)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Start sending gcode")
+	for _, l := range gfile.Lines {
+		cmd := command(l)
+		if cmd == "" {
+			continue
+		}
+		err = j.wait(ctx)
+		if err != nil {
+			fmt.Println("Print stopped")
+			return err
+		}
+		out, line := cmd+"\n", unnumbered
+		if flow.Mode == FlowAdvanced {
+			lineNumber++
+			out, line = numberLine(lineNumber, cmd), lineNumber
+		}
+		a.handleSent(cmd)
+		err = pump(out, line)
+		if err != nil {
+			return err
 		}
 	}
+	err = pump("", unnumbered)
+	if err != nil {
+		return err
+	}
 	fmt.Println("Send GCode complete")
 	return nil
 }
+
+// command is the gcode line without its comment, empty if there is nothing to send.
+// Comments have to go, Marlin ignores everything after a ; including the checksum.
+func command(l gcode.Line) string {
+	codes := []string{}
+	for _, c := range l.Codes {
+		if c.Comment != "" {
+			continue
+		}
+		codes = append(codes, c.String())
+	}
+	cmd := strings.Join(codes, " ")
+	if cmd == "" || !unicode.IsLetter(rune(cmd[0])) {
+		return ""
+	}
+	return cmd
+}
@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// CommandTimeout is how long an ordinary command has to be acknowledged
+const CommandTimeout = 30 * time.Second
+
+// LongCommandTimeout is for homing, probing, heating and waiting for moves to finish.
+// Firmware with HOST_KEEPALIVE sends busy: while they run, which restarts the clock.
+const LongCommandTimeout = 10 * time.Minute
+
+// StallTimeout is how long to wait for an answer to the M105 sent to a quiet printer
+const StallTimeout = 5 * time.Second
+
+// StallRetries is how many M105s a quiet printer gets before we give up on it
+const StallRetries = 3
+
+// ErrStalled is returned when the printer stops answering altogether
+var ErrStalled = errors.New("printer stopped responding")
+
+// errTimeout is a single wait for the printer running out
+var errTimeout = errors.New("timed out waiting for printer")
+
+// longCommands take as long as they take, G28 homing, G29 probing, M109/M190 heating and M400 finishing moves
+var longCommands = map[string]bool{
+	"G28":  true,
+	"G29":  true,
+	"M109": true,
+	"M190": true,
+	"M191": true,
+	"M303": true,
+	"M400": true,
+}
+
+// userCommands wait for someone at the printer, so they never time out
+var userCommands = map[string]bool{
+	"M0":   true,
+	"M1":   true,
+	"M600": true,
+}
+
+// commandCode is the G or M code of a line, upper cased. Line numbers and checksums are ignored.
+func commandCode(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) > 1 && strings.HasPrefix(fields[0], "N") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(strings.SplitN(fields[0], "*", 2)[0])
+}
+
+// commandTimeout is how long the firmware gets to acknowledge the line, 0 for no limit
+func commandTimeout(line string) time.Duration {
+	code := commandCode(line)
+	switch {
+	case userCommands[code]:
+		return 0
+	case longCommands[code]:
+		return LongCommandTimeout
+	case code == "G4":
+		// Firmware without HOST_KEEPALIVE says nothing while it dwells
+		return dwellTime(line) + CommandTimeout
+	}
+	return CommandTimeout
+}
+
+// dwellTime is how long a G4 pauses for, S in seconds taking precedence over P in milliseconds as in Marlin
+func dwellTime(line string) time.Duration {
+	line = strings.SplitN(strings.SplitN(line, "*", 2)[0], ";", 2)[0]
+	var dwell time.Duration
+	seconds := false
+	for _, field := range strings.Fields(line) {
+		if len(field) < 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(field[1:], 64)
+		if err != nil || v < 0 {
+			continue
+		}
+		switch field[0] {
+		case 'S', 's':
+			dwell = time.Duration(v * float64(time.Second))
+			seconds = true
+		case 'P', 'p':
+			if !seconds {
+				dwell = time.Duration(v * float64(time.Millisecond))
+			}
+		}
+	}
+	return dwell
+}
+
+// nextWithin is next with a time limit. Every response restarts the clock,
+// so busy: reports keep long commands alive. No limit if timeout is 0.
+func (sr *serialReader) nextWithin(ctx context.Context, timeout time.Duration) (*Response, error) {
+	if timeout <= 0 {
+		return sr.next(ctx)
+	}
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := sr.next(tctx)
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return nil, errTimeout
+	}
+	return resp, err
+}
+
+// probe sends M105 to a quiet printer. Its ok is told apart by the temperatures in it
+// and comes back from nextOrProbe as RespKindProbe, not as the ok of a line in flight.
+func (a *Agent) probe(port serial.Port, sr *serialReader) error {
+	// An answer left over from the last time the printer went quiet is no use now
+	select {
+	case <-sr.probed:
+	default:
+	}
+	sr.Lock()
+	sr.probes++
+	sr.Unlock()
+	err := writeTo(port, sr, "M105", false)
+	if err != nil {
+		sr.Lock()
+		sr.probes--
+		sr.Unlock()
+		return err
+	}
+	return nil
+}
+
+// nextOrProbe is nextWithin, also returning the answer to the last probe sent
+func (sr *serialReader) nextOrProbe(ctx context.Context, timeout time.Duration) (*Response, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-sr.responses:
+		return resp, nil
+	case resp := <-sr.probed:
+		return resp, nil
+	case <-sr.done:
+		sr.Lock()
+		defer sr.Unlock()
+		return nil, fmt.Errorf("%w: %v", ErrSerialClosed, sr.err)
+	case <-timer.C:
+		return nil, errTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// await waits for the next response to the lines in flight. If the printer goes quiet it's
+// nudged with M105. The firmware answers it once everything before it has been processed, so its
+// ok means the oks for the lines in flight were lost. A printer that stays quiet fails the job,
+// it's stopped rather than killed, M999 is worth a try before resetting the board.
+func (a *Agent) await(ctx context.Context, port serial.Port, sr *serialReader, timeout time.Duration) (*Response, error) {
+	resp, err := sr.nextWithin(ctx, timeout)
+	for i := 0; errors.Is(err, errTimeout) && i < StallRetries; i++ {
+		log.Warnw("Printer went quiet, sending M105 to resync", "attempt", i+1)
+		err = a.probe(port, sr)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = sr.nextOrProbe(ctx, StallTimeout)
+	}
+	if errors.Is(err, errTimeout) {
+		err = fmt.Errorf("%w, no answer to %d attempts to resync", ErrStalled, StallRetries)
+		a.fail(err.Error(), SeverityStopped)
+		return nil, err
+	}
+	return resp, err
+}
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCommandTimeout(t *testing.T) {
+	tests := []struct {
+		line string
+		want time.Duration
+	}{
+		{"G1 X10", CommandTimeout},
+		{"G28", LongCommandTimeout},
+		{"g28 X", LongCommandTimeout},
+		{"M109 S210", LongCommandTimeout},
+		{"N12 M190 S60*91", LongCommandTimeout},
+		{"G4 P60000", 60*time.Second + CommandTimeout},
+		{"N5 G4 S60*44", 90 * time.Second},
+		{"G4 S2 P500", 2*time.Second + CommandTimeout},
+		{"G4 P500 S2", 2*time.Second + CommandTimeout},
+		{"G4 S1.5 ; wait for the fan", 1500*time.Millisecond + CommandTimeout},
+		{"G4", CommandTimeout},
+		{"N3 M600*33\n", 0},
+		{"M0", 0},
+		{"", CommandTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := commandTimeout(tt.line)
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteProbe(t *testing.T) {
+	a := &Agent{Mutex: &sync.Mutex{}}
+	sr := newSerialReader()
+	sr.probes = 2
+
+	// The late ok of the stalled line is passed on as itself
+	a.route(sr, ParseResponse("ok\n"))
+	select {
+	case resp := <-sr.responses:
+		if resp.Kind != RespKindOK {
+			t.Fatalf("got %s, want ok", resp.Kind)
+		}
+	default:
+		t.Fatal("ok for the line in flight wasn't passed on")
+	}
+
+	// Only the answer to the last probe is handed over
+	a.route(sr, ParseResponse("ok T:210.0 /210.0 B:60.0 /60.0\n"))
+	select {
+	case <-sr.probed:
+		t.Fatal("answer to the first of two probes was handed over")
+	default:
+	}
+	a.route(sr, ParseResponse("ok T:210.0 /210.0 B:60.0 /60.0\n"))
+	select {
+	case resp := <-sr.probed:
+		if resp.Kind != RespKindProbe {
+			t.Errorf("got %s, want probe", resp.Kind)
+		}
+	default:
+		t.Fatal("answer to the last probe wasn't handed over")
+	}
+	select {
+	case resp := <-sr.responses:
+		t.Fatalf("probe answer %q was taken for a line's ok", resp.Raw)
+	default:
+	}
+}
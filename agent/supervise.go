@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// ErrPanicked is returned when the agent, or one of the goroutines it started, panicked
+var ErrPanicked = errors.New("agent panicked")
+
+// restartDelay is how long a printer's agent is left stopped before it's started again.
+// A var so tests don't have to wait it out.
+var restartDelay = 5 * time.Second
+
+// Supervise runs an agent for each printer with run. Each one is restarted on its own, so one
+// printer failing, panicking or being unplugged doesn't disturb the others. It returns once ctx
+// is done, or every printer has been given up on because the server rejected it.
+func Supervise(ctx context.Context, printers []*PrinterConfig, run func(ctx context.Context, printer *PrinterConfig) error) error {
+	wg := &sync.WaitGroup{}
+	for _, p := range printers {
+		wg.Add(1)
+		logW := log.With("service", "supervisor", "printer", p.Name)
+		p := p
+		goSafe(func() {
+			defer wg.Done()
+			for {
+				err := runOnce(ctx, p, run)
+				if ctx.Err() != nil {
+					return
+				}
+				if errors.Is(err, ErrRejected) {
+					logW.Errorw("Server rejected the agent, giving up on printer", "err", err)
+					return
+				}
+				logW.Warnw("Agent stopped, restarting", "err", err)
+				select {
+				case <-time.After(restartDelay):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}, func(err error) {
+			logW.Errorw("Supervisor panicked, giving up on printer", "err", err)
+		})
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// runOnce runs one printer's agent until it stops, and stops everything it started along with it.
+// A panic is returned as an error so the printer's agent can be restarted.
+func runOnce(ctx context.Context, printer *PrinterConfig, run func(ctx context.Context, printer *PrinterConfig) error) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrPanicked, rec, debug.Stack())
+		}
+	}()
+	return run(ctx, printer)
+}
+
+// goSafe runs f in a goroutine, handing a panic in it to recovered rather than taking down the
+// process and every other printer it runs
+func goSafe(f func(), recovered func(err error)) {
+	go func() {
+		defer func() {
+			rec := recover()
+			if rec != nil {
+				recovered(fmt.Errorf("%w: %v\n%s", ErrPanicked, rec, debug.Stack()))
+			}
+		}()
+		f()
+	}()
+}
+
+// goSafe runs f in a goroutine of the agent's. A panic in it ends Run, so the supervisor restarts the agent.
+func (a *Agent) goSafe(f func()) {
+	goSafe(f, func(err error) {
+		log.Errorw("Agent goroutine panicked", "printer", a.Name, "err", err)
+		select {
+		case a.panics <- err:
+		default:
+			// Run is already on its way out
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+	"go-3dprint/messages"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSuperviseRestartsPanickedPrinter(t *testing.T) {
+	defer func(d time.Duration) { restartDelay = d }(restartDelay)
+	restartDelay = time.Millisecond
+
+	mu := &sync.Mutex{}
+	runs := map[string]int{}
+	started := func(name string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return runs[name]
+	}
+	run := func(ctx context.Context, printer *PrinterConfig) error {
+		mu.Lock()
+		runs[printer.Name]++
+		first := runs[printer.Name] == 1
+		mu.Unlock()
+		// Nothing listens here, the agent keeps trying to reach the server until it's stopped
+		a := &Agent{
+			Name:      printer.Name,
+			Status:    messages.StatusDisconnected,
+			Mutex:     &sync.Mutex{},
+			ServerURL: "ws://127.0.0.1:1/api/websocket",
+			outbox:    newOutbox(),
+			panics:    make(chan error, 1),
+		}
+		if printer.Name == "flaky" && first {
+			a.goSafe(func() { panic("boom") })
+		}
+		return a.Run(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Supervise(ctx, []*PrinterConfig{{Name: "flaky"}, {Name: "steady"}}, run)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for started("flaky") < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("printer whose goroutine panicked wasn't restarted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := started("steady"); n != 1 {
+		t.Errorf("other printer was started %d times, want once", n)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor didn't stop")
+	}
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/ninja-software/terror"
+)
+
+// WebsocketPath is where the server accepts agents when the server URL doesn't say
+const WebsocketPath = "/api/websocket"
+
+// ServerConfig is how the agent reaches the server
+type ServerConfig struct {
+	URL      string `json:"server_url"` // ws:// or wss://, with or without the websocket path
+	CAFile   string `json:"ca_file"`    // PEM bundle to trust the server's certificate with, the system's when empty
+	CertFile string `json:"cert_file"`  // Client certificate, for servers that require mutual TLS
+	KeyFile  string `json:"key_file"`   // Key for the client certificate
+}
+
+// ServerURL is the plain websocket URL for a host and port, as given before the server URL could be
+func ServerURL(host, port string) string {
+	return fmt.Sprintf("ws://%s:%s", host, port)
+}
+
+// WebsocketURL is the server URL with the websocket path filled in. http and https are taken to mean ws and wss.
+func (s *ServerConfig) WebsocketURL() (string, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return "", terror.New(err, "")
+	}
+	switch u.Scheme {
+	case "ws", "wss":
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	default:
+		return "", terror.New(fmt.Errorf("server url %s: scheme must be ws or wss", s.URL), "")
+	}
+	if u.Host == "" {
+		return "", terror.New(fmt.Errorf("server url %s has no host", s.URL), "")
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = WebsocketPath
+	}
+	return u.String(), nil
+}
+
+// TLSConfig loads the CA bundle and client certificate. It's nil when neither is set, using the defaults.
+func (s *ServerConfig) TLSConfig() (*tls.Config, error) {
+	if s.CAFile == "" && s.CertFile == "" && s.KeyFile == "" {
+		return nil, nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.CAFile != "" {
+		b, err := ioutil.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, terror.New(fmt.Errorf("%s: no certificates found", s.CAFile), "")
+		}
+		config.RootCAs = pool
+	}
+	if s.CertFile != "" || s.KeyFile != "" {
+		if s.CertFile == "" || s.KeyFile == "" {
+			return nil, terror.New(errors.New("cert_file and key_file must be given together"), "")
+		}
+		cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// Client is used for everything the agent asks of the server, the websocket and file downloads alike
+func (s *ServerConfig) Client() (*http.Client, error) {
+	config, err := s.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return http.DefaultClient, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,693 @@
+// Code generated by go run ./gen from the server's OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// APITokenRequest is a schema from the OpenAPI document
+type APITokenRequest struct {
+	Name string `json:"name"`
+}
+
+// AgentInfo is a schema from the OpenAPI document
+type AgentInfo struct {
+	Busy         bool                    `json:"busy,omitempty"`
+	Error        string                  `json:"error,omitempty"`
+	Firmware     *FirmwareInfo           `json:"firmware,omitempty"`
+	PauseReason  string                  `json:"pause_reason,omitempty"`
+	Prompt       *Prompt                 `json:"prompt,omitempty"`
+	Status       string                  `json:"status,omitempty"`
+	Temperatures map[string]*Temperature `json:"temperatures,omitempty"`
+}
+
+// AuditEvent is a schema from the OpenAPI document
+type AuditEvent struct {
+	Action    string          `json:"action,omitempty"`
+	Actor     string          `json:"actor,omitempty"`
+	CreatedAt time.Time       `json:"created_at,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	PrinterID *string         `json:"printer_id,omitempty"`
+	UserID    *string         `json:"user_id,omitempty"`
+}
+
+// CommandRequest is a schema from the OpenAPI document
+type CommandRequest struct {
+	Choice  *int   `json:"choice,omitempty"`
+	Command string `json:"command"`
+}
+
+// ErrorBody is a schema from the OpenAPI document
+type ErrorBody struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ErrorResponse is a schema from the OpenAPI document
+type ErrorResponse struct {
+	Error *ErrorBody `json:"error,omitempty"`
+}
+
+// FirmwareInfo is a schema from the OpenAPI document
+type FirmwareInfo struct {
+	Capabilities    map[string]bool `json:"capabilities,omitempty"`
+	ExtruderCount   int             `json:"extruder_count,omitempty"`
+	MachineType     string          `json:"machine_type,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	ProtocolVersion string          `json:"protocol_version,omitempty"`
+	SourceCodeURL   string          `json:"source_code_url,omitempty"`
+	UUID            string          `json:"uuid,omitempty"`
+}
+
+// Folder is a schema from the OpenAPI document
+type Folder struct {
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	ParentID  *string   `json:"parent_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// FolderRequest is a schema from the OpenAPI document
+type FolderRequest struct {
+	Name     *string `json:"name,omitempty"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+// Gcode is a schema from the OpenAPI document
+type Gcode struct {
+	BlobID          string          `json:"blob_id,omitempty"`
+	CreatedAt       time.Time       `json:"created_at,omitempty"`
+	DeletedAt       *time.Time      `json:"deleted_at,omitempty"`
+	FolderID        *string         `json:"folder_id,omitempty"`
+	Format          string          `json:"format,omitempty"`
+	ID              string          `json:"id,omitempty"`
+	Metadata        json.RawMessage `json:"metadata,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	Tags            []string        `json:"tags,omitempty"`
+	ThumbnailBlobID *string         `json:"thumbnail_blob_id,omitempty"`
+	UpdatedAt       time.Time       `json:"updated_at,omitempty"`
+	Version         int             `json:"version,omitempty"`
+}
+
+// GcodeUpdate is a schema from the OpenAPI document
+type GcodeUpdate struct {
+	FolderID *string  `json:"folder_id,omitempty"`
+	Name     *string  `json:"name,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// IssuedToken is a schema from the OpenAPI document
+type IssuedToken struct {
+	ID        string `json:"id,omitempty"`
+	PrinterID string `json:"printerId,omitempty"`
+	Token     string `json:"token,omitempty"`
+}
+
+// Job is a schema from the OpenAPI document
+type Job struct {
+	FileID    string     `json:"file_id,omitempty"`
+	FileName  string     `json:"file_name,omitempty"`
+	LoadedAt  time.Time  `json:"loaded_at,omitempty"`
+	LoadedBy  string     `json:"loaded_by,omitempty"`
+	PrinterID string     `json:"printer_id,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	Version   int        `json:"version,omitempty"`
+}
+
+// JobRequest is a schema from the OpenAPI document
+type JobRequest struct {
+	FileID  string `json:"file_id"`
+	Version int    `json:"version,omitempty"`
+}
+
+// LoadCommand is a schema from the OpenAPI document
+type LoadCommand struct {
+	FileID    string `json:"file_id"`
+	SessionID string `json:"session_id"`
+}
+
+// LoginRequest is a schema from the OpenAPI document
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Printer is a schema from the OpenAPI document
+type Printer struct {
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at,omitempty"`
+}
+
+// PrinterRequest is a schema from the OpenAPI document
+type PrinterRequest struct {
+	Name string `json:"name"`
+}
+
+// PrinterResource is a schema from the OpenAPI document
+type PrinterResource struct {
+	Connected bool       `json:"connected,omitempty"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	Info      *AgentInfo `json:"info,omitempty"`
+	Job       *Job       `json:"job,omitempty"`
+	Name      string     `json:"name,omitempty"`
+}
+
+// Prompt is a schema from the OpenAPI document
+type Prompt struct {
+	Choices []string `json:"choices,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// PromptRequest is a schema from the OpenAPI document
+type PromptRequest struct {
+	Choice    int    `json:"choice"`
+	SessionID string `json:"sessionId"`
+}
+
+// PurgeResult is a schema from the OpenAPI document
+type PurgeResult struct {
+	Purged int `json:"purged,omitempty"`
+}
+
+// SessionRequest is a schema from the OpenAPI document
+type SessionRequest struct {
+	SessionID string `json:"sessionId"`
+}
+
+// SettingChange is a schema from the OpenAPI document
+type SettingChange struct {
+	Change string `json:"change,omitempty"`
+	From   string `json:"from,omitempty"`
+	Key    string `json:"key,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+// SettingsDiff is a schema from the OpenAPI document
+type SettingsDiff struct {
+	Changes []*SettingChange `json:"changes,omitempty"`
+	From    int              `json:"from,omitempty"`
+	To      int              `json:"to,omitempty"`
+}
+
+// Temperature is a schema from the OpenAPI document
+type Temperature struct {
+	Actual float64 `json:"actual,omitempty"`
+	Target float64 `json:"target,omitempty"`
+}
+
+// TokenInfo is a schema from the OpenAPI document
+type TokenInfo struct {
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	PrinterID  string     `json:"printerId,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// TokenRequest is a schema from the OpenAPI document
+type TokenRequest struct {
+	PrinterID string `json:"printerId,omitempty"`
+	TokenID   string `json:"tokenId,omitempty"`
+}
+
+// Upload is a schema from the OpenAPI document
+type Upload struct {
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+	CreatedBy     string    `json:"created_by,omitempty"`
+	ExpiresAt     time.Time `json:"expires_at,omitempty"`
+	FolderID      *string   `json:"folder_id,omitempty"`
+	ID            string    `json:"id,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	NewVersion    bool      `json:"new_version,omitempty"`
+	ReceivedBytes int       `json:"received_bytes,omitempty"`
+	Sha256        *string   `json:"sha256,omitempty"`
+	SizeBytes     int       `json:"size_bytes,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
+
+// UploadRequest is a schema from the OpenAPI document
+type UploadRequest struct {
+	FolderID   string   `json:"folder_id,omitempty"`
+	Name       string   `json:"name"`
+	NewVersion bool     `json:"new_version,omitempty"`
+	Sha256     string   `json:"sha256,omitempty"`
+	SizeBytes  int      `json:"size_bytes"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// UserInfo is a schema from the OpenAPI document
+type UserInfo struct {
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Role      string    `json:"role,omitempty"`
+}
+
+// UserRequest is a schema from the OpenAPI document
+type UserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// VersionInfo is a schema from the OpenAPI document
+type VersionInfo struct {
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	Current    bool      `json:"current,omitempty"`
+	Sha256     string    `json:"sha256,omitempty"`
+	SizeBytes  int       `json:"size_bytes,omitempty"`
+	UploadedBy string    `json:"uploaded_by,omitempty"`
+	Version    int       `json:"version,omitempty"`
+}
+
+// AuditListParams are the query parameters for AuditList
+type AuditListParams struct {
+	// Only events for this printer
+	PrinterID string
+	// Only events by this user
+	UserID string
+	// Only events with this action
+	Action string
+	// Only events at or after this time
+	Since time.Time
+	// Only events before this time
+	Until time.Time
+	// At most this many events
+	Limit int
+	// csv for CSV
+	Format string
+}
+
+// AuditList: Audit log, newest first. format=csv returns CSV instead..
+// GET /api/audit, needs the admin role
+func (c *Client) AuditList(ctx context.Context, params *AuditListParams) ([]*AuditEvent, error) {
+	q := url.Values{}
+	if params != nil {
+		if params.PrinterID != "" {
+			q.Set("printer_id", params.PrinterID)
+		}
+		if params.UserID != "" {
+			q.Set("user_id", params.UserID)
+		}
+		if params.Action != "" {
+			q.Set("action", params.Action)
+		}
+		if !params.Since.IsZero() {
+			q.Set("since", params.Since.Format(time.RFC3339))
+		}
+		if !params.Until.IsZero() {
+			q.Set("until", params.Until.Format(time.RFC3339))
+		}
+		if params.Limit != 0 {
+			q.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Format != "" {
+			q.Set("format", params.Format)
+		}
+	}
+	var result []*AuditEvent
+	err := c.do(ctx, "GET", "/api/audit", q, nil, &result)
+	return result, err
+}
+
+// Login: Log in, setting the session cookie.
+// POST /api/auth/login
+func (c *Client) Login(ctx context.Context, body *LoginRequest) (*UserInfo, error) {
+	var result *UserInfo
+	err := c.do(ctx, "POST", "/api/auth/login", nil, body, &result)
+	return result, err
+}
+
+// Logout: Log out, ending the session.
+// POST /api/auth/logout
+func (c *Client) Logout(ctx context.Context) error {
+	return c.do(ctx, "POST", "/api/auth/logout", nil, nil, nil)
+}
+
+// Me: The logged in user.
+// GET /api/auth/me, needs the viewer role
+func (c *Client) Me(ctx context.Context) (*UserInfo, error) {
+	var result *UserInfo
+	err := c.do(ctx, "GET", "/api/auth/me", nil, nil, &result)
+	return result, err
+}
+
+// IssueAPIToken: Issue an API token for the logged in user, shown only once.
+// POST /api/auth/tokens/issue, needs the viewer role
+func (c *Client) IssueAPIToken(ctx context.Context, body *APITokenRequest) (*IssuedToken, error) {
+	var result *IssuedToken
+	err := c.do(ctx, "POST", "/api/auth/tokens/issue", nil, body, &result)
+	return result, err
+}
+
+// RevokeAPIToken: Revoke an API token, admins can revoke anyone's.
+// POST /api/auth/tokens/revoke, needs the viewer role
+func (c *Client) RevokeAPIToken(ctx context.Context, body *TokenRequest) error {
+	return c.do(ctx, "POST", "/api/auth/tokens/revoke", nil, body, nil)
+}
+
+// CommandLevelBedTest: Level the bed, not implemented yet.
+// POST /api/command/levelbedtest, needs the operator role
+func (c *Client) CommandLevelBedTest(ctx context.Context, body *SessionRequest) error {
+	return c.do(ctx, "POST", "/api/command/levelbedtest", nil, body, nil)
+}
+
+// TokensListParams are the query parameters for TokensList
+type TokensListParams struct {
+	// ID of the printer
+	PrinterID string
+}
+
+// TokensList: Agent tokens issued for a printer.
+// GET /api/printers/tokens, needs the admin role
+func (c *Client) TokensList(ctx context.Context, params *TokensListParams) ([]*TokenInfo, error) {
+	q := url.Values{}
+	if params != nil {
+		if params.PrinterID != "" {
+			q.Set("printer_id", params.PrinterID)
+		}
+	}
+	var result []*TokenInfo
+	err := c.do(ctx, "GET", "/api/printers/tokens", q, nil, &result)
+	return result, err
+}
+
+// TokensIssue: Issue an agent token for a printer, shown only once.
+// POST /api/printers/tokens/issue, needs the admin role
+func (c *Client) TokensIssue(ctx context.Context, body *TokenRequest) (*IssuedToken, error) {
+	var result *IssuedToken
+	err := c.do(ctx, "POST", "/api/printers/tokens/issue", nil, body, &result)
+	return result, err
+}
+
+// TokensRevoke: Revoke an agent token, dropping agents using it.
+// POST /api/printers/tokens/revoke, needs the admin role
+func (c *Client) TokensRevoke(ctx context.Context, body *TokenRequest) error {
+	return c.do(ctx, "POST", "/api/printers/tokens/revoke", nil, body, nil)
+}
+
+// UsersList: Users.
+// GET /api/users, needs the admin role
+func (c *Client) UsersList(ctx context.Context) ([]*UserInfo, error) {
+	var result []*UserInfo
+	err := c.do(ctx, "GET", "/api/users", nil, nil, &result)
+	return result, err
+}
+
+// UsersCreate: Add a user.
+// POST /api/users, needs the admin role
+func (c *Client) UsersCreate(ctx context.Context, body *UserRequest) (*UserInfo, error) {
+	var result *UserInfo
+	err := c.do(ctx, "POST", "/api/users", nil, body, &result)
+	return result, err
+}
+
+// ListFolders: Every folder, build the tree from their parent_id.
+// GET /api/v2/folders, needs the viewer role
+func (c *Client) ListFolders(ctx context.Context) ([]*Folder, error) {
+	var result []*Folder
+	err := c.do(ctx, "GET", "/api/v2/folders", nil, nil, &result)
+	return result, err
+}
+
+// CreateFolder: Create a folder.
+// POST /api/v2/folders, needs the admin role
+func (c *Client) CreateFolder(ctx context.Context, body *FolderRequest) (*Folder, error) {
+	var result *Folder
+	err := c.do(ctx, "POST", "/api/v2/folders", nil, body, &result)
+	return result, err
+}
+
+// DeleteFolder: Delete an empty folder.
+// DELETE /api/v2/folders/{id}, needs the admin role
+func (c *Client) DeleteFolder(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v2/folders/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// UpdateFolder: Rename a folder or move it into another.
+// PATCH /api/v2/folders/{id}, needs the admin role
+func (c *Client) UpdateFolder(ctx context.Context, id string, body *FolderRequest) (*Folder, error) {
+	var result *Folder
+	err := c.do(ctx, "PATCH", "/api/v2/folders/"+url.PathEscape(id), nil, body, &result)
+	return result, err
+}
+
+// ListGcodesParams are the query parameters for ListGcodes
+type ListGcodesParams struct {
+	// Only files with this in their name, any case
+	Q string
+	// Only files in this folder, root for files outside any folder
+	FolderID string
+	// Only files with all these comma separated tags
+	Tags string
+	// Only deleted files instead of only live ones
+	Deleted bool
+	// Order, - prefix for descending
+	Sort string
+	// At most this many files, the total is in the X-Total-Count header
+	Limit int
+	// Skip this many files
+	Offset int
+}
+
+// ListGcodes: Uploaded gcode files.
+// GET /api/v2/gcodes, needs the viewer role
+func (c *Client) ListGcodes(ctx context.Context, params *ListGcodesParams) ([]*Gcode, error) {
+	q := url.Values{}
+	if params != nil {
+		if params.Q != "" {
+			q.Set("q", params.Q)
+		}
+		if params.FolderID != "" {
+			q.Set("folder_id", params.FolderID)
+		}
+		if params.Tags != "" {
+			q.Set("tags", params.Tags)
+		}
+		if params.Deleted {
+			q.Set("deleted", "true")
+		}
+		if params.Sort != "" {
+			q.Set("sort", params.Sort)
+		}
+		if params.Limit != 0 {
+			q.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			q.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	var result []*Gcode
+	err := c.do(ctx, "GET", "/api/v2/gcodes", q, nil, &result)
+	return result, err
+}
+
+// UploadGcode: Upload a gcode file.
+// POST /api/v2/gcodes, needs the admin role
+func (c *Client) UploadGcode(ctx context.Context, filename string, file io.Reader) (*Gcode, error) {
+	var result *Gcode
+	err := c.upload(ctx, "/api/v2/gcodes", filename, file, &result)
+	return result, err
+}
+
+// PurgeGcodesParams are the query parameters for PurgeGcodes
+type PurgeGcodesParams struct {
+	// Only files deleted before this time
+	Before time.Time
+}
+
+// PurgeGcodes: Remove deleted gcode files for good.
+// POST /api/v2/gcodes/purge, needs the admin role
+func (c *Client) PurgeGcodes(ctx context.Context, params *PurgeGcodesParams) (*PurgeResult, error) {
+	q := url.Values{}
+	if params != nil {
+		if !params.Before.IsZero() {
+			q.Set("before", params.Before.Format(time.RFC3339))
+		}
+	}
+	var result *PurgeResult
+	err := c.do(ctx, "POST", "/api/v2/gcodes/purge", q, nil, &result)
+	return result, err
+}
+
+// DeleteGcode: Delete a gcode file, it can be restored until it's purged.
+// DELETE /api/v2/gcodes/{id}, needs the admin role
+func (c *Client) DeleteGcode(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v2/gcodes/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetGcode: A gcode file's details.
+// GET /api/v2/gcodes/{id}, needs the viewer role
+func (c *Client) GetGcode(ctx context.Context, id string) (*Gcode, error) {
+	var result *Gcode
+	err := c.do(ctx, "GET", "/api/v2/gcodes/"+url.PathEscape(id), nil, nil, &result)
+	return result, err
+}
+
+// UpdateGcode: Rename a gcode file, move it to another folder or change its tags.
+// PATCH /api/v2/gcodes/{id}, needs the admin role
+func (c *Client) UpdateGcode(ctx context.Context, id string, body *GcodeUpdate) (*Gcode, error) {
+	var result *Gcode
+	err := c.do(ctx, "PATCH", "/api/v2/gcodes/"+url.PathEscape(id), nil, body, &result)
+	return result, err
+}
+
+// GetGcodeContent: Download a gcode file.
+// GET /api/v2/gcodes/{id}/content, needs the viewer role
+func (c *Client) GetGcodeContent(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.download(ctx, "/api/v2/gcodes/"+url.PathEscape(id)+"/content", nil)
+}
+
+// DiffGcodeVersionsParams are the query parameters for DiffGcodeVersions
+type DiffGcodeVersionsParams struct {
+	// Older version, defaults to the one before to
+	From int
+	// Newer version, defaults to the current one
+	To int
+}
+
+// DiffGcodeVersions: How the slicer settings changed between two versions.
+// GET /api/v2/gcodes/{id}/diff, needs the viewer role
+func (c *Client) DiffGcodeVersions(ctx context.Context, id string, params *DiffGcodeVersionsParams) (*SettingsDiff, error) {
+	q := url.Values{}
+	if params != nil {
+		if params.From != 0 {
+			q.Set("from", strconv.Itoa(params.From))
+		}
+		if params.To != 0 {
+			q.Set("to", strconv.Itoa(params.To))
+		}
+	}
+	var result *SettingsDiff
+	err := c.do(ctx, "GET", "/api/v2/gcodes/"+url.PathEscape(id)+"/diff", q, nil, &result)
+	return result, err
+}
+
+// RestoreGcode: Restore a deleted gcode file.
+// POST /api/v2/gcodes/{id}/restore, needs the admin role
+func (c *Client) RestoreGcode(ctx context.Context, id string) (*Gcode, error) {
+	var result *Gcode
+	err := c.do(ctx, "POST", "/api/v2/gcodes/"+url.PathEscape(id)+"/restore", nil, nil, &result)
+	return result, err
+}
+
+// GetGcodeThumbnail: The largest thumbnail the slicer put in the file, 404 when it had none.
+// GET /api/v2/gcodes/{id}/thumbnail, needs the viewer role
+func (c *Client) GetGcodeThumbnail(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.download(ctx, "/api/v2/gcodes/"+url.PathEscape(id)+"/thumbnail", nil)
+}
+
+// ListGcodeVersions: A gcode file's versions, newest first.
+// GET /api/v2/gcodes/{id}/versions, needs the viewer role
+func (c *Client) ListGcodeVersions(ctx context.Context, id string) ([]*VersionInfo, error) {
+	var result []*VersionInfo
+	err := c.do(ctx, "GET", "/api/v2/gcodes/"+url.PathEscape(id)+"/versions", nil, nil, &result)
+	return result, err
+}
+
+// GetGcodeVersionContent: Download a version of a gcode file, agents can download the version loaded onto their printer.
+// GET /api/v2/gcodes/{id}/versions/{version}/content, needs the viewer role
+func (c *Client) GetGcodeVersionContent(ctx context.Context, id string, version string) (io.ReadCloser, error) {
+	return c.download(ctx, "/api/v2/gcodes/"+url.PathEscape(id)+"/versions/"+url.PathEscape(version)+"/content", nil)
+}
+
+// ListPrinters: Registered printers with their status.
+// GET /api/v2/printers, needs the viewer role
+func (c *Client) ListPrinters(ctx context.Context) ([]*PrinterResource, error) {
+	var result []*PrinterResource
+	err := c.do(ctx, "GET", "/api/v2/printers", nil, nil, &result)
+	return result, err
+}
+
+// CreatePrinter: Register a printer.
+// POST /api/v2/printers, needs the admin role
+func (c *Client) CreatePrinter(ctx context.Context, body *PrinterRequest) (*Printer, error) {
+	var result *Printer
+	err := c.do(ctx, "POST", "/api/v2/printers", nil, body, &result)
+	return result, err
+}
+
+// GetPrinter: A printer with its status.
+// GET /api/v2/printers/{id}, needs the viewer role
+func (c *Client) GetPrinter(ctx context.Context, id string) (*PrinterResource, error) {
+	var result *PrinterResource
+	err := c.do(ctx, "GET", "/api/v2/printers/"+url.PathEscape(id), nil, nil, &result)
+	return result, err
+}
+
+// SendPrinterCommand: Send the printer a command.
+// POST /api/v2/printers/{id}/commands, needs the operator role
+func (c *Client) SendPrinterCommand(ctx context.Context, id string, body *CommandRequest) error {
+	return c.do(ctx, "POST", "/api/v2/printers/"+url.PathEscape(id)+"/commands", nil, body, nil)
+}
+
+// ListPrinterJobs: The file loaded onto the printer, if any.
+// GET /api/v2/printers/{id}/jobs, needs the viewer role
+func (c *Client) ListPrinterJobs(ctx context.Context, id string) ([]*Job, error) {
+	var result []*Job
+	err := c.do(ctx, "GET", "/api/v2/printers/"+url.PathEscape(id)+"/jobs", nil, nil, &result)
+	return result, err
+}
+
+// LoadPrinterJob: Load a gcode file onto the printer.
+// POST /api/v2/printers/{id}/jobs, needs the operator role
+func (c *Client) LoadPrinterJob(ctx context.Context, id string, body *JobRequest) (*Job, error) {
+	var result *Job
+	err := c.do(ctx, "POST", "/api/v2/printers/"+url.PathEscape(id)+"/jobs", nil, body, &result)
+	return result, err
+}
+
+// CreateUpload: Start a resumable upload, send the file in chunks then complete it.
+// POST /api/v2/uploads, needs the admin role
+func (c *Client) CreateUpload(ctx context.Context, body *UploadRequest) (*Upload, error) {
+	var result *Upload
+	err := c.do(ctx, "POST", "/api/v2/uploads", nil, body, &result)
+	return result, err
+}
+
+// DeleteUpload: Abandon a resumable upload.
+// DELETE /api/v2/uploads/{id}, needs the admin role
+func (c *Client) DeleteUpload(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/v2/uploads/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetUpload: Where a resumable upload is up to, received_bytes is where the next chunk starts.
+// GET /api/v2/uploads/{id}, needs the admin role
+func (c *Client) GetUpload(ctx context.Context, id string) (*Upload, error) {
+	var result *Upload
+	err := c.do(ctx, "GET", "/api/v2/uploads/"+url.PathEscape(id), nil, nil, &result)
+	return result, err
+}
+
+// UploadChunk: Send the next chunk of a resumable upload.
+// PATCH /api/v2/uploads/{id}, needs the admin role
+func (c *Client) UploadChunk(ctx context.Context, id string, uploadOffset int, body io.Reader) (*Upload, error) {
+	h := http.Header{}
+	h.Set("Upload-Offset", strconv.Itoa(uploadOffset))
+	var result *Upload
+	err := c.stream(ctx, "PATCH", "/api/v2/uploads/"+url.PathEscape(id), h, body, &result)
+	return result, err
+}
+
+// CompleteUpload: Check a resumable upload against its size and hash and add it to the library.
+// POST /api/v2/uploads/{id}/complete, needs the admin role
+func (c *Client) CompleteUpload(ctx context.Context, id string) (*Gcode, error) {
+	var result *Gcode
+	err := c.do(ctx, "POST", "/api/v2/uploads/"+url.PathEscape(id)+"/complete", nil, nil, &result)
+	return result, err
+}
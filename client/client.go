@@ -0,0 +1,155 @@
+// Package client drives the server's REST API from Go. The operations in api.go are generated
+// from the server's OpenAPI document, run go generate here after changing the API.
+package client
+
+//go:generate go run ./gen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the API as the user an API token belongs to
+type Client struct {
+	BaseURL    string // Where the server is, http://localhost:8080 for example
+	Token      string // API token issued to the user, sent as a bearer token
+	HTTPClient *http.Client
+}
+
+// New client for the server at baseURL
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Error is an error response from the API
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// send makes the request with the headers given, turning error responses into an *Error
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader) (*http.Response, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	apiErr := &Error{Status: resp.StatusCode, Code: "unknown", Message: resp.Status}
+	result := &ErrorResponse{}
+	if json.NewDecoder(resp.Body).Decode(result) == nil && result.Error != nil {
+		apiErr.Code = result.Error.Code
+		apiErr.Message = result.Error.Message
+	}
+	return nil, apiErr
+}
+
+// do sends body as JSON and decodes the payload of the response into result, either can be nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, result interface{}) error {
+	var r io.Reader
+	header := http.Header{}
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+		header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.send(ctx, method, path, query, header, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodePayload(resp.Body, result)
+}
+
+// upload sends the file as a multipart form and decodes the payload of the response into result
+func (c *Client) upload(ctx context.Context, path, filename string, file io.Reader, result interface{}) error {
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		part, err := form.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	resp, err := c.send(ctx, http.MethodPost, path, nil, http.Header{"Content-Type": {form.FormDataContentType()}}, pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	defer resp.Body.Close()
+	return decodePayload(resp.Body, result)
+}
+
+// stream sends body as raw bytes with the headers given and decodes the payload of the response into result
+func (c *Client) stream(ctx context.Context, method, path string, header http.Header, body io.Reader, result interface{}) error {
+	header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.send(ctx, method, path, nil, header, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodePayload(resp.Body, result)
+}
+
+// download returns the response body for the caller to read and close
+func (c *Client) download(ctx context.Context, path string, query url.Values) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, http.MethodGet, path, query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// decodePayload unwraps the API's response envelope
+func decodePayload(r io.Reader, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	envelope := &struct {
+		Payload json.RawMessage `json:"payload"`
+	}{}
+	err := json.NewDecoder(r).Decode(envelope)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(envelope.Payload, result)
+}
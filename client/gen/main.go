@@ -0,0 +1,302 @@
+// Command gen writes client/api.go from the server's OpenAPI document
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go-3dprint/server"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// skipTags are operations that aren't for API clients
+var skipTags = map[string]bool{"meta": true, "agent": true}
+
+// initialisms are kept upper case in Go names
+var initialisms = map[string]bool{"id": true, "url": true, "uuid": true, "api": true, "json": true, "csv": true, "rx": true}
+
+func main() {
+	doc := server.NewOpenAPI()
+	g := &generator{doc: doc}
+	names := []string{}
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.structType(name, doc.Components.Schemas[name])
+	}
+
+	paths := []string{}
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		methods := []string{}
+		for method := range doc.Paths[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			op := doc.Paths[path][method]
+			if op.Deprecated || (len(op.Tags) > 0 && skipTags[op.Tags[0]]) {
+				continue
+			}
+			g.operation(strings.ToUpper(method), path, op)
+		}
+	}
+
+	// Only import what the declarations use
+	decls := g.buf.String()
+	header := "// Code generated by go run ./gen from the server's OpenAPI document. DO NOT EDIT.\n\npackage client\n\nimport (\n"
+	for _, imp := range []string{"context", "encoding/json", "io", "net/http", "net/url", "strconv", "time"} {
+		pkg := imp[strings.LastIndex(imp, "/")+1:]
+		if strings.Contains(decls, pkg+".") {
+			header += fmt.Sprintf("%q\n", imp)
+		}
+	}
+	header += ")\n\n"
+
+	src, err := format.Source([]byte(header + decls))
+	if err != nil {
+		log.Fatalf("formatting generated code: %v\n%s", err, header+decls)
+	}
+	err = ioutil.WriteFile("api.go", src, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	doc *server.OpenAPIDoc
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// goName turns a JSON or operation name into an exported Go name
+func goName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || r == '.' })
+	result := ""
+	for _, w := range words {
+		// Split camelCase too, so sessionId becomes SessionID
+		start := 0
+		for i, r := range w {
+			if i > 0 && unicode.IsUpper(r) {
+				result += word(w[start:i])
+				start = i
+			}
+		}
+		result += word(w[start:])
+	}
+	return result
+}
+
+func word(w string) string {
+	if initialisms[strings.ToLower(w)] {
+		return strings.ToUpper(w)
+	}
+	return strings.ToUpper(w[:1]) + w[1:]
+}
+
+// argName turns a parameter name into an unexported Go name, id rather than iD
+func argName(s string) string {
+	name := goName(s)
+	if initialisms[strings.ToLower(name)] {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// goType is the Go type a schema decodes into
+func (g *generator) goType(s *server.Schema) string {
+	if s.Ref != "" {
+		return "*" + s.RefName()
+	}
+	t := ""
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			t = "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		default:
+			t = "string"
+		}
+	case "integer":
+		t = "int"
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		return "[]" + g.goType(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + g.goType(s.AdditionalProperties)
+		}
+		return "json.RawMessage"
+	default:
+		return "json.RawMessage"
+	}
+	if s.Nullable {
+		return "*" + t
+	}
+	return t
+}
+
+func (g *generator) structType(name string, s *server.Schema) {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	props := []string{}
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	g.printf("// %s is a schema from the OpenAPI document\ntype %s struct {\n", name, name)
+	for _, p := range props {
+		tag := p
+		if !required[p] {
+			tag += ",omitempty"
+		}
+		g.printf("%s %s `json:\"%s\"`\n", goName(p), g.goType(s.Properties[p]), tag)
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) operation(method, path string, op *server.Operation) {
+	name := goName(op.OperationID)
+	args := []string{"ctx context.Context"}
+	pathExpr := "\"" + path + "\""
+	query := []*server.Parameter{}
+	header := []*server.Parameter{}
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			arg := argName(p.Name)
+			args = append(args, arg+" string")
+			pathExpr = strings.Replace(pathExpr, "{"+p.Name+"}", "\" + url.PathEscape("+arg+") + \"", 1)
+		case "query":
+			query = append(query, p)
+		case "header":
+			header = append(header, p)
+		}
+	}
+	for _, p := range header {
+		args = append(args, argName(p.Name)+" "+g.goType(p.Schema))
+	}
+	pathExpr = strings.TrimSuffix(pathExpr, " + \"\"")
+	if len(query) > 0 {
+		g.printf("// %sParams are the query parameters for %s\ntype %sParams struct {\n", name, name, name)
+		for _, p := range query {
+			if p.Description != "" {
+				g.printf("// %s\n", p.Description)
+			}
+			g.printf("%s %s\n", goName(p.Name), g.goType(p.Schema))
+		}
+		g.printf("}\n\n")
+		args = append(args, "params *"+name+"Params")
+	}
+
+	body := "nil"
+	upload := false
+	stream := false
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["application/json"]; ok {
+			args = append(args, "body "+g.goType(media.Schema))
+			body = "body"
+		}
+		if _, ok := op.RequestBody.Content["multipart/form-data"]; ok {
+			args = append(args, "filename string", "file io.Reader")
+			upload = true
+		}
+		if _, ok := op.RequestBody.Content["application/octet-stream"]; ok {
+			args = append(args, "body io.Reader")
+			stream = true
+		}
+	}
+
+	result := ""
+	download := false
+	if resp := op.Responses["200"]; resp != nil {
+		if media, ok := resp.Content["application/json"]; ok {
+			result = g.goType(media.Schema.Properties["payload"])
+		}
+		for contentType := range resp.Content {
+			// Files and images come back as they are
+			if contentType != "application/json" {
+				download = true
+			}
+		}
+	}
+
+	g.printf("// %s: %s.\n// %s %s", name, op.Summary, method, path)
+	if op.Role != "" {
+		g.printf(", needs the %s role", op.Role)
+	}
+	g.printf("\n")
+	switch {
+	case download:
+		g.printf("func (c *Client) %s(%s) (io.ReadCloser, error) {\n", name, strings.Join(args, ", "))
+	case result != "":
+		g.printf("func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), result)
+	default:
+		g.printf("func (c *Client) %s(%s) error {\n", name, strings.Join(args, ", "))
+	}
+
+	q := "nil"
+	if len(query) > 0 {
+		q = "q"
+		g.printf("q := url.Values{}\n")
+		g.printf("if params != nil {\n")
+		for _, p := range query {
+			field := "params." + goName(p.Name)
+			switch g.goType(p.Schema) {
+			case "int":
+				g.printf("if %s != 0 {\nq.Set(%q, strconv.Itoa(%s))\n}\n", field, p.Name, field)
+			case "bool":
+				g.printf("if %s {\nq.Set(%q, \"true\")\n}\n", field, p.Name)
+			case "time.Time":
+				g.printf("if !%s.IsZero() {\nq.Set(%q, %s.Format(time.RFC3339))\n}\n", field, p.Name, field)
+			default:
+				g.printf("if %s != \"\" {\nq.Set(%q, %s)\n}\n", field, p.Name, field)
+			}
+		}
+		g.printf("}\n")
+	}
+
+	if stream {
+		g.printf("h := http.Header{}\n")
+		for _, p := range header {
+			arg := argName(p.Name)
+			if g.goType(p.Schema) == "int" {
+				arg = "strconv.Itoa(" + arg + ")"
+			}
+			g.printf("h.Set(%q, %s)\n", p.Name, arg)
+		}
+	}
+
+	switch {
+	case download:
+		g.printf("return c.download(ctx, %s, %s)\n", pathExpr, q)
+	case upload:
+		g.printf("var result %s\nerr := c.upload(ctx, %s, filename, file, &result)\nreturn result, err\n", result, pathExpr)
+	case stream:
+		g.printf("var result %s\nerr := c.stream(ctx, %q, %s, h, body, &result)\nreturn result, err\n", result, method, pathExpr)
+	case result != "":
+		g.printf("var result %s\nerr := c.do(ctx, %q, %s, %s, %s, &result)\nreturn result, err\n", result, method, pathExpr, q, body)
+	default:
+		g.printf("return c.do(ctx, %q, %s, %s, %s, nil)\n", method, pathExpr, q, body)
+	}
+	g.printf("}\n\n")
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ChunkSize is how much UploadFile sends in each request
+const ChunkSize = 4 << 20
+
+// UploadOptions are where UploadFile puts the file
+type UploadOptions struct {
+	FolderID   string
+	Tags       []string
+	NewVersion bool // Add a version to the file with the same name, see UploadRequest
+	Retries    int  // Failed chunks in a row before giving up
+}
+
+// UploadFile sends a file through a resumable upload. When a chunk fails it asks the server
+// where the upload got to and carries on from there, the file is hashed so the server can check it.
+func (c *Client) UploadFile(ctx context.Context, path string, opts *UploadOptions) (*Gcode, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := c.CreateUpload(ctx, &UploadRequest{
+		Name:       filepath.Base(path),
+		SizeBytes:  int(size),
+		Sha256:     hex.EncodeToString(h.Sum(nil)),
+		FolderID:   opts.FolderID,
+		Tags:       opts.Tags,
+		NewVersion: opts.NewVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, ChunkSize)
+	offset := 0
+	failures := 0
+	for offset < int(size) {
+		n, err := f.ReadAt(buf, int64(offset))
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		result, err := c.UploadChunk(ctx, upload.ID, offset, bytes.NewReader(buf[:n]))
+		if err == nil {
+			offset = result.ReceivedBytes
+			failures = 0
+			continue
+		}
+		failures++
+		var apiErr *Error
+		if failures > opts.Retries || ctx.Err() != nil || (errors.As(err, &apiErr) && apiErr.Status != http.StatusConflict && apiErr.Status < 500) {
+			return nil, err
+		}
+		time.Sleep(time.Duration(failures) * time.Second)
+		// The chunk may have landed before the connection dropped
+		current, err := c.GetUpload(ctx, upload.ID)
+		if err == nil {
+			offset = current.ReceivedBytes
+		}
+	}
+	return c.CompleteUpload(ctx, upload.ID)
+}
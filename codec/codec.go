@@ -0,0 +1,126 @@
+// Package codec compresses gcode for storage and transfer. The codec names are also the HTTP content codings
+// used for them, so a stored blob can be sent as it is to a client that accepts its codec.
+package codec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Identity is data stored or sent as it is
+const Identity = "identity"
+
+// Gzip is data compressed with gzip
+const Gzip = "gzip"
+
+// Zstd is data compressed with Zstandard
+const Zstd = "zstd"
+
+// Encode compresses data with the codec
+func Encode(codec string, data []byte) ([]byte, error) {
+	switch codec {
+	case Identity:
+		return data, nil
+	case Gzip:
+		var buf bytes.Buffer
+		w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return nil, err
+		}
+		err = w.Close()
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Zstd:
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+		if err != nil {
+			return nil, err
+		}
+		defer enc.Close()
+		return enc.EncodeAll(data, nil), nil
+	}
+	return nil, fmt.Errorf("unknown codec %q", codec)
+}
+
+// Decode reads everything from r, decompressing it with the codec. Data that decompresses to more
+// than limit bytes is refused, so a small file can't fill memory, 0 is no limit.
+func Decode(codec string, r io.Reader, limit int64) ([]byte, error) {
+	switch codec {
+	case Identity:
+	case Gzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	case Zstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unknown codec %q", codec)
+	}
+	if limit <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("data decompresses to more than %d bytes", limit)
+	}
+	return data, nil
+}
+
+// IsGzip reports whether data starts with the gzip magic number
+func IsGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// Accepts reports whether an Accept-Encoding header allows the codec. Identity is always allowed.
+// The codec named on its own takes precedence over *.
+func Accepts(header, codec string) bool {
+	if codec == Identity {
+		return true
+	}
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name != codec && name != "*" {
+			continue
+		}
+		accepted := true
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil || q == 0 {
+					accepted = false
+				}
+			}
+		}
+		if name == codec {
+			return accepted
+		}
+		wildcard = accepted
+	}
+	return wildcard
+}
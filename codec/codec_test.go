@@ -0,0 +1,95 @@
+package codec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAccepts(t *testing.T) {
+	tests := []struct {
+		header string
+		codec  string
+		want   bool
+	}{
+		{"", Identity, true},
+		{"", Zstd, false},
+		{"gzip, deflate, br, zstd", Zstd, true},
+		{"gzip, deflate", Zstd, false},
+		{"GZIP", Gzip, true},
+		{"zstd;q=0.5", Zstd, true},
+		{"zstd; q=0", Zstd, false},
+		{"zstd;q=nope", Zstd, false},
+		{"*", Zstd, true},
+		{"*;q=0", Gzip, false},
+		{"*;q=0, zstd", Zstd, true},
+		{"zstd;q=0, *", Zstd, false},
+		{"zstdx", Zstd, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header+" "+tt.codec, func(t *testing.T) {
+			got := Accepts(tt.header, tt.codec)
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	gcode := []byte(strings.Repeat("G1 X10 Y10 E0.5\n", 100))
+	tests := []struct {
+		name    string
+		codec   string
+		data    func(t *testing.T) []byte
+		limit   int64
+		wantErr string
+	}{
+		{name: "identity", codec: Identity, data: encoded(Identity, gcode)},
+		{name: "gzip", codec: Gzip, data: encoded(Gzip, gcode)},
+		{name: "zstd", codec: Zstd, data: encoded(Zstd, gcode)},
+		{name: "exactly the limit", codec: Zstd, data: encoded(Zstd, gcode), limit: int64(len(gcode))},
+		{name: "over the limit", codec: Gzip, data: encoded(Gzip, gcode), limit: int64(len(gcode)) - 1, wantErr: "more than"},
+		{name: "identity over the limit", codec: Identity, data: encoded(Identity, gcode), limit: 10, wantErr: "more than 10 bytes"},
+		{name: "not gzip", codec: Gzip, data: encoded(Identity, gcode), wantErr: "invalid header"},
+		{name: "truncated gzip", codec: Gzip, data: truncated(encoded(Gzip, gcode)), wantErr: "unexpected EOF"},
+		{name: "not zstd", codec: Zstd, data: encoded(Identity, gcode), wantErr: "magic"},
+		{name: "unknown codec", codec: "br", data: encoded(Identity, gcode), wantErr: "unknown codec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.codec, bytes.NewReader(tt.data(t)), tt.limit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, gcode) {
+				t.Errorf("got %d bytes back, want the %d encoded", len(got), len(gcode))
+			}
+		})
+	}
+}
+
+// encoded returns data compressed with the codec
+func encoded(codec string, data []byte) func(t *testing.T) []byte {
+	return func(t *testing.T) []byte {
+		b, err := Encode(codec, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+}
+
+// truncated cuts the second half off the data
+func truncated(data func(t *testing.T) []byte) func(t *testing.T) []byte {
+	return func(t *testing.T) []byte {
+		b := data(t)
+		return b[:len(b)/2]
+	}
+}
@@ -0,0 +1,1188 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// AgentToken is an object representing the database table.
+type AgentToken struct {
+	ID         string    `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	PrinterID  string    `db:"printer_id" boil:"printer_id" json:"printer_id" toml:"printer_id" yaml:"printer_id"`
+	TokenHash  string    `db:"token_hash" boil:"token_hash" json:"token_hash" toml:"token_hash" yaml:"token_hash"`
+	LastUsedAt null.Time `db:"last_used_at" boil:"last_used_at" json:"last_used_at,omitempty" toml:"last_used_at" yaml:"last_used_at,omitempty"`
+	RevokedAt  null.Time `db:"revoked_at" boil:"revoked_at" json:"revoked_at,omitempty" toml:"revoked_at" yaml:"revoked_at,omitempty"`
+	CreatedAt  time.Time `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *agentTokenR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L agentTokenL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var AgentTokenColumns = struct {
+	ID         string
+	PrinterID  string
+	TokenHash  string
+	LastUsedAt string
+	RevokedAt  string
+	CreatedAt  string
+}{
+	ID:         "id",
+	PrinterID:  "printer_id",
+	TokenHash:  "token_hash",
+	LastUsedAt: "last_used_at",
+	RevokedAt:  "revoked_at",
+	CreatedAt:  "created_at",
+}
+
+// Generated where
+
+type whereHelperstring struct{ field string }
+
+func (w whereHelperstring) EQ(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelperstring) NEQ(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelperstring) LT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelperstring) LTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelperstring) GT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelperstring) GTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+func (w whereHelperstring) IN(slice []string) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+type whereHelpernull_Time struct{ field string }
+
+func (w whereHelpernull_Time) EQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Time) NEQ(x null.Time) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+func (w whereHelpernull_Time) LT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Time) LTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Time) GT(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Time) GTE(x null.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+type whereHelpertime_Time struct{ field string }
+
+func (w whereHelpertime_Time) EQ(x time.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelpertime_Time) NEQ(x time.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelpertime_Time) LT(x time.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpertime_Time) LTE(x time.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpertime_Time) GT(x time.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpertime_Time) GTE(x time.Time) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+var AgentTokenWhere = struct {
+	ID         whereHelperstring
+	PrinterID  whereHelperstring
+	TokenHash  whereHelperstring
+	LastUsedAt whereHelpernull_Time
+	RevokedAt  whereHelpernull_Time
+	CreatedAt  whereHelpertime_Time
+}{
+	ID:         whereHelperstring{field: "\"agent_tokens\".\"id\""},
+	PrinterID:  whereHelperstring{field: "\"agent_tokens\".\"printer_id\""},
+	TokenHash:  whereHelperstring{field: "\"agent_tokens\".\"token_hash\""},
+	LastUsedAt: whereHelpernull_Time{field: "\"agent_tokens\".\"last_used_at\""},
+	RevokedAt:  whereHelpernull_Time{field: "\"agent_tokens\".\"revoked_at\""},
+	CreatedAt:  whereHelpertime_Time{field: "\"agent_tokens\".\"created_at\""},
+}
+
+// AgentTokenRels is where relationship names are stored.
+var AgentTokenRels = struct {
+	Printer string
+}{
+	Printer: "Printer",
+}
+
+// agentTokenR is where relationships are stored.
+type agentTokenR struct {
+	Printer *Printer `db:"Printer" boil:"Printer" json:"Printer" toml:"Printer" yaml:"Printer"`
+}
+
+// NewStruct creates a new relationship struct
+func (*agentTokenR) NewStruct() *agentTokenR {
+	return &agentTokenR{}
+}
+
+// agentTokenL is where Load methods for each relationship are stored.
+type agentTokenL struct{}
+
+var (
+	agentTokenAllColumns            = []string{"id", "printer_id", "token_hash", "last_used_at", "revoked_at", "created_at"}
+	agentTokenColumnsWithoutDefault = []string{"printer_id", "token_hash", "last_used_at", "revoked_at"}
+	agentTokenColumnsWithDefault    = []string{"id", "created_at"}
+	agentTokenPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// AgentTokenSlice is an alias for a slice of pointers to AgentToken.
+	// This should generally be used opposed to []AgentToken.
+	AgentTokenSlice []*AgentToken
+	// AgentTokenHook is the signature for custom AgentToken hook methods
+	AgentTokenHook func(boil.Executor, *AgentToken) error
+
+	agentTokenQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	agentTokenType                 = reflect.TypeOf(&AgentToken{})
+	agentTokenMapping              = queries.MakeStructMapping(agentTokenType)
+	agentTokenPrimaryKeyMapping, _ = queries.BindMapping(agentTokenType, agentTokenMapping, agentTokenPrimaryKeyColumns)
+	agentTokenInsertCacheMut       sync.RWMutex
+	agentTokenInsertCache          = make(map[string]insertCache)
+	agentTokenUpdateCacheMut       sync.RWMutex
+	agentTokenUpdateCache          = make(map[string]updateCache)
+	agentTokenUpsertCacheMut       sync.RWMutex
+	agentTokenUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var agentTokenBeforeInsertHooks []AgentTokenHook
+var agentTokenBeforeUpdateHooks []AgentTokenHook
+var agentTokenBeforeDeleteHooks []AgentTokenHook
+var agentTokenBeforeUpsertHooks []AgentTokenHook
+
+var agentTokenAfterInsertHooks []AgentTokenHook
+var agentTokenAfterSelectHooks []AgentTokenHook
+var agentTokenAfterUpdateHooks []AgentTokenHook
+var agentTokenAfterDeleteHooks []AgentTokenHook
+var agentTokenAfterUpsertHooks []AgentTokenHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *AgentToken) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *AgentToken) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *AgentToken) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *AgentToken) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *AgentToken) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *AgentToken) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *AgentToken) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *AgentToken) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *AgentToken) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range agentTokenAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddAgentTokenHook registers your hook function for all future operations.
+func AddAgentTokenHook(hookPoint boil.HookPoint, agentTokenHook AgentTokenHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		agentTokenBeforeInsertHooks = append(agentTokenBeforeInsertHooks, agentTokenHook)
+	case boil.BeforeUpdateHook:
+		agentTokenBeforeUpdateHooks = append(agentTokenBeforeUpdateHooks, agentTokenHook)
+	case boil.BeforeDeleteHook:
+		agentTokenBeforeDeleteHooks = append(agentTokenBeforeDeleteHooks, agentTokenHook)
+	case boil.BeforeUpsertHook:
+		agentTokenBeforeUpsertHooks = append(agentTokenBeforeUpsertHooks, agentTokenHook)
+	case boil.AfterInsertHook:
+		agentTokenAfterInsertHooks = append(agentTokenAfterInsertHooks, agentTokenHook)
+	case boil.AfterSelectHook:
+		agentTokenAfterSelectHooks = append(agentTokenAfterSelectHooks, agentTokenHook)
+	case boil.AfterUpdateHook:
+		agentTokenAfterUpdateHooks = append(agentTokenAfterUpdateHooks, agentTokenHook)
+	case boil.AfterDeleteHook:
+		agentTokenAfterDeleteHooks = append(agentTokenAfterDeleteHooks, agentTokenHook)
+	case boil.AfterUpsertHook:
+		agentTokenAfterUpsertHooks = append(agentTokenAfterUpsertHooks, agentTokenHook)
+	}
+}
+
+// OneG returns a single agentToken record from the query using the global executor.
+func (q agentTokenQuery) OneG() (*AgentToken, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single agentToken record from the query.
+func (q agentTokenQuery) One(exec boil.Executor) (*AgentToken, error) {
+	o := &AgentToken{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for agent_tokens")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all AgentToken records from the query using the global executor.
+func (q agentTokenQuery) AllG() (AgentTokenSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all AgentToken records from the query.
+func (q agentTokenQuery) All(exec boil.Executor) (AgentTokenSlice, error) {
+	var o []*AgentToken
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to AgentToken slice")
+	}
+
+	if len(agentTokenAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all AgentToken records in the query, and panics on error.
+func (q agentTokenQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all AgentToken records in the query.
+func (q agentTokenQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count agent_tokens rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q agentTokenQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q agentTokenQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if agent_tokens exists")
+	}
+
+	return count > 0, nil
+}
+
+// Printer pointed to by the foreign key.
+func (o *AgentToken) Printer(mods ...qm.QueryMod) printerQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.PrinterID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Printers(queryMods...)
+	queries.SetFrom(query.Query, "\"printers\"")
+
+	return query
+}
+
+// LoadPrinter allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (agentTokenL) LoadPrinter(e boil.Executor, singular bool, maybeAgentToken interface{}, mods queries.Applicator) error {
+	var slice []*AgentToken
+	var object *AgentToken
+
+	if singular {
+		object = maybeAgentToken.(*AgentToken)
+	} else {
+		slice = *maybeAgentToken.(*[]*AgentToken)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &agentTokenR{}
+		}
+		args = append(args, object.PrinterID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &agentTokenR{}
+			}
+
+			for _, a := range args {
+				if a == obj.PrinterID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.PrinterID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`printers`),
+		qm.WhereIn(`printers.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Printer")
+	}
+
+	var resultSlice []*Printer
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Printer")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for printers")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for printers")
+	}
+
+	if len(agentTokenAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Printer = foreign
+		if foreign.R == nil {
+			foreign.R = &printerR{}
+		}
+		foreign.R.AgentTokens = append(foreign.R.AgentTokens, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.PrinterID == foreign.ID {
+				local.R.Printer = foreign
+				if foreign.R == nil {
+					foreign.R = &printerR{}
+				}
+				foreign.R.AgentTokens = append(foreign.R.AgentTokens, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetPrinterG of the agentToken to the related item.
+// Sets o.R.Printer to related.
+// Adds o to related.R.AgentTokens.
+// Uses the global database handle.
+func (o *AgentToken) SetPrinterG(insert bool, related *Printer) error {
+	return o.SetPrinter(boil.GetDB(), insert, related)
+}
+
+// SetPrinter of the agentToken to the related item.
+// Sets o.R.Printer to related.
+// Adds o to related.R.AgentTokens.
+func (o *AgentToken) SetPrinter(exec boil.Executor, insert bool, related *Printer) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"agent_tokens\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"printer_id"}),
+		strmangle.WhereClause("\"", "\"", 2, agentTokenPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.PrinterID = related.ID
+	if o.R == nil {
+		o.R = &agentTokenR{
+			Printer: related,
+		}
+	} else {
+		o.R.Printer = related
+	}
+
+	if related.R == nil {
+		related.R = &printerR{
+			AgentTokens: AgentTokenSlice{o},
+		}
+	} else {
+		related.R.AgentTokens = append(related.R.AgentTokens, o)
+	}
+
+	return nil
+}
+
+// AgentTokens retrieves all the records using an executor.
+func AgentTokens(mods ...qm.QueryMod) agentTokenQuery {
+	mods = append(mods, qm.From("\"agent_tokens\""))
+	return agentTokenQuery{NewQuery(mods...)}
+}
+
+// FindAgentTokenG retrieves a single record by ID.
+func FindAgentTokenG(iD string, selectCols ...string) (*AgentToken, error) {
+	return FindAgentToken(boil.GetDB(), iD, selectCols...)
+}
+
+// FindAgentToken retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindAgentToken(exec boil.Executor, iD string, selectCols ...string) (*AgentToken, error) {
+	agentTokenObj := &AgentToken{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"agent_tokens\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, agentTokenObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from agent_tokens")
+	}
+
+	return agentTokenObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *AgentToken) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *AgentToken) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no agent_tokens provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(agentTokenColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	agentTokenInsertCacheMut.RLock()
+	cache, cached := agentTokenInsertCache[key]
+	agentTokenInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			agentTokenAllColumns,
+			agentTokenColumnsWithDefault,
+			agentTokenColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(agentTokenType, agentTokenMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(agentTokenType, agentTokenMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"agent_tokens\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"agent_tokens\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into agent_tokens")
+	}
+
+	if !cached {
+		agentTokenInsertCacheMut.Lock()
+		agentTokenInsertCache[key] = cache
+		agentTokenInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single AgentToken record using the global executor.
+// See Update for more documentation.
+func (o *AgentToken) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the AgentToken.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *AgentToken) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	agentTokenUpdateCacheMut.RLock()
+	cache, cached := agentTokenUpdateCache[key]
+	agentTokenUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			agentTokenAllColumns,
+			agentTokenPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update agent_tokens, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"agent_tokens\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, agentTokenPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(agentTokenType, agentTokenMapping, append(wl, agentTokenPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update agent_tokens row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for agent_tokens")
+	}
+
+	if !cached {
+		agentTokenUpdateCacheMut.Lock()
+		agentTokenUpdateCache[key] = cache
+		agentTokenUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q agentTokenQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q agentTokenQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for agent_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for agent_tokens")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o AgentTokenSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o AgentTokenSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), agentTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"agent_tokens\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, agentTokenPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in agentToken slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all agentToken")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *AgentToken) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *AgentToken) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no agent_tokens provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(agentTokenColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	agentTokenUpsertCacheMut.RLock()
+	cache, cached := agentTokenUpsertCache[key]
+	agentTokenUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			agentTokenAllColumns,
+			agentTokenColumnsWithDefault,
+			agentTokenColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			agentTokenAllColumns,
+			agentTokenPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert agent_tokens, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(agentTokenPrimaryKeyColumns))
+			copy(conflict, agentTokenPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"agent_tokens\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(agentTokenType, agentTokenMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(agentTokenType, agentTokenMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert agent_tokens")
+	}
+
+	if !cached {
+		agentTokenUpsertCacheMut.Lock()
+		agentTokenUpsertCache[key] = cache
+		agentTokenUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single AgentToken record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *AgentToken) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single AgentToken record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *AgentToken) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no AgentToken provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), agentTokenPrimaryKeyMapping)
+	sql := "DELETE FROM \"agent_tokens\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from agent_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for agent_tokens")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q agentTokenQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q agentTokenQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no agentTokenQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from agent_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for agent_tokens")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o AgentTokenSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o AgentTokenSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(agentTokenBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), agentTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"agent_tokens\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, agentTokenPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from agentToken slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for agent_tokens")
+	}
+
+	if len(agentTokenAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *AgentToken) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no AgentToken provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *AgentToken) Reload(exec boil.Executor) error {
+	ret, err := FindAgentToken(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *AgentTokenSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty AgentTokenSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *AgentTokenSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := AgentTokenSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), agentTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"agent_tokens\".* FROM \"agent_tokens\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, agentTokenPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in AgentTokenSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// AgentTokenExistsG checks if the AgentToken row exists.
+func AgentTokenExistsG(iD string) (bool, error) {
+	return AgentTokenExists(boil.GetDB(), iD)
+}
+
+// AgentTokenExists checks if the AgentToken row exists.
+func AgentTokenExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"agent_tokens\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if agent_tokens exists")
+	}
+
+	return exists, nil
+}
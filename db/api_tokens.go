@@ -0,0 +1,1126 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// APIToken is an object representing the database table.
+type APIToken struct {
+	ID         string    `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	UserID     string    `db:"user_id" boil:"user_id" json:"user_id" toml:"user_id" yaml:"user_id"`
+	Name       string    `db:"name" boil:"name" json:"name" toml:"name" yaml:"name"`
+	TokenHash  string    `db:"token_hash" boil:"token_hash" json:"token_hash" toml:"token_hash" yaml:"token_hash"`
+	LastUsedAt null.Time `db:"last_used_at" boil:"last_used_at" json:"last_used_at,omitempty" toml:"last_used_at" yaml:"last_used_at,omitempty"`
+	RevokedAt  null.Time `db:"revoked_at" boil:"revoked_at" json:"revoked_at,omitempty" toml:"revoked_at" yaml:"revoked_at,omitempty"`
+	CreatedAt  time.Time `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *apiTokenR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L apiTokenL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var APITokenColumns = struct {
+	ID         string
+	UserID     string
+	Name       string
+	TokenHash  string
+	LastUsedAt string
+	RevokedAt  string
+	CreatedAt  string
+}{
+	ID:         "id",
+	UserID:     "user_id",
+	Name:       "name",
+	TokenHash:  "token_hash",
+	LastUsedAt: "last_used_at",
+	RevokedAt:  "revoked_at",
+	CreatedAt:  "created_at",
+}
+
+// Generated where
+
+var APITokenWhere = struct {
+	ID         whereHelperstring
+	UserID     whereHelperstring
+	Name       whereHelperstring
+	TokenHash  whereHelperstring
+	LastUsedAt whereHelpernull_Time
+	RevokedAt  whereHelpernull_Time
+	CreatedAt  whereHelpertime_Time
+}{
+	ID:         whereHelperstring{field: "\"api_tokens\".\"id\""},
+	UserID:     whereHelperstring{field: "\"api_tokens\".\"user_id\""},
+	Name:       whereHelperstring{field: "\"api_tokens\".\"name\""},
+	TokenHash:  whereHelperstring{field: "\"api_tokens\".\"token_hash\""},
+	LastUsedAt: whereHelpernull_Time{field: "\"api_tokens\".\"last_used_at\""},
+	RevokedAt:  whereHelpernull_Time{field: "\"api_tokens\".\"revoked_at\""},
+	CreatedAt:  whereHelpertime_Time{field: "\"api_tokens\".\"created_at\""},
+}
+
+// APITokenRels is where relationship names are stored.
+var APITokenRels = struct {
+	User string
+}{
+	User: "User",
+}
+
+// apiTokenR is where relationships are stored.
+type apiTokenR struct {
+	User *User `db:"User" boil:"User" json:"User" toml:"User" yaml:"User"`
+}
+
+// NewStruct creates a new relationship struct
+func (*apiTokenR) NewStruct() *apiTokenR {
+	return &apiTokenR{}
+}
+
+// apiTokenL is where Load methods for each relationship are stored.
+type apiTokenL struct{}
+
+var (
+	apiTokenAllColumns            = []string{"id", "user_id", "name", "token_hash", "last_used_at", "revoked_at", "created_at"}
+	apiTokenColumnsWithoutDefault = []string{"user_id", "name", "token_hash", "last_used_at", "revoked_at"}
+	apiTokenColumnsWithDefault    = []string{"id", "created_at"}
+	apiTokenPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// APITokenSlice is an alias for a slice of pointers to APIToken.
+	// This should generally be used opposed to []APIToken.
+	APITokenSlice []*APIToken
+	// APITokenHook is the signature for custom APIToken hook methods
+	APITokenHook func(boil.Executor, *APIToken) error
+
+	apiTokenQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	apiTokenType                 = reflect.TypeOf(&APIToken{})
+	apiTokenMapping              = queries.MakeStructMapping(apiTokenType)
+	apiTokenPrimaryKeyMapping, _ = queries.BindMapping(apiTokenType, apiTokenMapping, apiTokenPrimaryKeyColumns)
+	apiTokenInsertCacheMut       sync.RWMutex
+	apiTokenInsertCache          = make(map[string]insertCache)
+	apiTokenUpdateCacheMut       sync.RWMutex
+	apiTokenUpdateCache          = make(map[string]updateCache)
+	apiTokenUpsertCacheMut       sync.RWMutex
+	apiTokenUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var apiTokenBeforeInsertHooks []APITokenHook
+var apiTokenBeforeUpdateHooks []APITokenHook
+var apiTokenBeforeDeleteHooks []APITokenHook
+var apiTokenBeforeUpsertHooks []APITokenHook
+
+var apiTokenAfterInsertHooks []APITokenHook
+var apiTokenAfterSelectHooks []APITokenHook
+var apiTokenAfterUpdateHooks []APITokenHook
+var apiTokenAfterDeleteHooks []APITokenHook
+var apiTokenAfterUpsertHooks []APITokenHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *APIToken) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *APIToken) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *APIToken) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *APIToken) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *APIToken) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *APIToken) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *APIToken) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *APIToken) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *APIToken) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range apiTokenAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddAPITokenHook registers your hook function for all future operations.
+func AddAPITokenHook(hookPoint boil.HookPoint, apiTokenHook APITokenHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		apiTokenBeforeInsertHooks = append(apiTokenBeforeInsertHooks, apiTokenHook)
+	case boil.BeforeUpdateHook:
+		apiTokenBeforeUpdateHooks = append(apiTokenBeforeUpdateHooks, apiTokenHook)
+	case boil.BeforeDeleteHook:
+		apiTokenBeforeDeleteHooks = append(apiTokenBeforeDeleteHooks, apiTokenHook)
+	case boil.BeforeUpsertHook:
+		apiTokenBeforeUpsertHooks = append(apiTokenBeforeUpsertHooks, apiTokenHook)
+	case boil.AfterInsertHook:
+		apiTokenAfterInsertHooks = append(apiTokenAfterInsertHooks, apiTokenHook)
+	case boil.AfterSelectHook:
+		apiTokenAfterSelectHooks = append(apiTokenAfterSelectHooks, apiTokenHook)
+	case boil.AfterUpdateHook:
+		apiTokenAfterUpdateHooks = append(apiTokenAfterUpdateHooks, apiTokenHook)
+	case boil.AfterDeleteHook:
+		apiTokenAfterDeleteHooks = append(apiTokenAfterDeleteHooks, apiTokenHook)
+	case boil.AfterUpsertHook:
+		apiTokenAfterUpsertHooks = append(apiTokenAfterUpsertHooks, apiTokenHook)
+	}
+}
+
+// OneG returns a single apiToken record from the query using the global executor.
+func (q apiTokenQuery) OneG() (*APIToken, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single apiToken record from the query.
+func (q apiTokenQuery) One(exec boil.Executor) (*APIToken, error) {
+	o := &APIToken{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for api_tokens")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all APIToken records from the query using the global executor.
+func (q apiTokenQuery) AllG() (APITokenSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all APIToken records from the query.
+func (q apiTokenQuery) All(exec boil.Executor) (APITokenSlice, error) {
+	var o []*APIToken
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to APIToken slice")
+	}
+
+	if len(apiTokenAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all APIToken records in the query, and panics on error.
+func (q apiTokenQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all APIToken records in the query.
+func (q apiTokenQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count api_tokens rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q apiTokenQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q apiTokenQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if api_tokens exists")
+	}
+
+	return count > 0, nil
+}
+
+// User pointed to by the foreign key.
+func (o *APIToken) User(mods ...qm.QueryMod) userQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.UserID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Users(queryMods...)
+	queries.SetFrom(query.Query, "\"users\"")
+
+	return query
+}
+
+// LoadUser allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (apiTokenL) LoadUser(e boil.Executor, singular bool, maybeAPIToken interface{}, mods queries.Applicator) error {
+	var slice []*APIToken
+	var object *APIToken
+
+	if singular {
+		object = maybeAPIToken.(*APIToken)
+	} else {
+		slice = *maybeAPIToken.(*[]*APIToken)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &apiTokenR{}
+		}
+		args = append(args, object.UserID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &apiTokenR{}
+			}
+
+			for _, a := range args {
+				if a == obj.UserID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.UserID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`users`),
+		qm.WhereIn(`users.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load User")
+	}
+
+	var resultSlice []*User
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice User")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for users")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for users")
+	}
+
+	if len(apiTokenAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.User = foreign
+		if foreign.R == nil {
+			foreign.R = &userR{}
+		}
+		foreign.R.APITokens = append(foreign.R.APITokens, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.UserID == foreign.ID {
+				local.R.User = foreign
+				if foreign.R == nil {
+					foreign.R = &userR{}
+				}
+				foreign.R.APITokens = append(foreign.R.APITokens, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetUserG of the apiToken to the related item.
+// Sets o.R.User to related.
+// Adds o to related.R.APITokens.
+// Uses the global database handle.
+func (o *APIToken) SetUserG(insert bool, related *User) error {
+	return o.SetUser(boil.GetDB(), insert, related)
+}
+
+// SetUser of the apiToken to the related item.
+// Sets o.R.User to related.
+// Adds o to related.R.APITokens.
+func (o *APIToken) SetUser(exec boil.Executor, insert bool, related *User) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"api_tokens\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"user_id"}),
+		strmangle.WhereClause("\"", "\"", 2, apiTokenPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.UserID = related.ID
+	if o.R == nil {
+		o.R = &apiTokenR{
+			User: related,
+		}
+	} else {
+		o.R.User = related
+	}
+
+	if related.R == nil {
+		related.R = &userR{
+			APITokens: APITokenSlice{o},
+		}
+	} else {
+		related.R.APITokens = append(related.R.APITokens, o)
+	}
+
+	return nil
+}
+
+// APITokens retrieves all the records using an executor.
+func APITokens(mods ...qm.QueryMod) apiTokenQuery {
+	mods = append(mods, qm.From("\"api_tokens\""))
+	return apiTokenQuery{NewQuery(mods...)}
+}
+
+// FindAPITokenG retrieves a single record by ID.
+func FindAPITokenG(iD string, selectCols ...string) (*APIToken, error) {
+	return FindAPIToken(boil.GetDB(), iD, selectCols...)
+}
+
+// FindAPIToken retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindAPIToken(exec boil.Executor, iD string, selectCols ...string) (*APIToken, error) {
+	apiTokenObj := &APIToken{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"api_tokens\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, apiTokenObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from api_tokens")
+	}
+
+	return apiTokenObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *APIToken) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *APIToken) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no api_tokens provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(apiTokenColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	apiTokenInsertCacheMut.RLock()
+	cache, cached := apiTokenInsertCache[key]
+	apiTokenInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			apiTokenAllColumns,
+			apiTokenColumnsWithDefault,
+			apiTokenColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(apiTokenType, apiTokenMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(apiTokenType, apiTokenMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"api_tokens\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"api_tokens\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into api_tokens")
+	}
+
+	if !cached {
+		apiTokenInsertCacheMut.Lock()
+		apiTokenInsertCache[key] = cache
+		apiTokenInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single APIToken record using the global executor.
+// See Update for more documentation.
+func (o *APIToken) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the APIToken.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *APIToken) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	apiTokenUpdateCacheMut.RLock()
+	cache, cached := apiTokenUpdateCache[key]
+	apiTokenUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			apiTokenAllColumns,
+			apiTokenPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update api_tokens, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"api_tokens\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, apiTokenPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(apiTokenType, apiTokenMapping, append(wl, apiTokenPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update api_tokens row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for api_tokens")
+	}
+
+	if !cached {
+		apiTokenUpdateCacheMut.Lock()
+		apiTokenUpdateCache[key] = cache
+		apiTokenUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q apiTokenQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q apiTokenQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for api_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for api_tokens")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o APITokenSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o APITokenSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), apiTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"api_tokens\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, apiTokenPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in apiToken slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all apiToken")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *APIToken) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *APIToken) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no api_tokens provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(apiTokenColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	apiTokenUpsertCacheMut.RLock()
+	cache, cached := apiTokenUpsertCache[key]
+	apiTokenUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			apiTokenAllColumns,
+			apiTokenColumnsWithDefault,
+			apiTokenColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			apiTokenAllColumns,
+			apiTokenPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert api_tokens, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(apiTokenPrimaryKeyColumns))
+			copy(conflict, apiTokenPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"api_tokens\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(apiTokenType, apiTokenMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(apiTokenType, apiTokenMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert api_tokens")
+	}
+
+	if !cached {
+		apiTokenUpsertCacheMut.Lock()
+		apiTokenUpsertCache[key] = cache
+		apiTokenUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single APIToken record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *APIToken) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single APIToken record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *APIToken) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no APIToken provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), apiTokenPrimaryKeyMapping)
+	sql := "DELETE FROM \"api_tokens\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from api_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for api_tokens")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q apiTokenQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q apiTokenQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no apiTokenQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from api_tokens")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for api_tokens")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o APITokenSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o APITokenSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(apiTokenBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), apiTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"api_tokens\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, apiTokenPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from apiToken slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for api_tokens")
+	}
+
+	if len(apiTokenAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *APIToken) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no APIToken provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *APIToken) Reload(exec boil.Executor) error {
+	ret, err := FindAPIToken(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *APITokenSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty APITokenSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *APITokenSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := APITokenSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), apiTokenPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"api_tokens\".* FROM \"api_tokens\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, apiTokenPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in APITokenSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// APITokenExistsG checks if the APIToken row exists.
+func APITokenExistsG(iD string) (bool, error) {
+	return APITokenExists(boil.GetDB(), iD)
+}
+
+// APITokenExists checks if the APIToken row exists.
+func APITokenExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"api_tokens\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if api_tokens exists")
+	}
+
+	return exists, nil
+}
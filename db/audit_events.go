@@ -0,0 +1,1436 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"github.com/volatiletech/strmangle"
+)
+
+// AuditEvent is an object representing the database table.
+type AuditEvent struct {
+	ID        string      `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	UserID    null.String `db:"user_id" boil:"user_id" json:"user_id,omitempty" toml:"user_id" yaml:"user_id,omitempty"`
+	Actor     string      `db:"actor" boil:"actor" json:"actor" toml:"actor" yaml:"actor"`
+	PrinterID null.String `db:"printer_id" boil:"printer_id" json:"printer_id,omitempty" toml:"printer_id" yaml:"printer_id,omitempty"`
+	Action    string      `db:"action" boil:"action" json:"action" toml:"action" yaml:"action"`
+	Params    types.JSON  `db:"params" boil:"params" json:"params" toml:"params" yaml:"params"`
+	CreatedAt time.Time   `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *auditEventR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L auditEventL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var AuditEventColumns = struct {
+	ID        string
+	UserID    string
+	Actor     string
+	PrinterID string
+	Action    string
+	Params    string
+	CreatedAt string
+}{
+	ID:        "id",
+	UserID:    "user_id",
+	Actor:     "actor",
+	PrinterID: "printer_id",
+	Action:    "action",
+	Params:    "params",
+	CreatedAt: "created_at",
+}
+
+// Generated where
+
+type whereHelpernull_String struct{ field string }
+
+func (w whereHelpernull_String) EQ(x null.String) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_String) NEQ(x null.String) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_String) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_String) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+func (w whereHelpernull_String) LT(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_String) LTE(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_String) GT(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_String) GTE(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+type whereHelpertypes_JSON struct{ field string }
+
+func (w whereHelpertypes_JSON) EQ(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelpertypes_JSON) NEQ(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelpertypes_JSON) LT(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpertypes_JSON) LTE(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpertypes_JSON) GT(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpertypes_JSON) GTE(x types.JSON) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+var AuditEventWhere = struct {
+	ID        whereHelperstring
+	UserID    whereHelpernull_String
+	Actor     whereHelperstring
+	PrinterID whereHelpernull_String
+	Action    whereHelperstring
+	Params    whereHelpertypes_JSON
+	CreatedAt whereHelpertime_Time
+}{
+	ID:        whereHelperstring{field: "\"audit_events\".\"id\""},
+	UserID:    whereHelpernull_String{field: "\"audit_events\".\"user_id\""},
+	Actor:     whereHelperstring{field: "\"audit_events\".\"actor\""},
+	PrinterID: whereHelpernull_String{field: "\"audit_events\".\"printer_id\""},
+	Action:    whereHelperstring{field: "\"audit_events\".\"action\""},
+	Params:    whereHelpertypes_JSON{field: "\"audit_events\".\"params\""},
+	CreatedAt: whereHelpertime_Time{field: "\"audit_events\".\"created_at\""},
+}
+
+// AuditEventRels is where relationship names are stored.
+var AuditEventRels = struct {
+	User    string
+	Printer string
+}{
+	User:    "User",
+	Printer: "Printer",
+}
+
+// auditEventR is where relationships are stored.
+type auditEventR struct {
+	User    *User    `db:"User" boil:"User" json:"User" toml:"User" yaml:"User"`
+	Printer *Printer `db:"Printer" boil:"Printer" json:"Printer" toml:"Printer" yaml:"Printer"`
+}
+
+// NewStruct creates a new relationship struct
+func (*auditEventR) NewStruct() *auditEventR {
+	return &auditEventR{}
+}
+
+// auditEventL is where Load methods for each relationship are stored.
+type auditEventL struct{}
+
+var (
+	auditEventAllColumns            = []string{"id", "user_id", "actor", "printer_id", "action", "params", "created_at"}
+	auditEventColumnsWithoutDefault = []string{"user_id", "actor", "printer_id", "action"}
+	auditEventColumnsWithDefault    = []string{"id", "params", "created_at"}
+	auditEventPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// AuditEventSlice is an alias for a slice of pointers to AuditEvent.
+	// This should generally be used opposed to []AuditEvent.
+	AuditEventSlice []*AuditEvent
+	// AuditEventHook is the signature for custom AuditEvent hook methods
+	AuditEventHook func(boil.Executor, *AuditEvent) error
+
+	auditEventQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	auditEventType                 = reflect.TypeOf(&AuditEvent{})
+	auditEventMapping              = queries.MakeStructMapping(auditEventType)
+	auditEventPrimaryKeyMapping, _ = queries.BindMapping(auditEventType, auditEventMapping, auditEventPrimaryKeyColumns)
+	auditEventInsertCacheMut       sync.RWMutex
+	auditEventInsertCache          = make(map[string]insertCache)
+	auditEventUpdateCacheMut       sync.RWMutex
+	auditEventUpdateCache          = make(map[string]updateCache)
+	auditEventUpsertCacheMut       sync.RWMutex
+	auditEventUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var auditEventBeforeInsertHooks []AuditEventHook
+var auditEventBeforeUpdateHooks []AuditEventHook
+var auditEventBeforeDeleteHooks []AuditEventHook
+var auditEventBeforeUpsertHooks []AuditEventHook
+
+var auditEventAfterInsertHooks []AuditEventHook
+var auditEventAfterSelectHooks []AuditEventHook
+var auditEventAfterUpdateHooks []AuditEventHook
+var auditEventAfterDeleteHooks []AuditEventHook
+var auditEventAfterUpsertHooks []AuditEventHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *AuditEvent) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *AuditEvent) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *AuditEvent) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *AuditEvent) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *AuditEvent) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *AuditEvent) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *AuditEvent) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *AuditEvent) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *AuditEvent) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range auditEventAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddAuditEventHook registers your hook function for all future operations.
+func AddAuditEventHook(hookPoint boil.HookPoint, auditEventHook AuditEventHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		auditEventBeforeInsertHooks = append(auditEventBeforeInsertHooks, auditEventHook)
+	case boil.BeforeUpdateHook:
+		auditEventBeforeUpdateHooks = append(auditEventBeforeUpdateHooks, auditEventHook)
+	case boil.BeforeDeleteHook:
+		auditEventBeforeDeleteHooks = append(auditEventBeforeDeleteHooks, auditEventHook)
+	case boil.BeforeUpsertHook:
+		auditEventBeforeUpsertHooks = append(auditEventBeforeUpsertHooks, auditEventHook)
+	case boil.AfterInsertHook:
+		auditEventAfterInsertHooks = append(auditEventAfterInsertHooks, auditEventHook)
+	case boil.AfterSelectHook:
+		auditEventAfterSelectHooks = append(auditEventAfterSelectHooks, auditEventHook)
+	case boil.AfterUpdateHook:
+		auditEventAfterUpdateHooks = append(auditEventAfterUpdateHooks, auditEventHook)
+	case boil.AfterDeleteHook:
+		auditEventAfterDeleteHooks = append(auditEventAfterDeleteHooks, auditEventHook)
+	case boil.AfterUpsertHook:
+		auditEventAfterUpsertHooks = append(auditEventAfterUpsertHooks, auditEventHook)
+	}
+}
+
+// OneG returns a single auditEvent record from the query using the global executor.
+func (q auditEventQuery) OneG() (*AuditEvent, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single auditEvent record from the query.
+func (q auditEventQuery) One(exec boil.Executor) (*AuditEvent, error) {
+	o := &AuditEvent{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for audit_events")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all AuditEvent records from the query using the global executor.
+func (q auditEventQuery) AllG() (AuditEventSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all AuditEvent records from the query.
+func (q auditEventQuery) All(exec boil.Executor) (AuditEventSlice, error) {
+	var o []*AuditEvent
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to AuditEvent slice")
+	}
+
+	if len(auditEventAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all AuditEvent records in the query, and panics on error.
+func (q auditEventQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all AuditEvent records in the query.
+func (q auditEventQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count audit_events rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q auditEventQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q auditEventQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if audit_events exists")
+	}
+
+	return count > 0, nil
+}
+
+// User pointed to by the foreign key.
+func (o *AuditEvent) User(mods ...qm.QueryMod) userQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.UserID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Users(queryMods...)
+	queries.SetFrom(query.Query, "\"users\"")
+
+	return query
+}
+
+// Printer pointed to by the foreign key.
+func (o *AuditEvent) Printer(mods ...qm.QueryMod) printerQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.PrinterID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Printers(queryMods...)
+	queries.SetFrom(query.Query, "\"printers\"")
+
+	return query
+}
+
+// LoadUser allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (auditEventL) LoadUser(e boil.Executor, singular bool, maybeAuditEvent interface{}, mods queries.Applicator) error {
+	var slice []*AuditEvent
+	var object *AuditEvent
+
+	if singular {
+		object = maybeAuditEvent.(*AuditEvent)
+	} else {
+		slice = *maybeAuditEvent.(*[]*AuditEvent)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &auditEventR{}
+		}
+		if !queries.IsNil(object.UserID) {
+			args = append(args, object.UserID)
+		}
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &auditEventR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.UserID) {
+					continue Outer
+				}
+			}
+
+			if !queries.IsNil(obj.UserID) {
+				args = append(args, obj.UserID)
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`users`),
+		qm.WhereIn(`users.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load User")
+	}
+
+	var resultSlice []*User
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice User")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for users")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for users")
+	}
+
+	if len(auditEventAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.User = foreign
+		if foreign.R == nil {
+			foreign.R = &userR{}
+		}
+		foreign.R.AuditEvents = append(foreign.R.AuditEvents, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.UserID, foreign.ID) {
+				local.R.User = foreign
+				if foreign.R == nil {
+					foreign.R = &userR{}
+				}
+				foreign.R.AuditEvents = append(foreign.R.AuditEvents, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadPrinter allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (auditEventL) LoadPrinter(e boil.Executor, singular bool, maybeAuditEvent interface{}, mods queries.Applicator) error {
+	var slice []*AuditEvent
+	var object *AuditEvent
+
+	if singular {
+		object = maybeAuditEvent.(*AuditEvent)
+	} else {
+		slice = *maybeAuditEvent.(*[]*AuditEvent)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &auditEventR{}
+		}
+		if !queries.IsNil(object.PrinterID) {
+			args = append(args, object.PrinterID)
+		}
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &auditEventR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.PrinterID) {
+					continue Outer
+				}
+			}
+
+			if !queries.IsNil(obj.PrinterID) {
+				args = append(args, obj.PrinterID)
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`printers`),
+		qm.WhereIn(`printers.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Printer")
+	}
+
+	var resultSlice []*Printer
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Printer")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for printers")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for printers")
+	}
+
+	if len(auditEventAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Printer = foreign
+		if foreign.R == nil {
+			foreign.R = &printerR{}
+		}
+		foreign.R.AuditEvents = append(foreign.R.AuditEvents, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.PrinterID, foreign.ID) {
+				local.R.Printer = foreign
+				if foreign.R == nil {
+					foreign.R = &printerR{}
+				}
+				foreign.R.AuditEvents = append(foreign.R.AuditEvents, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetUserG of the auditEvent to the related item.
+// Sets o.R.User to related.
+// Adds o to related.R.AuditEvents.
+// Uses the global database handle.
+func (o *AuditEvent) SetUserG(insert bool, related *User) error {
+	return o.SetUser(boil.GetDB(), insert, related)
+}
+
+// SetUser of the auditEvent to the related item.
+// Sets o.R.User to related.
+// Adds o to related.R.AuditEvents.
+func (o *AuditEvent) SetUser(exec boil.Executor, insert bool, related *User) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"audit_events\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"user_id"}),
+		strmangle.WhereClause("\"", "\"", 2, auditEventPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.UserID, related.ID)
+	if o.R == nil {
+		o.R = &auditEventR{
+			User: related,
+		}
+	} else {
+		o.R.User = related
+	}
+
+	if related.R == nil {
+		related.R = &userR{
+			AuditEvents: AuditEventSlice{o},
+		}
+	} else {
+		related.R.AuditEvents = append(related.R.AuditEvents, o)
+	}
+
+	return nil
+}
+
+// RemoveUserG relationship.
+// Sets o.R.User to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+// Uses the global database handle.
+func (o *AuditEvent) RemoveUserG(related *User) error {
+	return o.RemoveUser(boil.GetDB(), related)
+}
+
+// RemoveUser relationship.
+// Sets o.R.User to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+func (o *AuditEvent) RemoveUser(exec boil.Executor, related *User) error {
+	var err error
+
+	queries.SetScanner(&o.UserID, nil)
+	if _, err = o.Update(exec, boil.Whitelist("user_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.User = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.AuditEvents {
+		if queries.Equal(o.UserID, ri.UserID) {
+			continue
+		}
+
+		ln := len(related.R.AuditEvents)
+		if ln > 1 && i < ln-1 {
+			related.R.AuditEvents[i] = related.R.AuditEvents[ln-1]
+		}
+		related.R.AuditEvents = related.R.AuditEvents[:ln-1]
+		break
+	}
+	return nil
+}
+
+// SetPrinterG of the auditEvent to the related item.
+// Sets o.R.Printer to related.
+// Adds o to related.R.AuditEvents.
+// Uses the global database handle.
+func (o *AuditEvent) SetPrinterG(insert bool, related *Printer) error {
+	return o.SetPrinter(boil.GetDB(), insert, related)
+}
+
+// SetPrinter of the auditEvent to the related item.
+// Sets o.R.Printer to related.
+// Adds o to related.R.AuditEvents.
+func (o *AuditEvent) SetPrinter(exec boil.Executor, insert bool, related *Printer) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"audit_events\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"printer_id"}),
+		strmangle.WhereClause("\"", "\"", 2, auditEventPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.PrinterID, related.ID)
+	if o.R == nil {
+		o.R = &auditEventR{
+			Printer: related,
+		}
+	} else {
+		o.R.Printer = related
+	}
+
+	if related.R == nil {
+		related.R = &printerR{
+			AuditEvents: AuditEventSlice{o},
+		}
+	} else {
+		related.R.AuditEvents = append(related.R.AuditEvents, o)
+	}
+
+	return nil
+}
+
+// RemovePrinterG relationship.
+// Sets o.R.Printer to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+// Uses the global database handle.
+func (o *AuditEvent) RemovePrinterG(related *Printer) error {
+	return o.RemovePrinter(boil.GetDB(), related)
+}
+
+// RemovePrinter relationship.
+// Sets o.R.Printer to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+func (o *AuditEvent) RemovePrinter(exec boil.Executor, related *Printer) error {
+	var err error
+
+	queries.SetScanner(&o.PrinterID, nil)
+	if _, err = o.Update(exec, boil.Whitelist("printer_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.Printer = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.AuditEvents {
+		if queries.Equal(o.PrinterID, ri.PrinterID) {
+			continue
+		}
+
+		ln := len(related.R.AuditEvents)
+		if ln > 1 && i < ln-1 {
+			related.R.AuditEvents[i] = related.R.AuditEvents[ln-1]
+		}
+		related.R.AuditEvents = related.R.AuditEvents[:ln-1]
+		break
+	}
+	return nil
+}
+
+// AuditEvents retrieves all the records using an executor.
+func AuditEvents(mods ...qm.QueryMod) auditEventQuery {
+	mods = append(mods, qm.From("\"audit_events\""))
+	return auditEventQuery{NewQuery(mods...)}
+}
+
+// FindAuditEventG retrieves a single record by ID.
+func FindAuditEventG(iD string, selectCols ...string) (*AuditEvent, error) {
+	return FindAuditEvent(boil.GetDB(), iD, selectCols...)
+}
+
+// FindAuditEvent retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindAuditEvent(exec boil.Executor, iD string, selectCols ...string) (*AuditEvent, error) {
+	auditEventObj := &AuditEvent{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"audit_events\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, auditEventObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from audit_events")
+	}
+
+	return auditEventObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *AuditEvent) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *AuditEvent) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no audit_events provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(auditEventColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	auditEventInsertCacheMut.RLock()
+	cache, cached := auditEventInsertCache[key]
+	auditEventInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			auditEventAllColumns,
+			auditEventColumnsWithDefault,
+			auditEventColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(auditEventType, auditEventMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(auditEventType, auditEventMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"audit_events\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"audit_events\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into audit_events")
+	}
+
+	if !cached {
+		auditEventInsertCacheMut.Lock()
+		auditEventInsertCache[key] = cache
+		auditEventInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single AuditEvent record using the global executor.
+// See Update for more documentation.
+func (o *AuditEvent) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the AuditEvent.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *AuditEvent) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	auditEventUpdateCacheMut.RLock()
+	cache, cached := auditEventUpdateCache[key]
+	auditEventUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			auditEventAllColumns,
+			auditEventPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update audit_events, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"audit_events\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, auditEventPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(auditEventType, auditEventMapping, append(wl, auditEventPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update audit_events row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for audit_events")
+	}
+
+	if !cached {
+		auditEventUpdateCacheMut.Lock()
+		auditEventUpdateCache[key] = cache
+		auditEventUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q auditEventQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q auditEventQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for audit_events")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for audit_events")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o AuditEventSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o AuditEventSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), auditEventPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"audit_events\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, auditEventPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in auditEvent slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all auditEvent")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *AuditEvent) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *AuditEvent) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no audit_events provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(auditEventColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	auditEventUpsertCacheMut.RLock()
+	cache, cached := auditEventUpsertCache[key]
+	auditEventUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			auditEventAllColumns,
+			auditEventColumnsWithDefault,
+			auditEventColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			auditEventAllColumns,
+			auditEventPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert audit_events, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(auditEventPrimaryKeyColumns))
+			copy(conflict, auditEventPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"audit_events\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(auditEventType, auditEventMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(auditEventType, auditEventMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert audit_events")
+	}
+
+	if !cached {
+		auditEventUpsertCacheMut.Lock()
+		auditEventUpsertCache[key] = cache
+		auditEventUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single AuditEvent record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *AuditEvent) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single AuditEvent record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *AuditEvent) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no AuditEvent provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), auditEventPrimaryKeyMapping)
+	sql := "DELETE FROM \"audit_events\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from audit_events")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for audit_events")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q auditEventQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q auditEventQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no auditEventQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from audit_events")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for audit_events")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o AuditEventSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o AuditEventSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(auditEventBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), auditEventPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"audit_events\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, auditEventPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from auditEvent slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for audit_events")
+	}
+
+	if len(auditEventAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *AuditEvent) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no AuditEvent provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *AuditEvent) Reload(exec boil.Executor) error {
+	ret, err := FindAuditEvent(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *AuditEventSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty AuditEventSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *AuditEventSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := AuditEventSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), auditEventPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"audit_events\".* FROM \"audit_events\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, auditEventPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in AuditEventSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// AuditEventExistsG checks if the AuditEvent row exists.
+func AuditEventExistsG(iD string) (bool, error) {
+	return AuditEventExists(boil.GetDB(), iD)
+}
+
+// AuditEventExists checks if the AuditEvent row exists.
+func AuditEventExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"audit_events\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if audit_events exists")
+	}
+
+	return exists, nil
+}
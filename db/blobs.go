@@ -33,6 +33,8 @@ type Blob struct {
 	DeletedAt     null.Time `db:"deleted_at" boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
 	UpdatedAt     time.Time `db:"updated_at" boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
 	CreatedAt     time.Time `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	Sha256        string    `db:"sha256" boil:"sha256" json:"sha256" toml:"sha256" yaml:"sha256"`
+	Codec         string    `db:"codec" boil:"codec" json:"codec" toml:"codec" yaml:"codec"`
 
 	R *blobR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
 	L blobL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -49,6 +51,8 @@ var BlobColumns = struct {
 	DeletedAt     string
 	UpdatedAt     string
 	CreatedAt     string
+	Sha256        string
+	Codec         string
 }{
 	ID:            "id",
 	FileName:      "file_name",
@@ -60,33 +64,12 @@ var BlobColumns = struct {
 	DeletedAt:     "deleted_at",
 	UpdatedAt:     "updated_at",
 	CreatedAt:     "created_at",
+	Sha256:        "sha256",
+	Codec:         "codec",
 }
 
 // Generated where
 
-type whereHelperstring struct{ field string }
-
-func (w whereHelperstring) EQ(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperstring) NEQ(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperstring) LT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperstring) LTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperstring) GT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperstring) GTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperstring) IN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
 type whereHelperint64 struct{ field string }
 
 func (w whereHelperint64) EQ(x int64) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
@@ -142,50 +125,6 @@ func (w whereHelperint) NIN(slice []int) qm.QueryMod {
 	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
 }
 
-type whereHelpernull_Time struct{ field string }
-
-func (w whereHelpernull_Time) EQ(x null.Time) qm.QueryMod {
-	return qmhelper.WhereNullEQ(w.field, false, x)
-}
-func (w whereHelpernull_Time) NEQ(x null.Time) qm.QueryMod {
-	return qmhelper.WhereNullEQ(w.field, true, x)
-}
-func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
-func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
-func (w whereHelpernull_Time) LT(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelpernull_Time) LTE(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelpernull_Time) GT(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
-}
-func (w whereHelpernull_Time) GTE(x null.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
-}
-
-type whereHelpertime_Time struct{ field string }
-
-func (w whereHelpertime_Time) EQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.EQ, x)
-}
-func (w whereHelpertime_Time) NEQ(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.NEQ, x)
-}
-func (w whereHelpertime_Time) LT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LT, x)
-}
-func (w whereHelpertime_Time) LTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.LTE, x)
-}
-func (w whereHelpertime_Time) GT(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GT, x)
-}
-func (w whereHelpertime_Time) GTE(x time.Time) qm.QueryMod {
-	return qmhelper.Where(w.field, qmhelper.GTE, x)
-}
-
 var BlobWhere = struct {
 	ID            whereHelperstring
 	FileName      whereHelperstring
@@ -197,6 +136,8 @@ var BlobWhere = struct {
 	DeletedAt     whereHelpernull_Time
 	UpdatedAt     whereHelpertime_Time
 	CreatedAt     whereHelpertime_Time
+	Sha256        whereHelperstring
+	Codec         whereHelperstring
 }{
 	ID:            whereHelperstring{field: "\"blobs\".\"id\""},
 	FileName:      whereHelperstring{field: "\"blobs\".\"file_name\""},
@@ -208,18 +149,26 @@ var BlobWhere = struct {
 	DeletedAt:     whereHelpernull_Time{field: "\"blobs\".\"deleted_at\""},
 	UpdatedAt:     whereHelpertime_Time{field: "\"blobs\".\"updated_at\""},
 	CreatedAt:     whereHelpertime_Time{field: "\"blobs\".\"created_at\""},
+	Sha256:        whereHelperstring{field: "\"blobs\".\"sha256\""},
+	Codec:         whereHelperstring{field: "\"blobs\".\"codec\""},
 }
 
 // BlobRels is where relationship names are stored.
 var BlobRels = struct {
-	Gcodes string
+	GcodeVersions       string
+	Gcodes              string
+	ThumbnailBlobGcodes string
 }{
-	Gcodes: "Gcodes",
+	GcodeVersions:       "GcodeVersions",
+	Gcodes:              "Gcodes",
+	ThumbnailBlobGcodes: "ThumbnailBlobGcodes",
 }
 
 // blobR is where relationships are stored.
 type blobR struct {
-	Gcodes GcodeSlice `db:"Gcodes" boil:"Gcodes" json:"Gcodes" toml:"Gcodes" yaml:"Gcodes"`
+	GcodeVersions       GcodeVersionSlice `db:"GcodeVersions" boil:"GcodeVersions" json:"GcodeVersions" toml:"GcodeVersions" yaml:"GcodeVersions"`
+	Gcodes              GcodeSlice        `db:"Gcodes" boil:"Gcodes" json:"Gcodes" toml:"Gcodes" yaml:"Gcodes"`
+	ThumbnailBlobGcodes GcodeSlice        `db:"ThumbnailBlobGcodes" boil:"ThumbnailBlobGcodes" json:"ThumbnailBlobGcodes" toml:"ThumbnailBlobGcodes" yaml:"ThumbnailBlobGcodes"`
 }
 
 // NewStruct creates a new relationship struct
@@ -231,9 +180,9 @@ func (*blobR) NewStruct() *blobR {
 type blobL struct{}
 
 var (
-	blobAllColumns            = []string{"id", "file_name", "mime_type", "file_size_bytes", "extension", "data", "views", "deleted_at", "updated_at", "created_at"}
-	blobColumnsWithoutDefault = []string{"file_name", "mime_type", "file_size_bytes", "extension", "data", "deleted_at"}
-	blobColumnsWithDefault    = []string{"id", "views", "updated_at", "created_at"}
+	blobAllColumns            = []string{"id", "file_name", "mime_type", "file_size_bytes", "extension", "data", "views", "deleted_at", "updated_at", "created_at", "sha256", "codec"}
+	blobColumnsWithoutDefault = []string{"file_name", "mime_type", "file_size_bytes", "extension", "data", "deleted_at", "sha256"}
+	blobColumnsWithDefault    = []string{"id", "views", "updated_at", "created_at", "codec"}
 	blobPrimaryKeyColumns     = []string{"id"}
 )
 
@@ -496,6 +445,27 @@ func (q blobQuery) Exists(exec boil.Executor) (bool, error) {
 	return count > 0, nil
 }
 
+// GcodeVersions retrieves all the gcode_version's GcodeVersions with an executor.
+func (o *Blob) GcodeVersions(mods ...qm.QueryMod) gcodeVersionQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"gcode_versions\".\"blob_id\"=?", o.ID),
+	)
+
+	query := GcodeVersions(queryMods...)
+	queries.SetFrom(query.Query, "\"gcode_versions\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"gcode_versions\".*"})
+	}
+
+	return query
+}
+
 // Gcodes retrieves all the gcode's Gcodes with an executor.
 func (o *Blob) Gcodes(mods ...qm.QueryMod) gcodeQuery {
 	var queryMods []qm.QueryMod
@@ -517,6 +487,125 @@ func (o *Blob) Gcodes(mods ...qm.QueryMod) gcodeQuery {
 	return query
 }
 
+// ThumbnailBlobGcodes retrieves all the gcode's Gcodes with an executor via thumbnail_blob_id column.
+func (o *Blob) ThumbnailBlobGcodes(mods ...qm.QueryMod) gcodeQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"gcodes\".\"thumbnail_blob_id\"=?", o.ID),
+	)
+
+	query := Gcodes(queryMods...)
+	queries.SetFrom(query.Query, "\"gcodes\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"gcodes\".*"})
+	}
+
+	return query
+}
+
+// LoadGcodeVersions allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (blobL) LoadGcodeVersions(e boil.Executor, singular bool, maybeBlob interface{}, mods queries.Applicator) error {
+	var slice []*Blob
+	var object *Blob
+
+	if singular {
+		object = maybeBlob.(*Blob)
+	} else {
+		slice = *maybeBlob.(*[]*Blob)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &blobR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &blobR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`gcode_versions`),
+		qm.WhereIn(`gcode_versions.blob_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load gcode_versions")
+	}
+
+	var resultSlice []*GcodeVersion
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice gcode_versions")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on gcode_versions")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for gcode_versions")
+	}
+
+	if len(gcodeVersionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.GcodeVersions = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &gcodeVersionR{}
+			}
+			foreign.R.Blob = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.BlobID {
+				local.R.GcodeVersions = append(local.R.GcodeVersions, foreign)
+				if foreign.R == nil {
+					foreign.R = &gcodeVersionR{}
+				}
+				foreign.R.Blob = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadGcodes allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (blobL) LoadGcodes(e boil.Executor, singular bool, maybeBlob interface{}, mods queries.Applicator) error {
@@ -615,6 +704,165 @@ func (blobL) LoadGcodes(e boil.Executor, singular bool, maybeBlob interface{}, m
 	return nil
 }
 
+// LoadThumbnailBlobGcodes allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (blobL) LoadThumbnailBlobGcodes(e boil.Executor, singular bool, maybeBlob interface{}, mods queries.Applicator) error {
+	var slice []*Blob
+	var object *Blob
+
+	if singular {
+		object = maybeBlob.(*Blob)
+	} else {
+		slice = *maybeBlob.(*[]*Blob)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &blobR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &blobR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`gcodes`),
+		qm.WhereIn(`gcodes.thumbnail_blob_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load gcodes")
+	}
+
+	var resultSlice []*Gcode
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice gcodes")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on gcodes")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for gcodes")
+	}
+
+	if len(gcodeAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.ThumbnailBlobGcodes = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &gcodeR{}
+			}
+			foreign.R.ThumbnailBlob = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.ThumbnailBlobID) {
+				local.R.ThumbnailBlobGcodes = append(local.R.ThumbnailBlobGcodes, foreign)
+				if foreign.R == nil {
+					foreign.R = &gcodeR{}
+				}
+				foreign.R.ThumbnailBlob = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddGcodeVersionsG adds the given related objects to the existing relationships
+// of the blob, optionally inserting them as new records.
+// Appends related to o.R.GcodeVersions.
+// Sets related.R.Blob appropriately.
+// Uses the global database handle.
+func (o *Blob) AddGcodeVersionsG(insert bool, related ...*GcodeVersion) error {
+	return o.AddGcodeVersions(boil.GetDB(), insert, related...)
+}
+
+// AddGcodeVersions adds the given related objects to the existing relationships
+// of the blob, optionally inserting them as new records.
+// Appends related to o.R.GcodeVersions.
+// Sets related.R.Blob appropriately.
+func (o *Blob) AddGcodeVersions(exec boil.Executor, insert bool, related ...*GcodeVersion) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.BlobID = o.ID
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"gcode_versions\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"blob_id"}),
+				strmangle.WhereClause("\"", "\"", 2, gcodeVersionPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.BlobID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &blobR{
+			GcodeVersions: related,
+		}
+	} else {
+		o.R.GcodeVersions = append(o.R.GcodeVersions, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &gcodeVersionR{
+				Blob: o,
+			}
+		} else {
+			rel.R.Blob = o
+		}
+	}
+	return nil
+}
+
 // AddGcodesG adds the given related objects to the existing relationships
 // of the blob, optionally inserting them as new records.
 // Appends related to o.R.Gcodes.
@@ -676,6 +924,155 @@ func (o *Blob) AddGcodes(exec boil.Executor, insert bool, related ...*Gcode) err
 	return nil
 }
 
+// AddThumbnailBlobGcodesG adds the given related objects to the existing relationships
+// of the blob, optionally inserting them as new records.
+// Appends related to o.R.ThumbnailBlobGcodes.
+// Sets related.R.ThumbnailBlob appropriately.
+// Uses the global database handle.
+func (o *Blob) AddThumbnailBlobGcodesG(insert bool, related ...*Gcode) error {
+	return o.AddThumbnailBlobGcodes(boil.GetDB(), insert, related...)
+}
+
+// AddThumbnailBlobGcodes adds the given related objects to the existing relationships
+// of the blob, optionally inserting them as new records.
+// Appends related to o.R.ThumbnailBlobGcodes.
+// Sets related.R.ThumbnailBlob appropriately.
+func (o *Blob) AddThumbnailBlobGcodes(exec boil.Executor, insert bool, related ...*Gcode) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.ThumbnailBlobID, o.ID)
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"gcodes\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"thumbnail_blob_id"}),
+				strmangle.WhereClause("\"", "\"", 2, gcodePrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.ThumbnailBlobID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &blobR{
+			ThumbnailBlobGcodes: related,
+		}
+	} else {
+		o.R.ThumbnailBlobGcodes = append(o.R.ThumbnailBlobGcodes, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &gcodeR{
+				ThumbnailBlob: o,
+			}
+		} else {
+			rel.R.ThumbnailBlob = o
+		}
+	}
+	return nil
+}
+
+// SetThumbnailBlobGcodesG removes all previously related items of the
+// blob replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.ThumbnailBlob's ThumbnailBlobGcodes accordingly.
+// Replaces o.R.ThumbnailBlobGcodes with related.
+// Sets related.R.ThumbnailBlob's ThumbnailBlobGcodes accordingly.
+// Uses the global database handle.
+func (o *Blob) SetThumbnailBlobGcodesG(insert bool, related ...*Gcode) error {
+	return o.SetThumbnailBlobGcodes(boil.GetDB(), insert, related...)
+}
+
+// SetThumbnailBlobGcodes removes all previously related items of the
+// blob replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.ThumbnailBlob's ThumbnailBlobGcodes accordingly.
+// Replaces o.R.ThumbnailBlobGcodes with related.
+// Sets related.R.ThumbnailBlob's ThumbnailBlobGcodes accordingly.
+func (o *Blob) SetThumbnailBlobGcodes(exec boil.Executor, insert bool, related ...*Gcode) error {
+	query := "update \"gcodes\" set \"thumbnail_blob_id\" = null where \"thumbnail_blob_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	_, err := exec.Exec(query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.ThumbnailBlobGcodes {
+			queries.SetScanner(&rel.ThumbnailBlobID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.ThumbnailBlob = nil
+		}
+
+		o.R.ThumbnailBlobGcodes = nil
+	}
+	return o.AddThumbnailBlobGcodes(exec, insert, related...)
+}
+
+// RemoveThumbnailBlobGcodesG relationships from objects passed in.
+// Removes related items from R.ThumbnailBlobGcodes (uses pointer comparison, removal does not keep order)
+// Sets related.R.ThumbnailBlob.
+// Uses the global database handle.
+func (o *Blob) RemoveThumbnailBlobGcodesG(related ...*Gcode) error {
+	return o.RemoveThumbnailBlobGcodes(boil.GetDB(), related...)
+}
+
+// RemoveThumbnailBlobGcodes relationships from objects passed in.
+// Removes related items from R.ThumbnailBlobGcodes (uses pointer comparison, removal does not keep order)
+// Sets related.R.ThumbnailBlob.
+func (o *Blob) RemoveThumbnailBlobGcodes(exec boil.Executor, related ...*Gcode) error {
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.ThumbnailBlobID, nil)
+		if rel.R != nil {
+			rel.R.ThumbnailBlob = nil
+		}
+		if _, err = rel.Update(exec, boil.Whitelist("thumbnail_blob_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.ThumbnailBlobGcodes {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.ThumbnailBlobGcodes)
+			if ln > 1 && i < ln-1 {
+				o.R.ThumbnailBlobGcodes[i] = o.R.ThumbnailBlobGcodes[ln-1]
+			}
+			o.R.ThumbnailBlobGcodes = o.R.ThumbnailBlobGcodes[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
 // Blobs retrieves all the records using an executor.
 func Blobs(mods ...qm.QueryMod) blobQuery {
 	mods = append(mods, qm.From("\"blobs\""))
@@ -4,11 +4,31 @@
 package db
 
 var TableNames = struct {
+	AgentTokens      string
+	APITokens        string
+	AuditEvents      string
 	Blobs            string
+	Folders          string
+	GcodeVersions    string
 	Gcodes           string
+	Printers         string
 	SchemaMigrations string
+	UploadChunks     string
+	Uploads          string
+	UserSessions     string
+	Users            string
 }{
+	AgentTokens:      "agent_tokens",
+	APITokens:        "api_tokens",
+	AuditEvents:      "audit_events",
 	Blobs:            "blobs",
+	Folders:          "folders",
+	GcodeVersions:    "gcode_versions",
 	Gcodes:           "gcodes",
+	Printers:         "printers",
 	SchemaMigrations: "schema_migrations",
+	UploadChunks:     "upload_chunks",
+	Uploads:          "uploads",
+	UserSessions:     "user_sessions",
+	Users:            "users",
 }
@@ -0,0 +1,1982 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// Folder is an object representing the database table.
+type Folder struct {
+	ID        string      `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name      string      `db:"name" boil:"name" json:"name" toml:"name" yaml:"name"`
+	ParentID  null.String `db:"parent_id" boil:"parent_id" json:"parent_id,omitempty" toml:"parent_id" yaml:"parent_id,omitempty"`
+	UpdatedAt time.Time   `db:"updated_at" boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	CreatedAt time.Time   `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *folderR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L folderL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var FolderColumns = struct {
+	ID        string
+	Name      string
+	ParentID  string
+	UpdatedAt string
+	CreatedAt string
+}{
+	ID:        "id",
+	Name:      "name",
+	ParentID:  "parent_id",
+	UpdatedAt: "updated_at",
+	CreatedAt: "created_at",
+}
+
+// Generated where
+
+var FolderWhere = struct {
+	ID        whereHelperstring
+	Name      whereHelperstring
+	ParentID  whereHelpernull_String
+	UpdatedAt whereHelpertime_Time
+	CreatedAt whereHelpertime_Time
+}{
+	ID:        whereHelperstring{field: "\"folders\".\"id\""},
+	Name:      whereHelperstring{field: "\"folders\".\"name\""},
+	ParentID:  whereHelpernull_String{field: "\"folders\".\"parent_id\""},
+	UpdatedAt: whereHelpertime_Time{field: "\"folders\".\"updated_at\""},
+	CreatedAt: whereHelpertime_Time{field: "\"folders\".\"created_at\""},
+}
+
+// FolderRels is where relationship names are stored.
+var FolderRels = struct {
+	Parent        string
+	ParentFolders string
+	Gcodes        string
+	Uploads       string
+}{
+	Parent:        "Parent",
+	ParentFolders: "ParentFolders",
+	Gcodes:        "Gcodes",
+	Uploads:       "Uploads",
+}
+
+// folderR is where relationships are stored.
+type folderR struct {
+	Parent        *Folder     `db:"Parent" boil:"Parent" json:"Parent" toml:"Parent" yaml:"Parent"`
+	ParentFolders FolderSlice `db:"ParentFolders" boil:"ParentFolders" json:"ParentFolders" toml:"ParentFolders" yaml:"ParentFolders"`
+	Gcodes        GcodeSlice  `db:"Gcodes" boil:"Gcodes" json:"Gcodes" toml:"Gcodes" yaml:"Gcodes"`
+	Uploads       UploadSlice `db:"Uploads" boil:"Uploads" json:"Uploads" toml:"Uploads" yaml:"Uploads"`
+}
+
+// NewStruct creates a new relationship struct
+func (*folderR) NewStruct() *folderR {
+	return &folderR{}
+}
+
+// folderL is where Load methods for each relationship are stored.
+type folderL struct{}
+
+var (
+	folderAllColumns            = []string{"id", "name", "parent_id", "updated_at", "created_at"}
+	folderColumnsWithoutDefault = []string{"name", "parent_id"}
+	folderColumnsWithDefault    = []string{"id", "updated_at", "created_at"}
+	folderPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// FolderSlice is an alias for a slice of pointers to Folder.
+	// This should generally be used opposed to []Folder.
+	FolderSlice []*Folder
+	// FolderHook is the signature for custom Folder hook methods
+	FolderHook func(boil.Executor, *Folder) error
+
+	folderQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	folderType                 = reflect.TypeOf(&Folder{})
+	folderMapping              = queries.MakeStructMapping(folderType)
+	folderPrimaryKeyMapping, _ = queries.BindMapping(folderType, folderMapping, folderPrimaryKeyColumns)
+	folderInsertCacheMut       sync.RWMutex
+	folderInsertCache          = make(map[string]insertCache)
+	folderUpdateCacheMut       sync.RWMutex
+	folderUpdateCache          = make(map[string]updateCache)
+	folderUpsertCacheMut       sync.RWMutex
+	folderUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var folderBeforeInsertHooks []FolderHook
+var folderBeforeUpdateHooks []FolderHook
+var folderBeforeDeleteHooks []FolderHook
+var folderBeforeUpsertHooks []FolderHook
+
+var folderAfterInsertHooks []FolderHook
+var folderAfterSelectHooks []FolderHook
+var folderAfterUpdateHooks []FolderHook
+var folderAfterDeleteHooks []FolderHook
+var folderAfterUpsertHooks []FolderHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *Folder) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *Folder) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *Folder) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *Folder) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *Folder) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *Folder) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *Folder) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *Folder) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *Folder) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range folderAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddFolderHook registers your hook function for all future operations.
+func AddFolderHook(hookPoint boil.HookPoint, folderHook FolderHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		folderBeforeInsertHooks = append(folderBeforeInsertHooks, folderHook)
+	case boil.BeforeUpdateHook:
+		folderBeforeUpdateHooks = append(folderBeforeUpdateHooks, folderHook)
+	case boil.BeforeDeleteHook:
+		folderBeforeDeleteHooks = append(folderBeforeDeleteHooks, folderHook)
+	case boil.BeforeUpsertHook:
+		folderBeforeUpsertHooks = append(folderBeforeUpsertHooks, folderHook)
+	case boil.AfterInsertHook:
+		folderAfterInsertHooks = append(folderAfterInsertHooks, folderHook)
+	case boil.AfterSelectHook:
+		folderAfterSelectHooks = append(folderAfterSelectHooks, folderHook)
+	case boil.AfterUpdateHook:
+		folderAfterUpdateHooks = append(folderAfterUpdateHooks, folderHook)
+	case boil.AfterDeleteHook:
+		folderAfterDeleteHooks = append(folderAfterDeleteHooks, folderHook)
+	case boil.AfterUpsertHook:
+		folderAfterUpsertHooks = append(folderAfterUpsertHooks, folderHook)
+	}
+}
+
+// OneG returns a single folder record from the query using the global executor.
+func (q folderQuery) OneG() (*Folder, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single folder record from the query.
+func (q folderQuery) One(exec boil.Executor) (*Folder, error) {
+	o := &Folder{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for folders")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all Folder records from the query using the global executor.
+func (q folderQuery) AllG() (FolderSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all Folder records from the query.
+func (q folderQuery) All(exec boil.Executor) (FolderSlice, error) {
+	var o []*Folder
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to Folder slice")
+	}
+
+	if len(folderAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all Folder records in the query, and panics on error.
+func (q folderQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all Folder records in the query.
+func (q folderQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count folders rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q folderQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q folderQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if folders exists")
+	}
+
+	return count > 0, nil
+}
+
+// Parent pointed to by the foreign key.
+func (o *Folder) Parent(mods ...qm.QueryMod) folderQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.ParentID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Folders(queryMods...)
+	queries.SetFrom(query.Query, "\"folders\"")
+
+	return query
+}
+
+// ParentFolders retrieves all the folder's Folders with an executor via parent_id column.
+func (o *Folder) ParentFolders(mods ...qm.QueryMod) folderQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"folders\".\"parent_id\"=?", o.ID),
+	)
+
+	query := Folders(queryMods...)
+	queries.SetFrom(query.Query, "\"folders\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"folders\".*"})
+	}
+
+	return query
+}
+
+// Gcodes retrieves all the gcode's Gcodes with an executor.
+func (o *Folder) Gcodes(mods ...qm.QueryMod) gcodeQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"gcodes\".\"folder_id\"=?", o.ID),
+	)
+
+	query := Gcodes(queryMods...)
+	queries.SetFrom(query.Query, "\"gcodes\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"gcodes\".*"})
+	}
+
+	return query
+}
+
+// Uploads retrieves all the upload's Uploads with an executor.
+func (o *Folder) Uploads(mods ...qm.QueryMod) uploadQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"uploads\".\"folder_id\"=?", o.ID),
+	)
+
+	query := Uploads(queryMods...)
+	queries.SetFrom(query.Query, "\"uploads\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"uploads\".*"})
+	}
+
+	return query
+}
+
+// LoadParent allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (folderL) LoadParent(e boil.Executor, singular bool, maybeFolder interface{}, mods queries.Applicator) error {
+	var slice []*Folder
+	var object *Folder
+
+	if singular {
+		object = maybeFolder.(*Folder)
+	} else {
+		slice = *maybeFolder.(*[]*Folder)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &folderR{}
+		}
+		if !queries.IsNil(object.ParentID) {
+			args = append(args, object.ParentID)
+		}
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &folderR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ParentID) {
+					continue Outer
+				}
+			}
+
+			if !queries.IsNil(obj.ParentID) {
+				args = append(args, obj.ParentID)
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`folders`),
+		qm.WhereIn(`folders.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Folder")
+	}
+
+	var resultSlice []*Folder
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Folder")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for folders")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for folders")
+	}
+
+	if len(folderAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Parent = foreign
+		if foreign.R == nil {
+			foreign.R = &folderR{}
+		}
+		foreign.R.ParentFolders = append(foreign.R.ParentFolders, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.ParentID, foreign.ID) {
+				local.R.Parent = foreign
+				if foreign.R == nil {
+					foreign.R = &folderR{}
+				}
+				foreign.R.ParentFolders = append(foreign.R.ParentFolders, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadParentFolders allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (folderL) LoadParentFolders(e boil.Executor, singular bool, maybeFolder interface{}, mods queries.Applicator) error {
+	var slice []*Folder
+	var object *Folder
+
+	if singular {
+		object = maybeFolder.(*Folder)
+	} else {
+		slice = *maybeFolder.(*[]*Folder)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &folderR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &folderR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`folders`),
+		qm.WhereIn(`folders.parent_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load folders")
+	}
+
+	var resultSlice []*Folder
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice folders")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on folders")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for folders")
+	}
+
+	if len(folderAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.ParentFolders = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &folderR{}
+			}
+			foreign.R.Parent = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.ParentID) {
+				local.R.ParentFolders = append(local.R.ParentFolders, foreign)
+				if foreign.R == nil {
+					foreign.R = &folderR{}
+				}
+				foreign.R.Parent = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadGcodes allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (folderL) LoadGcodes(e boil.Executor, singular bool, maybeFolder interface{}, mods queries.Applicator) error {
+	var slice []*Folder
+	var object *Folder
+
+	if singular {
+		object = maybeFolder.(*Folder)
+	} else {
+		slice = *maybeFolder.(*[]*Folder)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &folderR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &folderR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`gcodes`),
+		qm.WhereIn(`gcodes.folder_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load gcodes")
+	}
+
+	var resultSlice []*Gcode
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice gcodes")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on gcodes")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for gcodes")
+	}
+
+	if len(gcodeAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.Gcodes = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &gcodeR{}
+			}
+			foreign.R.Folder = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.FolderID) {
+				local.R.Gcodes = append(local.R.Gcodes, foreign)
+				if foreign.R == nil {
+					foreign.R = &gcodeR{}
+				}
+				foreign.R.Folder = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadUploads allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (folderL) LoadUploads(e boil.Executor, singular bool, maybeFolder interface{}, mods queries.Applicator) error {
+	var slice []*Folder
+	var object *Folder
+
+	if singular {
+		object = maybeFolder.(*Folder)
+	} else {
+		slice = *maybeFolder.(*[]*Folder)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &folderR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &folderR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`uploads`),
+		qm.WhereIn(`uploads.folder_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load uploads")
+	}
+
+	var resultSlice []*Upload
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice uploads")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on uploads")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for uploads")
+	}
+
+	if len(uploadAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.Uploads = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &uploadR{}
+			}
+			foreign.R.Folder = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.FolderID) {
+				local.R.Uploads = append(local.R.Uploads, foreign)
+				if foreign.R == nil {
+					foreign.R = &uploadR{}
+				}
+				foreign.R.Folder = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetParentG of the folder to the related item.
+// Sets o.R.Parent to related.
+// Adds o to related.R.ParentFolders.
+// Uses the global database handle.
+func (o *Folder) SetParentG(insert bool, related *Folder) error {
+	return o.SetParent(boil.GetDB(), insert, related)
+}
+
+// SetParent of the folder to the related item.
+// Sets o.R.Parent to related.
+// Adds o to related.R.ParentFolders.
+func (o *Folder) SetParent(exec boil.Executor, insert bool, related *Folder) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"folders\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"parent_id"}),
+		strmangle.WhereClause("\"", "\"", 2, folderPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.ParentID, related.ID)
+	if o.R == nil {
+		o.R = &folderR{
+			Parent: related,
+		}
+	} else {
+		o.R.Parent = related
+	}
+
+	if related.R == nil {
+		related.R = &folderR{
+			ParentFolders: FolderSlice{o},
+		}
+	} else {
+		related.R.ParentFolders = append(related.R.ParentFolders, o)
+	}
+
+	return nil
+}
+
+// RemoveParentG relationship.
+// Sets o.R.Parent to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+// Uses the global database handle.
+func (o *Folder) RemoveParentG(related *Folder) error {
+	return o.RemoveParent(boil.GetDB(), related)
+}
+
+// RemoveParent relationship.
+// Sets o.R.Parent to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+func (o *Folder) RemoveParent(exec boil.Executor, related *Folder) error {
+	var err error
+
+	queries.SetScanner(&o.ParentID, nil)
+	if _, err = o.Update(exec, boil.Whitelist("parent_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.Parent = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.ParentFolders {
+		if queries.Equal(o.ParentID, ri.ParentID) {
+			continue
+		}
+
+		ln := len(related.R.ParentFolders)
+		if ln > 1 && i < ln-1 {
+			related.R.ParentFolders[i] = related.R.ParentFolders[ln-1]
+		}
+		related.R.ParentFolders = related.R.ParentFolders[:ln-1]
+		break
+	}
+	return nil
+}
+
+// AddParentFoldersG adds the given related objects to the existing relationships
+// of the folder, optionally inserting them as new records.
+// Appends related to o.R.ParentFolders.
+// Sets related.R.Parent appropriately.
+// Uses the global database handle.
+func (o *Folder) AddParentFoldersG(insert bool, related ...*Folder) error {
+	return o.AddParentFolders(boil.GetDB(), insert, related...)
+}
+
+// AddParentFolders adds the given related objects to the existing relationships
+// of the folder, optionally inserting them as new records.
+// Appends related to o.R.ParentFolders.
+// Sets related.R.Parent appropriately.
+func (o *Folder) AddParentFolders(exec boil.Executor, insert bool, related ...*Folder) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.ParentID, o.ID)
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"folders\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"parent_id"}),
+				strmangle.WhereClause("\"", "\"", 2, folderPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.ParentID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &folderR{
+			ParentFolders: related,
+		}
+	} else {
+		o.R.ParentFolders = append(o.R.ParentFolders, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &folderR{
+				Parent: o,
+			}
+		} else {
+			rel.R.Parent = o
+		}
+	}
+	return nil
+}
+
+// SetParentFoldersG removes all previously related items of the
+// folder replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Parent's ParentFolders accordingly.
+// Replaces o.R.ParentFolders with related.
+// Sets related.R.Parent's ParentFolders accordingly.
+// Uses the global database handle.
+func (o *Folder) SetParentFoldersG(insert bool, related ...*Folder) error {
+	return o.SetParentFolders(boil.GetDB(), insert, related...)
+}
+
+// SetParentFolders removes all previously related items of the
+// folder replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Parent's ParentFolders accordingly.
+// Replaces o.R.ParentFolders with related.
+// Sets related.R.Parent's ParentFolders accordingly.
+func (o *Folder) SetParentFolders(exec boil.Executor, insert bool, related ...*Folder) error {
+	query := "update \"folders\" set \"parent_id\" = null where \"parent_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	_, err := exec.Exec(query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.ParentFolders {
+			queries.SetScanner(&rel.ParentID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Parent = nil
+		}
+
+		o.R.ParentFolders = nil
+	}
+	return o.AddParentFolders(exec, insert, related...)
+}
+
+// RemoveParentFoldersG relationships from objects passed in.
+// Removes related items from R.ParentFolders (uses pointer comparison, removal does not keep order)
+// Sets related.R.Parent.
+// Uses the global database handle.
+func (o *Folder) RemoveParentFoldersG(related ...*Folder) error {
+	return o.RemoveParentFolders(boil.GetDB(), related...)
+}
+
+// RemoveParentFolders relationships from objects passed in.
+// Removes related items from R.ParentFolders (uses pointer comparison, removal does not keep order)
+// Sets related.R.Parent.
+func (o *Folder) RemoveParentFolders(exec boil.Executor, related ...*Folder) error {
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.ParentID, nil)
+		if rel.R != nil {
+			rel.R.Parent = nil
+		}
+		if _, err = rel.Update(exec, boil.Whitelist("parent_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.ParentFolders {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.ParentFolders)
+			if ln > 1 && i < ln-1 {
+				o.R.ParentFolders[i] = o.R.ParentFolders[ln-1]
+			}
+			o.R.ParentFolders = o.R.ParentFolders[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// AddGcodesG adds the given related objects to the existing relationships
+// of the folder, optionally inserting them as new records.
+// Appends related to o.R.Gcodes.
+// Sets related.R.Folder appropriately.
+// Uses the global database handle.
+func (o *Folder) AddGcodesG(insert bool, related ...*Gcode) error {
+	return o.AddGcodes(boil.GetDB(), insert, related...)
+}
+
+// AddGcodes adds the given related objects to the existing relationships
+// of the folder, optionally inserting them as new records.
+// Appends related to o.R.Gcodes.
+// Sets related.R.Folder appropriately.
+func (o *Folder) AddGcodes(exec boil.Executor, insert bool, related ...*Gcode) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.FolderID, o.ID)
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"gcodes\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"folder_id"}),
+				strmangle.WhereClause("\"", "\"", 2, gcodePrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.FolderID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &folderR{
+			Gcodes: related,
+		}
+	} else {
+		o.R.Gcodes = append(o.R.Gcodes, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &gcodeR{
+				Folder: o,
+			}
+		} else {
+			rel.R.Folder = o
+		}
+	}
+	return nil
+}
+
+// SetGcodesG removes all previously related items of the
+// folder replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Folder's Gcodes accordingly.
+// Replaces o.R.Gcodes with related.
+// Sets related.R.Folder's Gcodes accordingly.
+// Uses the global database handle.
+func (o *Folder) SetGcodesG(insert bool, related ...*Gcode) error {
+	return o.SetGcodes(boil.GetDB(), insert, related...)
+}
+
+// SetGcodes removes all previously related items of the
+// folder replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Folder's Gcodes accordingly.
+// Replaces o.R.Gcodes with related.
+// Sets related.R.Folder's Gcodes accordingly.
+func (o *Folder) SetGcodes(exec boil.Executor, insert bool, related ...*Gcode) error {
+	query := "update \"gcodes\" set \"folder_id\" = null where \"folder_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	_, err := exec.Exec(query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.Gcodes {
+			queries.SetScanner(&rel.FolderID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Folder = nil
+		}
+
+		o.R.Gcodes = nil
+	}
+	return o.AddGcodes(exec, insert, related...)
+}
+
+// RemoveGcodesG relationships from objects passed in.
+// Removes related items from R.Gcodes (uses pointer comparison, removal does not keep order)
+// Sets related.R.Folder.
+// Uses the global database handle.
+func (o *Folder) RemoveGcodesG(related ...*Gcode) error {
+	return o.RemoveGcodes(boil.GetDB(), related...)
+}
+
+// RemoveGcodes relationships from objects passed in.
+// Removes related items from R.Gcodes (uses pointer comparison, removal does not keep order)
+// Sets related.R.Folder.
+func (o *Folder) RemoveGcodes(exec boil.Executor, related ...*Gcode) error {
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.FolderID, nil)
+		if rel.R != nil {
+			rel.R.Folder = nil
+		}
+		if _, err = rel.Update(exec, boil.Whitelist("folder_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.Gcodes {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.Gcodes)
+			if ln > 1 && i < ln-1 {
+				o.R.Gcodes[i] = o.R.Gcodes[ln-1]
+			}
+			o.R.Gcodes = o.R.Gcodes[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// AddUploadsG adds the given related objects to the existing relationships
+// of the folder, optionally inserting them as new records.
+// Appends related to o.R.Uploads.
+// Sets related.R.Folder appropriately.
+// Uses the global database handle.
+func (o *Folder) AddUploadsG(insert bool, related ...*Upload) error {
+	return o.AddUploads(boil.GetDB(), insert, related...)
+}
+
+// AddUploads adds the given related objects to the existing relationships
+// of the folder, optionally inserting them as new records.
+// Appends related to o.R.Uploads.
+// Sets related.R.Folder appropriately.
+func (o *Folder) AddUploads(exec boil.Executor, insert bool, related ...*Upload) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.FolderID, o.ID)
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"uploads\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"folder_id"}),
+				strmangle.WhereClause("\"", "\"", 2, uploadPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.FolderID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &folderR{
+			Uploads: related,
+		}
+	} else {
+		o.R.Uploads = append(o.R.Uploads, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &uploadR{
+				Folder: o,
+			}
+		} else {
+			rel.R.Folder = o
+		}
+	}
+	return nil
+}
+
+// SetUploadsG removes all previously related items of the
+// folder replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Folder's Uploads accordingly.
+// Replaces o.R.Uploads with related.
+// Sets related.R.Folder's Uploads accordingly.
+// Uses the global database handle.
+func (o *Folder) SetUploadsG(insert bool, related ...*Upload) error {
+	return o.SetUploads(boil.GetDB(), insert, related...)
+}
+
+// SetUploads removes all previously related items of the
+// folder replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Folder's Uploads accordingly.
+// Replaces o.R.Uploads with related.
+// Sets related.R.Folder's Uploads accordingly.
+func (o *Folder) SetUploads(exec boil.Executor, insert bool, related ...*Upload) error {
+	query := "update \"uploads\" set \"folder_id\" = null where \"folder_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	_, err := exec.Exec(query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.Uploads {
+			queries.SetScanner(&rel.FolderID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Folder = nil
+		}
+
+		o.R.Uploads = nil
+	}
+	return o.AddUploads(exec, insert, related...)
+}
+
+// RemoveUploadsG relationships from objects passed in.
+// Removes related items from R.Uploads (uses pointer comparison, removal does not keep order)
+// Sets related.R.Folder.
+// Uses the global database handle.
+func (o *Folder) RemoveUploadsG(related ...*Upload) error {
+	return o.RemoveUploads(boil.GetDB(), related...)
+}
+
+// RemoveUploads relationships from objects passed in.
+// Removes related items from R.Uploads (uses pointer comparison, removal does not keep order)
+// Sets related.R.Folder.
+func (o *Folder) RemoveUploads(exec boil.Executor, related ...*Upload) error {
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.FolderID, nil)
+		if rel.R != nil {
+			rel.R.Folder = nil
+		}
+		if _, err = rel.Update(exec, boil.Whitelist("folder_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.Uploads {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.Uploads)
+			if ln > 1 && i < ln-1 {
+				o.R.Uploads[i] = o.R.Uploads[ln-1]
+			}
+			o.R.Uploads = o.R.Uploads[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// Folders retrieves all the records using an executor.
+func Folders(mods ...qm.QueryMod) folderQuery {
+	mods = append(mods, qm.From("\"folders\""))
+	return folderQuery{NewQuery(mods...)}
+}
+
+// FindFolderG retrieves a single record by ID.
+func FindFolderG(iD string, selectCols ...string) (*Folder, error) {
+	return FindFolder(boil.GetDB(), iD, selectCols...)
+}
+
+// FindFolder retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindFolder(exec boil.Executor, iD string, selectCols ...string) (*Folder, error) {
+	folderObj := &Folder{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"folders\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, folderObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from folders")
+	}
+
+	return folderObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *Folder) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *Folder) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no folders provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.UpdatedAt.IsZero() {
+		o.UpdatedAt = currTime
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(folderColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	folderInsertCacheMut.RLock()
+	cache, cached := folderInsertCache[key]
+	folderInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			folderAllColumns,
+			folderColumnsWithDefault,
+			folderColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(folderType, folderMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(folderType, folderMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"folders\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"folders\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into folders")
+	}
+
+	if !cached {
+		folderInsertCacheMut.Lock()
+		folderInsertCache[key] = cache
+		folderInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single Folder record using the global executor.
+// See Update for more documentation.
+func (o *Folder) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the Folder.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *Folder) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	currTime := time.Now().In(boil.GetLocation())
+
+	o.UpdatedAt = currTime
+
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	folderUpdateCacheMut.RLock()
+	cache, cached := folderUpdateCache[key]
+	folderUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			folderAllColumns,
+			folderPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update folders, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"folders\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, folderPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(folderType, folderMapping, append(wl, folderPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update folders row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for folders")
+	}
+
+	if !cached {
+		folderUpdateCacheMut.Lock()
+		folderUpdateCache[key] = cache
+		folderUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q folderQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q folderQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for folders")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for folders")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o FolderSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o FolderSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), folderPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"folders\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, folderPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in folder slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all folder")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *Folder) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *Folder) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no folders provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	o.UpdatedAt = currTime
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(folderColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	folderUpsertCacheMut.RLock()
+	cache, cached := folderUpsertCache[key]
+	folderUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			folderAllColumns,
+			folderColumnsWithDefault,
+			folderColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			folderAllColumns,
+			folderPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert folders, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(folderPrimaryKeyColumns))
+			copy(conflict, folderPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"folders\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(folderType, folderMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(folderType, folderMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert folders")
+	}
+
+	if !cached {
+		folderUpsertCacheMut.Lock()
+		folderUpsertCache[key] = cache
+		folderUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single Folder record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *Folder) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single Folder record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *Folder) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no Folder provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), folderPrimaryKeyMapping)
+	sql := "DELETE FROM \"folders\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from folders")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for folders")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q folderQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q folderQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no folderQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from folders")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for folders")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o FolderSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o FolderSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(folderBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), folderPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"folders\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, folderPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from folder slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for folders")
+	}
+
+	if len(folderAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *Folder) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no Folder provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *Folder) Reload(exec boil.Executor) error {
+	ret, err := FindFolder(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *FolderSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty FolderSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *FolderSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := FolderSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), folderPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"folders\".* FROM \"folders\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, folderPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in FolderSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// FolderExistsG checks if the Folder row exists.
+func FolderExistsG(iD string) (bool, error) {
+	return FolderExists(boil.GetDB(), iD)
+}
+
+// FolderExists checks if the Folder row exists.
+func FolderExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"folders\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if folders exists")
+	}
+
+	return exists, nil
+}
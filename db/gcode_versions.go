@@ -0,0 +1,1295 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// GcodeVersion is an object representing the database table.
+type GcodeVersion struct {
+	ID         string    `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	GcodeID    string    `db:"gcode_id" boil:"gcode_id" json:"gcode_id" toml:"gcode_id" yaml:"gcode_id"`
+	Version    int       `db:"version" boil:"version" json:"version" toml:"version" yaml:"version"`
+	BlobID     string    `db:"blob_id" boil:"blob_id" json:"blob_id" toml:"blob_id" yaml:"blob_id"`
+	UploadedBy string    `db:"uploaded_by" boil:"uploaded_by" json:"uploaded_by" toml:"uploaded_by" yaml:"uploaded_by"`
+	CreatedAt  time.Time `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *gcodeVersionR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L gcodeVersionL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var GcodeVersionColumns = struct {
+	ID         string
+	GcodeID    string
+	Version    string
+	BlobID     string
+	UploadedBy string
+	CreatedAt  string
+}{
+	ID:         "id",
+	GcodeID:    "gcode_id",
+	Version:    "version",
+	BlobID:     "blob_id",
+	UploadedBy: "uploaded_by",
+	CreatedAt:  "created_at",
+}
+
+// Generated where
+
+var GcodeVersionWhere = struct {
+	ID         whereHelperstring
+	GcodeID    whereHelperstring
+	Version    whereHelperint
+	BlobID     whereHelperstring
+	UploadedBy whereHelperstring
+	CreatedAt  whereHelpertime_Time
+}{
+	ID:         whereHelperstring{field: "\"gcode_versions\".\"id\""},
+	GcodeID:    whereHelperstring{field: "\"gcode_versions\".\"gcode_id\""},
+	Version:    whereHelperint{field: "\"gcode_versions\".\"version\""},
+	BlobID:     whereHelperstring{field: "\"gcode_versions\".\"blob_id\""},
+	UploadedBy: whereHelperstring{field: "\"gcode_versions\".\"uploaded_by\""},
+	CreatedAt:  whereHelpertime_Time{field: "\"gcode_versions\".\"created_at\""},
+}
+
+// GcodeVersionRels is where relationship names are stored.
+var GcodeVersionRels = struct {
+	Gcode string
+	Blob  string
+}{
+	Gcode: "Gcode",
+	Blob:  "Blob",
+}
+
+// gcodeVersionR is where relationships are stored.
+type gcodeVersionR struct {
+	Gcode *Gcode `db:"Gcode" boil:"Gcode" json:"Gcode" toml:"Gcode" yaml:"Gcode"`
+	Blob  *Blob  `db:"Blob" boil:"Blob" json:"Blob" toml:"Blob" yaml:"Blob"`
+}
+
+// NewStruct creates a new relationship struct
+func (*gcodeVersionR) NewStruct() *gcodeVersionR {
+	return &gcodeVersionR{}
+}
+
+// gcodeVersionL is where Load methods for each relationship are stored.
+type gcodeVersionL struct{}
+
+var (
+	gcodeVersionAllColumns            = []string{"id", "gcode_id", "version", "blob_id", "uploaded_by", "created_at"}
+	gcodeVersionColumnsWithoutDefault = []string{"gcode_id", "version", "blob_id"}
+	gcodeVersionColumnsWithDefault    = []string{"id", "uploaded_by", "created_at"}
+	gcodeVersionPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// GcodeVersionSlice is an alias for a slice of pointers to GcodeVersion.
+	// This should generally be used opposed to []GcodeVersion.
+	GcodeVersionSlice []*GcodeVersion
+	// GcodeVersionHook is the signature for custom GcodeVersion hook methods
+	GcodeVersionHook func(boil.Executor, *GcodeVersion) error
+
+	gcodeVersionQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	gcodeVersionType                 = reflect.TypeOf(&GcodeVersion{})
+	gcodeVersionMapping              = queries.MakeStructMapping(gcodeVersionType)
+	gcodeVersionPrimaryKeyMapping, _ = queries.BindMapping(gcodeVersionType, gcodeVersionMapping, gcodeVersionPrimaryKeyColumns)
+	gcodeVersionInsertCacheMut       sync.RWMutex
+	gcodeVersionInsertCache          = make(map[string]insertCache)
+	gcodeVersionUpdateCacheMut       sync.RWMutex
+	gcodeVersionUpdateCache          = make(map[string]updateCache)
+	gcodeVersionUpsertCacheMut       sync.RWMutex
+	gcodeVersionUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var gcodeVersionBeforeInsertHooks []GcodeVersionHook
+var gcodeVersionBeforeUpdateHooks []GcodeVersionHook
+var gcodeVersionBeforeDeleteHooks []GcodeVersionHook
+var gcodeVersionBeforeUpsertHooks []GcodeVersionHook
+
+var gcodeVersionAfterInsertHooks []GcodeVersionHook
+var gcodeVersionAfterSelectHooks []GcodeVersionHook
+var gcodeVersionAfterUpdateHooks []GcodeVersionHook
+var gcodeVersionAfterDeleteHooks []GcodeVersionHook
+var gcodeVersionAfterUpsertHooks []GcodeVersionHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *GcodeVersion) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *GcodeVersion) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *GcodeVersion) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *GcodeVersion) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *GcodeVersion) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *GcodeVersion) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *GcodeVersion) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *GcodeVersion) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *GcodeVersion) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range gcodeVersionAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddGcodeVersionHook registers your hook function for all future operations.
+func AddGcodeVersionHook(hookPoint boil.HookPoint, gcodeVersionHook GcodeVersionHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		gcodeVersionBeforeInsertHooks = append(gcodeVersionBeforeInsertHooks, gcodeVersionHook)
+	case boil.BeforeUpdateHook:
+		gcodeVersionBeforeUpdateHooks = append(gcodeVersionBeforeUpdateHooks, gcodeVersionHook)
+	case boil.BeforeDeleteHook:
+		gcodeVersionBeforeDeleteHooks = append(gcodeVersionBeforeDeleteHooks, gcodeVersionHook)
+	case boil.BeforeUpsertHook:
+		gcodeVersionBeforeUpsertHooks = append(gcodeVersionBeforeUpsertHooks, gcodeVersionHook)
+	case boil.AfterInsertHook:
+		gcodeVersionAfterInsertHooks = append(gcodeVersionAfterInsertHooks, gcodeVersionHook)
+	case boil.AfterSelectHook:
+		gcodeVersionAfterSelectHooks = append(gcodeVersionAfterSelectHooks, gcodeVersionHook)
+	case boil.AfterUpdateHook:
+		gcodeVersionAfterUpdateHooks = append(gcodeVersionAfterUpdateHooks, gcodeVersionHook)
+	case boil.AfterDeleteHook:
+		gcodeVersionAfterDeleteHooks = append(gcodeVersionAfterDeleteHooks, gcodeVersionHook)
+	case boil.AfterUpsertHook:
+		gcodeVersionAfterUpsertHooks = append(gcodeVersionAfterUpsertHooks, gcodeVersionHook)
+	}
+}
+
+// OneG returns a single gcodeVersion record from the query using the global executor.
+func (q gcodeVersionQuery) OneG() (*GcodeVersion, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single gcodeVersion record from the query.
+func (q gcodeVersionQuery) One(exec boil.Executor) (*GcodeVersion, error) {
+	o := &GcodeVersion{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for gcode_versions")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all GcodeVersion records from the query using the global executor.
+func (q gcodeVersionQuery) AllG() (GcodeVersionSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all GcodeVersion records from the query.
+func (q gcodeVersionQuery) All(exec boil.Executor) (GcodeVersionSlice, error) {
+	var o []*GcodeVersion
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to GcodeVersion slice")
+	}
+
+	if len(gcodeVersionAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all GcodeVersion records in the query, and panics on error.
+func (q gcodeVersionQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all GcodeVersion records in the query.
+func (q gcodeVersionQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count gcode_versions rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q gcodeVersionQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q gcodeVersionQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if gcode_versions exists")
+	}
+
+	return count > 0, nil
+}
+
+// Gcode pointed to by the foreign key.
+func (o *GcodeVersion) Gcode(mods ...qm.QueryMod) gcodeQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.GcodeID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Gcodes(queryMods...)
+	queries.SetFrom(query.Query, "\"gcodes\"")
+
+	return query
+}
+
+// Blob pointed to by the foreign key.
+func (o *GcodeVersion) Blob(mods ...qm.QueryMod) blobQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.BlobID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Blobs(queryMods...)
+	queries.SetFrom(query.Query, "\"blobs\"")
+
+	return query
+}
+
+// LoadGcode allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (gcodeVersionL) LoadGcode(e boil.Executor, singular bool, maybeGcodeVersion interface{}, mods queries.Applicator) error {
+	var slice []*GcodeVersion
+	var object *GcodeVersion
+
+	if singular {
+		object = maybeGcodeVersion.(*GcodeVersion)
+	} else {
+		slice = *maybeGcodeVersion.(*[]*GcodeVersion)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &gcodeVersionR{}
+		}
+		args = append(args, object.GcodeID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &gcodeVersionR{}
+			}
+
+			for _, a := range args {
+				if a == obj.GcodeID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.GcodeID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`gcodes`),
+		qm.WhereIn(`gcodes.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Gcode")
+	}
+
+	var resultSlice []*Gcode
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Gcode")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for gcodes")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for gcodes")
+	}
+
+	if len(gcodeVersionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Gcode = foreign
+		if foreign.R == nil {
+			foreign.R = &gcodeR{}
+		}
+		foreign.R.GcodeVersions = append(foreign.R.GcodeVersions, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.GcodeID == foreign.ID {
+				local.R.Gcode = foreign
+				if foreign.R == nil {
+					foreign.R = &gcodeR{}
+				}
+				foreign.R.GcodeVersions = append(foreign.R.GcodeVersions, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadBlob allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (gcodeVersionL) LoadBlob(e boil.Executor, singular bool, maybeGcodeVersion interface{}, mods queries.Applicator) error {
+	var slice []*GcodeVersion
+	var object *GcodeVersion
+
+	if singular {
+		object = maybeGcodeVersion.(*GcodeVersion)
+	} else {
+		slice = *maybeGcodeVersion.(*[]*GcodeVersion)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &gcodeVersionR{}
+		}
+		args = append(args, object.BlobID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &gcodeVersionR{}
+			}
+
+			for _, a := range args {
+				if a == obj.BlobID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.BlobID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`blobs`),
+		qm.WhereIn(`blobs.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Blob")
+	}
+
+	var resultSlice []*Blob
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Blob")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for blobs")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for blobs")
+	}
+
+	if len(gcodeVersionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Blob = foreign
+		if foreign.R == nil {
+			foreign.R = &blobR{}
+		}
+		foreign.R.GcodeVersions = append(foreign.R.GcodeVersions, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.BlobID == foreign.ID {
+				local.R.Blob = foreign
+				if foreign.R == nil {
+					foreign.R = &blobR{}
+				}
+				foreign.R.GcodeVersions = append(foreign.R.GcodeVersions, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetGcodeG of the gcodeVersion to the related item.
+// Sets o.R.Gcode to related.
+// Adds o to related.R.GcodeVersions.
+// Uses the global database handle.
+func (o *GcodeVersion) SetGcodeG(insert bool, related *Gcode) error {
+	return o.SetGcode(boil.GetDB(), insert, related)
+}
+
+// SetGcode of the gcodeVersion to the related item.
+// Sets o.R.Gcode to related.
+// Adds o to related.R.GcodeVersions.
+func (o *GcodeVersion) SetGcode(exec boil.Executor, insert bool, related *Gcode) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"gcode_versions\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"gcode_id"}),
+		strmangle.WhereClause("\"", "\"", 2, gcodeVersionPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.GcodeID = related.ID
+	if o.R == nil {
+		o.R = &gcodeVersionR{
+			Gcode: related,
+		}
+	} else {
+		o.R.Gcode = related
+	}
+
+	if related.R == nil {
+		related.R = &gcodeR{
+			GcodeVersions: GcodeVersionSlice{o},
+		}
+	} else {
+		related.R.GcodeVersions = append(related.R.GcodeVersions, o)
+	}
+
+	return nil
+}
+
+// SetBlobG of the gcodeVersion to the related item.
+// Sets o.R.Blob to related.
+// Adds o to related.R.GcodeVersions.
+// Uses the global database handle.
+func (o *GcodeVersion) SetBlobG(insert bool, related *Blob) error {
+	return o.SetBlob(boil.GetDB(), insert, related)
+}
+
+// SetBlob of the gcodeVersion to the related item.
+// Sets o.R.Blob to related.
+// Adds o to related.R.GcodeVersions.
+func (o *GcodeVersion) SetBlob(exec boil.Executor, insert bool, related *Blob) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"gcode_versions\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"blob_id"}),
+		strmangle.WhereClause("\"", "\"", 2, gcodeVersionPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.BlobID = related.ID
+	if o.R == nil {
+		o.R = &gcodeVersionR{
+			Blob: related,
+		}
+	} else {
+		o.R.Blob = related
+	}
+
+	if related.R == nil {
+		related.R = &blobR{
+			GcodeVersions: GcodeVersionSlice{o},
+		}
+	} else {
+		related.R.GcodeVersions = append(related.R.GcodeVersions, o)
+	}
+
+	return nil
+}
+
+// GcodeVersions retrieves all the records using an executor.
+func GcodeVersions(mods ...qm.QueryMod) gcodeVersionQuery {
+	mods = append(mods, qm.From("\"gcode_versions\""))
+	return gcodeVersionQuery{NewQuery(mods...)}
+}
+
+// FindGcodeVersionG retrieves a single record by ID.
+func FindGcodeVersionG(iD string, selectCols ...string) (*GcodeVersion, error) {
+	return FindGcodeVersion(boil.GetDB(), iD, selectCols...)
+}
+
+// FindGcodeVersion retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindGcodeVersion(exec boil.Executor, iD string, selectCols ...string) (*GcodeVersion, error) {
+	gcodeVersionObj := &GcodeVersion{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"gcode_versions\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, gcodeVersionObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from gcode_versions")
+	}
+
+	return gcodeVersionObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *GcodeVersion) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *GcodeVersion) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no gcode_versions provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(gcodeVersionColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	gcodeVersionInsertCacheMut.RLock()
+	cache, cached := gcodeVersionInsertCache[key]
+	gcodeVersionInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			gcodeVersionAllColumns,
+			gcodeVersionColumnsWithDefault,
+			gcodeVersionColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(gcodeVersionType, gcodeVersionMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(gcodeVersionType, gcodeVersionMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"gcode_versions\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"gcode_versions\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into gcode_versions")
+	}
+
+	if !cached {
+		gcodeVersionInsertCacheMut.Lock()
+		gcodeVersionInsertCache[key] = cache
+		gcodeVersionInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single GcodeVersion record using the global executor.
+// See Update for more documentation.
+func (o *GcodeVersion) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the GcodeVersion.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *GcodeVersion) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	gcodeVersionUpdateCacheMut.RLock()
+	cache, cached := gcodeVersionUpdateCache[key]
+	gcodeVersionUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			gcodeVersionAllColumns,
+			gcodeVersionPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update gcode_versions, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"gcode_versions\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, gcodeVersionPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(gcodeVersionType, gcodeVersionMapping, append(wl, gcodeVersionPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update gcode_versions row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for gcode_versions")
+	}
+
+	if !cached {
+		gcodeVersionUpdateCacheMut.Lock()
+		gcodeVersionUpdateCache[key] = cache
+		gcodeVersionUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q gcodeVersionQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q gcodeVersionQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for gcode_versions")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for gcode_versions")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o GcodeVersionSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o GcodeVersionSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), gcodeVersionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"gcode_versions\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, gcodeVersionPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in gcodeVersion slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all gcodeVersion")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *GcodeVersion) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *GcodeVersion) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no gcode_versions provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(gcodeVersionColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	gcodeVersionUpsertCacheMut.RLock()
+	cache, cached := gcodeVersionUpsertCache[key]
+	gcodeVersionUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			gcodeVersionAllColumns,
+			gcodeVersionColumnsWithDefault,
+			gcodeVersionColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			gcodeVersionAllColumns,
+			gcodeVersionPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert gcode_versions, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(gcodeVersionPrimaryKeyColumns))
+			copy(conflict, gcodeVersionPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"gcode_versions\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(gcodeVersionType, gcodeVersionMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(gcodeVersionType, gcodeVersionMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert gcode_versions")
+	}
+
+	if !cached {
+		gcodeVersionUpsertCacheMut.Lock()
+		gcodeVersionUpsertCache[key] = cache
+		gcodeVersionUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single GcodeVersion record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *GcodeVersion) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single GcodeVersion record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *GcodeVersion) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no GcodeVersion provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), gcodeVersionPrimaryKeyMapping)
+	sql := "DELETE FROM \"gcode_versions\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from gcode_versions")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for gcode_versions")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q gcodeVersionQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q gcodeVersionQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no gcodeVersionQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from gcode_versions")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for gcode_versions")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o GcodeVersionSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o GcodeVersionSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(gcodeVersionBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), gcodeVersionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"gcode_versions\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, gcodeVersionPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from gcodeVersion slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for gcode_versions")
+	}
+
+	if len(gcodeVersionAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *GcodeVersion) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no GcodeVersion provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *GcodeVersion) Reload(exec boil.Executor) error {
+	ret, err := FindGcodeVersion(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *GcodeVersionSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty GcodeVersionSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *GcodeVersionSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := GcodeVersionSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), gcodeVersionPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"gcode_versions\".* FROM \"gcode_versions\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, gcodeVersionPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in GcodeVersionSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// GcodeVersionExistsG checks if the GcodeVersion row exists.
+func GcodeVersionExistsG(iD string) (bool, error) {
+	return GcodeVersionExists(boil.GetDB(), iD)
+}
+
+// GcodeVersionExists checks if the GcodeVersion row exists.
+func GcodeVersionExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"gcode_versions\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if gcode_versions exists")
+	}
+
+	return exists, nil
+}
@@ -18,66 +18,127 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"github.com/volatiletech/strmangle"
 )
 
 // Gcode is an object representing the database table.
 type Gcode struct {
-	ID        string    `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
-	Name      string    `db:"name" boil:"name" json:"name" toml:"name" yaml:"name"`
-	BlobID    string    `db:"blob_id" boil:"blob_id" json:"blob_id" toml:"blob_id" yaml:"blob_id"`
-	DeletedAt null.Time `db:"deleted_at" boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
-	UpdatedAt time.Time `db:"updated_at" boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
-	CreatedAt time.Time `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	ID              string            `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name            string            `db:"name" boil:"name" json:"name" toml:"name" yaml:"name"`
+	BlobID          string            `db:"blob_id" boil:"blob_id" json:"blob_id" toml:"blob_id" yaml:"blob_id"`
+	DeletedAt       null.Time         `db:"deleted_at" boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	UpdatedAt       time.Time         `db:"updated_at" boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	CreatedAt       time.Time         `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	FolderID        null.String       `db:"folder_id" boil:"folder_id" json:"folder_id,omitempty" toml:"folder_id" yaml:"folder_id,omitempty"`
+	Tags            types.StringArray `db:"tags" boil:"tags" json:"tags" toml:"tags" yaml:"tags"`
+	Version         int               `db:"version" boil:"version" json:"version" toml:"version" yaml:"version"`
+	Format          string            `db:"format" boil:"format" json:"format" toml:"format" yaml:"format"`
+	Metadata        types.JSON        `db:"metadata" boil:"metadata" json:"metadata" toml:"metadata" yaml:"metadata"`
+	ThumbnailBlobID null.String       `db:"thumbnail_blob_id" boil:"thumbnail_blob_id" json:"thumbnail_blob_id,omitempty" toml:"thumbnail_blob_id" yaml:"thumbnail_blob_id,omitempty"`
 
 	R *gcodeR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
 	L gcodeL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var GcodeColumns = struct {
-	ID        string
-	Name      string
-	BlobID    string
-	DeletedAt string
-	UpdatedAt string
-	CreatedAt string
+	ID              string
+	Name            string
+	BlobID          string
+	DeletedAt       string
+	UpdatedAt       string
+	CreatedAt       string
+	FolderID        string
+	Tags            string
+	Version         string
+	Format          string
+	Metadata        string
+	ThumbnailBlobID string
 }{
-	ID:        "id",
-	Name:      "name",
-	BlobID:    "blob_id",
-	DeletedAt: "deleted_at",
-	UpdatedAt: "updated_at",
-	CreatedAt: "created_at",
+	ID:              "id",
+	Name:            "name",
+	BlobID:          "blob_id",
+	DeletedAt:       "deleted_at",
+	UpdatedAt:       "updated_at",
+	CreatedAt:       "created_at",
+	FolderID:        "folder_id",
+	Tags:            "tags",
+	Version:         "version",
+	Format:          "format",
+	Metadata:        "metadata",
+	ThumbnailBlobID: "thumbnail_blob_id",
 }
 
 // Generated where
 
+type whereHelpertypes_StringArray struct{ field string }
+
+func (w whereHelpertypes_StringArray) EQ(x types.StringArray) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelpertypes_StringArray) NEQ(x types.StringArray) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelpertypes_StringArray) LT(x types.StringArray) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpertypes_StringArray) LTE(x types.StringArray) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpertypes_StringArray) GT(x types.StringArray) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpertypes_StringArray) GTE(x types.StringArray) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
 var GcodeWhere = struct {
-	ID        whereHelperstring
-	Name      whereHelperstring
-	BlobID    whereHelperstring
-	DeletedAt whereHelpernull_Time
-	UpdatedAt whereHelpertime_Time
-	CreatedAt whereHelpertime_Time
+	ID              whereHelperstring
+	Name            whereHelperstring
+	BlobID          whereHelperstring
+	DeletedAt       whereHelpernull_Time
+	UpdatedAt       whereHelpertime_Time
+	CreatedAt       whereHelpertime_Time
+	FolderID        whereHelpernull_String
+	Tags            whereHelpertypes_StringArray
+	Version         whereHelperint
+	Format          whereHelperstring
+	Metadata        whereHelpertypes_JSON
+	ThumbnailBlobID whereHelpernull_String
 }{
-	ID:        whereHelperstring{field: "\"gcodes\".\"id\""},
-	Name:      whereHelperstring{field: "\"gcodes\".\"name\""},
-	BlobID:    whereHelperstring{field: "\"gcodes\".\"blob_id\""},
-	DeletedAt: whereHelpernull_Time{field: "\"gcodes\".\"deleted_at\""},
-	UpdatedAt: whereHelpertime_Time{field: "\"gcodes\".\"updated_at\""},
-	CreatedAt: whereHelpertime_Time{field: "\"gcodes\".\"created_at\""},
+	ID:              whereHelperstring{field: "\"gcodes\".\"id\""},
+	Name:            whereHelperstring{field: "\"gcodes\".\"name\""},
+	BlobID:          whereHelperstring{field: "\"gcodes\".\"blob_id\""},
+	DeletedAt:       whereHelpernull_Time{field: "\"gcodes\".\"deleted_at\""},
+	UpdatedAt:       whereHelpertime_Time{field: "\"gcodes\".\"updated_at\""},
+	CreatedAt:       whereHelpertime_Time{field: "\"gcodes\".\"created_at\""},
+	FolderID:        whereHelpernull_String{field: "\"gcodes\".\"folder_id\""},
+	Tags:            whereHelpertypes_StringArray{field: "\"gcodes\".\"tags\""},
+	Version:         whereHelperint{field: "\"gcodes\".\"version\""},
+	Format:          whereHelperstring{field: "\"gcodes\".\"format\""},
+	Metadata:        whereHelpertypes_JSON{field: "\"gcodes\".\"metadata\""},
+	ThumbnailBlobID: whereHelpernull_String{field: "\"gcodes\".\"thumbnail_blob_id\""},
 }
 
 // GcodeRels is where relationship names are stored.
 var GcodeRels = struct {
-	Blob string
+	Blob          string
+	Folder        string
+	ThumbnailBlob string
+	GcodeVersions string
 }{
-	Blob: "Blob",
+	Blob:          "Blob",
+	Folder:        "Folder",
+	ThumbnailBlob: "ThumbnailBlob",
+	GcodeVersions: "GcodeVersions",
 }
 
 // gcodeR is where relationships are stored.
 type gcodeR struct {
-	Blob *Blob `db:"Blob" boil:"Blob" json:"Blob" toml:"Blob" yaml:"Blob"`
+	Blob          *Blob             `db:"Blob" boil:"Blob" json:"Blob" toml:"Blob" yaml:"Blob"`
+	Folder        *Folder           `db:"Folder" boil:"Folder" json:"Folder" toml:"Folder" yaml:"Folder"`
+	ThumbnailBlob *Blob             `db:"ThumbnailBlob" boil:"ThumbnailBlob" json:"ThumbnailBlob" toml:"ThumbnailBlob" yaml:"ThumbnailBlob"`
+	GcodeVersions GcodeVersionSlice `db:"GcodeVersions" boil:"GcodeVersions" json:"GcodeVersions" toml:"GcodeVersions" yaml:"GcodeVersions"`
 }
 
 // NewStruct creates a new relationship struct
@@ -89,9 +150,9 @@ func (*gcodeR) NewStruct() *gcodeR {
 type gcodeL struct{}
 
 var (
-	gcodeAllColumns            = []string{"id", "name", "blob_id", "deleted_at", "updated_at", "created_at"}
-	gcodeColumnsWithoutDefault = []string{"name", "blob_id", "deleted_at"}
-	gcodeColumnsWithDefault    = []string{"id", "updated_at", "created_at"}
+	gcodeAllColumns            = []string{"id", "name", "blob_id", "deleted_at", "updated_at", "created_at", "folder_id", "tags", "version", "format", "metadata", "thumbnail_blob_id"}
+	gcodeColumnsWithoutDefault = []string{"name", "blob_id", "deleted_at", "folder_id", "thumbnail_blob_id"}
+	gcodeColumnsWithDefault    = []string{"id", "updated_at", "created_at", "tags", "version", "format", "metadata"}
 	gcodePrimaryKeyColumns     = []string{"id"}
 )
 
@@ -368,6 +429,55 @@ func (o *Gcode) Blob(mods ...qm.QueryMod) blobQuery {
 	return query
 }
 
+// Folder pointed to by the foreign key.
+func (o *Gcode) Folder(mods ...qm.QueryMod) folderQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.FolderID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Folders(queryMods...)
+	queries.SetFrom(query.Query, "\"folders\"")
+
+	return query
+}
+
+// ThumbnailBlob pointed to by the foreign key.
+func (o *Gcode) ThumbnailBlob(mods ...qm.QueryMod) blobQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.ThumbnailBlobID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Blobs(queryMods...)
+	queries.SetFrom(query.Query, "\"blobs\"")
+
+	return query
+}
+
+// GcodeVersions retrieves all the gcode_version's GcodeVersions with an executor.
+func (o *Gcode) GcodeVersions(mods ...qm.QueryMod) gcodeVersionQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"gcode_versions\".\"gcode_id\"=?", o.ID),
+	)
+
+	query := GcodeVersions(queryMods...)
+	queries.SetFrom(query.Query, "\"gcode_versions\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"gcode_versions\".*"})
+	}
+
+	return query
+}
+
 // LoadBlob allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for an N-1 relationship.
 func (gcodeL) LoadBlob(e boil.Executor, singular bool, maybeGcode interface{}, mods queries.Applicator) error {
@@ -472,6 +582,320 @@ func (gcodeL) LoadBlob(e boil.Executor, singular bool, maybeGcode interface{}, m
 	return nil
 }
 
+// LoadFolder allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (gcodeL) LoadFolder(e boil.Executor, singular bool, maybeGcode interface{}, mods queries.Applicator) error {
+	var slice []*Gcode
+	var object *Gcode
+
+	if singular {
+		object = maybeGcode.(*Gcode)
+	} else {
+		slice = *maybeGcode.(*[]*Gcode)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &gcodeR{}
+		}
+		if !queries.IsNil(object.FolderID) {
+			args = append(args, object.FolderID)
+		}
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &gcodeR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.FolderID) {
+					continue Outer
+				}
+			}
+
+			if !queries.IsNil(obj.FolderID) {
+				args = append(args, obj.FolderID)
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`folders`),
+		qm.WhereIn(`folders.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Folder")
+	}
+
+	var resultSlice []*Folder
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Folder")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for folders")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for folders")
+	}
+
+	if len(gcodeAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Folder = foreign
+		if foreign.R == nil {
+			foreign.R = &folderR{}
+		}
+		foreign.R.Gcodes = append(foreign.R.Gcodes, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.FolderID, foreign.ID) {
+				local.R.Folder = foreign
+				if foreign.R == nil {
+					foreign.R = &folderR{}
+				}
+				foreign.R.Gcodes = append(foreign.R.Gcodes, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadThumbnailBlob allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (gcodeL) LoadThumbnailBlob(e boil.Executor, singular bool, maybeGcode interface{}, mods queries.Applicator) error {
+	var slice []*Gcode
+	var object *Gcode
+
+	if singular {
+		object = maybeGcode.(*Gcode)
+	} else {
+		slice = *maybeGcode.(*[]*Gcode)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &gcodeR{}
+		}
+		if !queries.IsNil(object.ThumbnailBlobID) {
+			args = append(args, object.ThumbnailBlobID)
+		}
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &gcodeR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ThumbnailBlobID) {
+					continue Outer
+				}
+			}
+
+			if !queries.IsNil(obj.ThumbnailBlobID) {
+				args = append(args, obj.ThumbnailBlobID)
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`blobs`),
+		qm.WhereIn(`blobs.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Blob")
+	}
+
+	var resultSlice []*Blob
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Blob")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for blobs")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for blobs")
+	}
+
+	if len(gcodeAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.ThumbnailBlob = foreign
+		if foreign.R == nil {
+			foreign.R = &blobR{}
+		}
+		foreign.R.ThumbnailBlobGcodes = append(foreign.R.ThumbnailBlobGcodes, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.ThumbnailBlobID, foreign.ID) {
+				local.R.ThumbnailBlob = foreign
+				if foreign.R == nil {
+					foreign.R = &blobR{}
+				}
+				foreign.R.ThumbnailBlobGcodes = append(foreign.R.ThumbnailBlobGcodes, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadGcodeVersions allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (gcodeL) LoadGcodeVersions(e boil.Executor, singular bool, maybeGcode interface{}, mods queries.Applicator) error {
+	var slice []*Gcode
+	var object *Gcode
+
+	if singular {
+		object = maybeGcode.(*Gcode)
+	} else {
+		slice = *maybeGcode.(*[]*Gcode)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &gcodeR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &gcodeR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`gcode_versions`),
+		qm.WhereIn(`gcode_versions.gcode_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load gcode_versions")
+	}
+
+	var resultSlice []*GcodeVersion
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice gcode_versions")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on gcode_versions")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for gcode_versions")
+	}
+
+	if len(gcodeVersionAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.GcodeVersions = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &gcodeVersionR{}
+			}
+			foreign.R.Gcode = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.GcodeID {
+				local.R.GcodeVersions = append(local.R.GcodeVersions, foreign)
+				if foreign.R == nil {
+					foreign.R = &gcodeVersionR{}
+				}
+				foreign.R.Gcode = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // SetBlobG of the gcode to the related item.
 // Sets o.R.Blob to related.
 // Adds o to related.R.Gcodes.
@@ -526,6 +950,257 @@ func (o *Gcode) SetBlob(exec boil.Executor, insert bool, related *Blob) error {
 	return nil
 }
 
+// SetFolderG of the gcode to the related item.
+// Sets o.R.Folder to related.
+// Adds o to related.R.Gcodes.
+// Uses the global database handle.
+func (o *Gcode) SetFolderG(insert bool, related *Folder) error {
+	return o.SetFolder(boil.GetDB(), insert, related)
+}
+
+// SetFolder of the gcode to the related item.
+// Sets o.R.Folder to related.
+// Adds o to related.R.Gcodes.
+func (o *Gcode) SetFolder(exec boil.Executor, insert bool, related *Folder) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"gcodes\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"folder_id"}),
+		strmangle.WhereClause("\"", "\"", 2, gcodePrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.FolderID, related.ID)
+	if o.R == nil {
+		o.R = &gcodeR{
+			Folder: related,
+		}
+	} else {
+		o.R.Folder = related
+	}
+
+	if related.R == nil {
+		related.R = &folderR{
+			Gcodes: GcodeSlice{o},
+		}
+	} else {
+		related.R.Gcodes = append(related.R.Gcodes, o)
+	}
+
+	return nil
+}
+
+// RemoveFolderG relationship.
+// Sets o.R.Folder to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+// Uses the global database handle.
+func (o *Gcode) RemoveFolderG(related *Folder) error {
+	return o.RemoveFolder(boil.GetDB(), related)
+}
+
+// RemoveFolder relationship.
+// Sets o.R.Folder to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+func (o *Gcode) RemoveFolder(exec boil.Executor, related *Folder) error {
+	var err error
+
+	queries.SetScanner(&o.FolderID, nil)
+	if _, err = o.Update(exec, boil.Whitelist("folder_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.Folder = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.Gcodes {
+		if queries.Equal(o.FolderID, ri.FolderID) {
+			continue
+		}
+
+		ln := len(related.R.Gcodes)
+		if ln > 1 && i < ln-1 {
+			related.R.Gcodes[i] = related.R.Gcodes[ln-1]
+		}
+		related.R.Gcodes = related.R.Gcodes[:ln-1]
+		break
+	}
+	return nil
+}
+
+// SetThumbnailBlobG of the gcode to the related item.
+// Sets o.R.ThumbnailBlob to related.
+// Adds o to related.R.ThumbnailBlobGcodes.
+// Uses the global database handle.
+func (o *Gcode) SetThumbnailBlobG(insert bool, related *Blob) error {
+	return o.SetThumbnailBlob(boil.GetDB(), insert, related)
+}
+
+// SetThumbnailBlob of the gcode to the related item.
+// Sets o.R.ThumbnailBlob to related.
+// Adds o to related.R.ThumbnailBlobGcodes.
+func (o *Gcode) SetThumbnailBlob(exec boil.Executor, insert bool, related *Blob) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"gcodes\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"thumbnail_blob_id"}),
+		strmangle.WhereClause("\"", "\"", 2, gcodePrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.ThumbnailBlobID, related.ID)
+	if o.R == nil {
+		o.R = &gcodeR{
+			ThumbnailBlob: related,
+		}
+	} else {
+		o.R.ThumbnailBlob = related
+	}
+
+	if related.R == nil {
+		related.R = &blobR{
+			ThumbnailBlobGcodes: GcodeSlice{o},
+		}
+	} else {
+		related.R.ThumbnailBlobGcodes = append(related.R.ThumbnailBlobGcodes, o)
+	}
+
+	return nil
+}
+
+// RemoveThumbnailBlobG relationship.
+// Sets o.R.ThumbnailBlob to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+// Uses the global database handle.
+func (o *Gcode) RemoveThumbnailBlobG(related *Blob) error {
+	return o.RemoveThumbnailBlob(boil.GetDB(), related)
+}
+
+// RemoveThumbnailBlob relationship.
+// Sets o.R.ThumbnailBlob to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+func (o *Gcode) RemoveThumbnailBlob(exec boil.Executor, related *Blob) error {
+	var err error
+
+	queries.SetScanner(&o.ThumbnailBlobID, nil)
+	if _, err = o.Update(exec, boil.Whitelist("thumbnail_blob_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.ThumbnailBlob = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.ThumbnailBlobGcodes {
+		if queries.Equal(o.ThumbnailBlobID, ri.ThumbnailBlobID) {
+			continue
+		}
+
+		ln := len(related.R.ThumbnailBlobGcodes)
+		if ln > 1 && i < ln-1 {
+			related.R.ThumbnailBlobGcodes[i] = related.R.ThumbnailBlobGcodes[ln-1]
+		}
+		related.R.ThumbnailBlobGcodes = related.R.ThumbnailBlobGcodes[:ln-1]
+		break
+	}
+	return nil
+}
+
+// AddGcodeVersionsG adds the given related objects to the existing relationships
+// of the gcode, optionally inserting them as new records.
+// Appends related to o.R.GcodeVersions.
+// Sets related.R.Gcode appropriately.
+// Uses the global database handle.
+func (o *Gcode) AddGcodeVersionsG(insert bool, related ...*GcodeVersion) error {
+	return o.AddGcodeVersions(boil.GetDB(), insert, related...)
+}
+
+// AddGcodeVersions adds the given related objects to the existing relationships
+// of the gcode, optionally inserting them as new records.
+// Appends related to o.R.GcodeVersions.
+// Sets related.R.Gcode appropriately.
+func (o *Gcode) AddGcodeVersions(exec boil.Executor, insert bool, related ...*GcodeVersion) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.GcodeID = o.ID
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"gcode_versions\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"gcode_id"}),
+				strmangle.WhereClause("\"", "\"", 2, gcodeVersionPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.GcodeID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &gcodeR{
+			GcodeVersions: related,
+		}
+	} else {
+		o.R.GcodeVersions = append(o.R.GcodeVersions, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &gcodeVersionR{
+				Gcode: o,
+			}
+		} else {
+			rel.R.Gcode = o
+		}
+	}
+	return nil
+}
+
 // Gcodes retrieves all the records using an executor.
 func Gcodes(mods ...qm.QueryMod) gcodeQuery {
 	mods = append(mods, qm.From("\"gcodes\""))
@@ -0,0 +1,1403 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// Printer is an object representing the database table.
+type Printer struct {
+	ID        string    `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name      string    `db:"name" boil:"name" json:"name" toml:"name" yaml:"name"`
+	DeletedAt null.Time `db:"deleted_at" boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
+	UpdatedAt time.Time `db:"updated_at" boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	CreatedAt time.Time `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *printerR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L printerL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var PrinterColumns = struct {
+	ID        string
+	Name      string
+	DeletedAt string
+	UpdatedAt string
+	CreatedAt string
+}{
+	ID:        "id",
+	Name:      "name",
+	DeletedAt: "deleted_at",
+	UpdatedAt: "updated_at",
+	CreatedAt: "created_at",
+}
+
+// Generated where
+
+var PrinterWhere = struct {
+	ID        whereHelperstring
+	Name      whereHelperstring
+	DeletedAt whereHelpernull_Time
+	UpdatedAt whereHelpertime_Time
+	CreatedAt whereHelpertime_Time
+}{
+	ID:        whereHelperstring{field: "\"printers\".\"id\""},
+	Name:      whereHelperstring{field: "\"printers\".\"name\""},
+	DeletedAt: whereHelpernull_Time{field: "\"printers\".\"deleted_at\""},
+	UpdatedAt: whereHelpertime_Time{field: "\"printers\".\"updated_at\""},
+	CreatedAt: whereHelpertime_Time{field: "\"printers\".\"created_at\""},
+}
+
+// PrinterRels is where relationship names are stored.
+var PrinterRels = struct {
+	AgentTokens string
+	AuditEvents string
+}{
+	AgentTokens: "AgentTokens",
+	AuditEvents: "AuditEvents",
+}
+
+// printerR is where relationships are stored.
+type printerR struct {
+	AgentTokens AgentTokenSlice `db:"AgentTokens" boil:"AgentTokens" json:"AgentTokens" toml:"AgentTokens" yaml:"AgentTokens"`
+	AuditEvents AuditEventSlice `db:"AuditEvents" boil:"AuditEvents" json:"AuditEvents" toml:"AuditEvents" yaml:"AuditEvents"`
+}
+
+// NewStruct creates a new relationship struct
+func (*printerR) NewStruct() *printerR {
+	return &printerR{}
+}
+
+// printerL is where Load methods for each relationship are stored.
+type printerL struct{}
+
+var (
+	printerAllColumns            = []string{"id", "name", "deleted_at", "updated_at", "created_at"}
+	printerColumnsWithoutDefault = []string{"name", "deleted_at"}
+	printerColumnsWithDefault    = []string{"id", "updated_at", "created_at"}
+	printerPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// PrinterSlice is an alias for a slice of pointers to Printer.
+	// This should generally be used opposed to []Printer.
+	PrinterSlice []*Printer
+	// PrinterHook is the signature for custom Printer hook methods
+	PrinterHook func(boil.Executor, *Printer) error
+
+	printerQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	printerType                 = reflect.TypeOf(&Printer{})
+	printerMapping              = queries.MakeStructMapping(printerType)
+	printerPrimaryKeyMapping, _ = queries.BindMapping(printerType, printerMapping, printerPrimaryKeyColumns)
+	printerInsertCacheMut       sync.RWMutex
+	printerInsertCache          = make(map[string]insertCache)
+	printerUpdateCacheMut       sync.RWMutex
+	printerUpdateCache          = make(map[string]updateCache)
+	printerUpsertCacheMut       sync.RWMutex
+	printerUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var printerBeforeInsertHooks []PrinterHook
+var printerBeforeUpdateHooks []PrinterHook
+var printerBeforeDeleteHooks []PrinterHook
+var printerBeforeUpsertHooks []PrinterHook
+
+var printerAfterInsertHooks []PrinterHook
+var printerAfterSelectHooks []PrinterHook
+var printerAfterUpdateHooks []PrinterHook
+var printerAfterDeleteHooks []PrinterHook
+var printerAfterUpsertHooks []PrinterHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *Printer) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *Printer) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *Printer) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *Printer) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *Printer) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *Printer) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *Printer) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *Printer) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *Printer) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range printerAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddPrinterHook registers your hook function for all future operations.
+func AddPrinterHook(hookPoint boil.HookPoint, printerHook PrinterHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		printerBeforeInsertHooks = append(printerBeforeInsertHooks, printerHook)
+	case boil.BeforeUpdateHook:
+		printerBeforeUpdateHooks = append(printerBeforeUpdateHooks, printerHook)
+	case boil.BeforeDeleteHook:
+		printerBeforeDeleteHooks = append(printerBeforeDeleteHooks, printerHook)
+	case boil.BeforeUpsertHook:
+		printerBeforeUpsertHooks = append(printerBeforeUpsertHooks, printerHook)
+	case boil.AfterInsertHook:
+		printerAfterInsertHooks = append(printerAfterInsertHooks, printerHook)
+	case boil.AfterSelectHook:
+		printerAfterSelectHooks = append(printerAfterSelectHooks, printerHook)
+	case boil.AfterUpdateHook:
+		printerAfterUpdateHooks = append(printerAfterUpdateHooks, printerHook)
+	case boil.AfterDeleteHook:
+		printerAfterDeleteHooks = append(printerAfterDeleteHooks, printerHook)
+	case boil.AfterUpsertHook:
+		printerAfterUpsertHooks = append(printerAfterUpsertHooks, printerHook)
+	}
+}
+
+// OneG returns a single printer record from the query using the global executor.
+func (q printerQuery) OneG() (*Printer, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single printer record from the query.
+func (q printerQuery) One(exec boil.Executor) (*Printer, error) {
+	o := &Printer{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for printers")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all Printer records from the query using the global executor.
+func (q printerQuery) AllG() (PrinterSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all Printer records from the query.
+func (q printerQuery) All(exec boil.Executor) (PrinterSlice, error) {
+	var o []*Printer
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to Printer slice")
+	}
+
+	if len(printerAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all Printer records in the query, and panics on error.
+func (q printerQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all Printer records in the query.
+func (q printerQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count printers rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q printerQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q printerQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if printers exists")
+	}
+
+	return count > 0, nil
+}
+
+// AgentTokens retrieves all the agent_token's AgentTokens with an executor.
+func (o *Printer) AgentTokens(mods ...qm.QueryMod) agentTokenQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"agent_tokens\".\"printer_id\"=?", o.ID),
+	)
+
+	query := AgentTokens(queryMods...)
+	queries.SetFrom(query.Query, "\"agent_tokens\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"agent_tokens\".*"})
+	}
+
+	return query
+}
+
+// AuditEvents retrieves all the audit_event's AuditEvents with an executor.
+func (o *Printer) AuditEvents(mods ...qm.QueryMod) auditEventQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"audit_events\".\"printer_id\"=?", o.ID),
+	)
+
+	query := AuditEvents(queryMods...)
+	queries.SetFrom(query.Query, "\"audit_events\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"audit_events\".*"})
+	}
+
+	return query
+}
+
+// LoadAgentTokens allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (printerL) LoadAgentTokens(e boil.Executor, singular bool, maybePrinter interface{}, mods queries.Applicator) error {
+	var slice []*Printer
+	var object *Printer
+
+	if singular {
+		object = maybePrinter.(*Printer)
+	} else {
+		slice = *maybePrinter.(*[]*Printer)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &printerR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &printerR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`agent_tokens`),
+		qm.WhereIn(`agent_tokens.printer_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load agent_tokens")
+	}
+
+	var resultSlice []*AgentToken
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice agent_tokens")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on agent_tokens")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for agent_tokens")
+	}
+
+	if len(agentTokenAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.AgentTokens = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &agentTokenR{}
+			}
+			foreign.R.Printer = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.PrinterID {
+				local.R.AgentTokens = append(local.R.AgentTokens, foreign)
+				if foreign.R == nil {
+					foreign.R = &agentTokenR{}
+				}
+				foreign.R.Printer = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadAuditEvents allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (printerL) LoadAuditEvents(e boil.Executor, singular bool, maybePrinter interface{}, mods queries.Applicator) error {
+	var slice []*Printer
+	var object *Printer
+
+	if singular {
+		object = maybePrinter.(*Printer)
+	} else {
+		slice = *maybePrinter.(*[]*Printer)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &printerR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &printerR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`audit_events`),
+		qm.WhereIn(`audit_events.printer_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load audit_events")
+	}
+
+	var resultSlice []*AuditEvent
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice audit_events")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on audit_events")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for audit_events")
+	}
+
+	if len(auditEventAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.AuditEvents = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &auditEventR{}
+			}
+			foreign.R.Printer = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if queries.Equal(local.ID, foreign.PrinterID) {
+				local.R.AuditEvents = append(local.R.AuditEvents, foreign)
+				if foreign.R == nil {
+					foreign.R = &auditEventR{}
+				}
+				foreign.R.Printer = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddAgentTokensG adds the given related objects to the existing relationships
+// of the printer, optionally inserting them as new records.
+// Appends related to o.R.AgentTokens.
+// Sets related.R.Printer appropriately.
+// Uses the global database handle.
+func (o *Printer) AddAgentTokensG(insert bool, related ...*AgentToken) error {
+	return o.AddAgentTokens(boil.GetDB(), insert, related...)
+}
+
+// AddAgentTokens adds the given related objects to the existing relationships
+// of the printer, optionally inserting them as new records.
+// Appends related to o.R.AgentTokens.
+// Sets related.R.Printer appropriately.
+func (o *Printer) AddAgentTokens(exec boil.Executor, insert bool, related ...*AgentToken) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.PrinterID = o.ID
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"agent_tokens\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"printer_id"}),
+				strmangle.WhereClause("\"", "\"", 2, agentTokenPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.PrinterID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &printerR{
+			AgentTokens: related,
+		}
+	} else {
+		o.R.AgentTokens = append(o.R.AgentTokens, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &agentTokenR{
+				Printer: o,
+			}
+		} else {
+			rel.R.Printer = o
+		}
+	}
+	return nil
+}
+
+// AddAuditEventsG adds the given related objects to the existing relationships
+// of the printer, optionally inserting them as new records.
+// Appends related to o.R.AuditEvents.
+// Sets related.R.Printer appropriately.
+// Uses the global database handle.
+func (o *Printer) AddAuditEventsG(insert bool, related ...*AuditEvent) error {
+	return o.AddAuditEvents(boil.GetDB(), insert, related...)
+}
+
+// AddAuditEvents adds the given related objects to the existing relationships
+// of the printer, optionally inserting them as new records.
+// Appends related to o.R.AuditEvents.
+// Sets related.R.Printer appropriately.
+func (o *Printer) AddAuditEvents(exec boil.Executor, insert bool, related ...*AuditEvent) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			queries.Assign(&rel.PrinterID, o.ID)
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"audit_events\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"printer_id"}),
+				strmangle.WhereClause("\"", "\"", 2, auditEventPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			queries.Assign(&rel.PrinterID, o.ID)
+		}
+	}
+
+	if o.R == nil {
+		o.R = &printerR{
+			AuditEvents: related,
+		}
+	} else {
+		o.R.AuditEvents = append(o.R.AuditEvents, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &auditEventR{
+				Printer: o,
+			}
+		} else {
+			rel.R.Printer = o
+		}
+	}
+	return nil
+}
+
+// SetAuditEventsG removes all previously related items of the
+// printer replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Printer's AuditEvents accordingly.
+// Replaces o.R.AuditEvents with related.
+// Sets related.R.Printer's AuditEvents accordingly.
+// Uses the global database handle.
+func (o *Printer) SetAuditEventsG(insert bool, related ...*AuditEvent) error {
+	return o.SetAuditEvents(boil.GetDB(), insert, related...)
+}
+
+// SetAuditEvents removes all previously related items of the
+// printer replacing them completely with the passed
+// in related items, optionally inserting them as new records.
+// Sets o.R.Printer's AuditEvents accordingly.
+// Replaces o.R.AuditEvents with related.
+// Sets related.R.Printer's AuditEvents accordingly.
+func (o *Printer) SetAuditEvents(exec boil.Executor, insert bool, related ...*AuditEvent) error {
+	query := "update \"audit_events\" set \"printer_id\" = null where \"printer_id\" = $1"
+	values := []interface{}{o.ID}
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	_, err := exec.Exec(query, values...)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove relationships before set")
+	}
+
+	if o.R != nil {
+		for _, rel := range o.R.AuditEvents {
+			queries.SetScanner(&rel.PrinterID, nil)
+			if rel.R == nil {
+				continue
+			}
+
+			rel.R.Printer = nil
+		}
+
+		o.R.AuditEvents = nil
+	}
+	return o.AddAuditEvents(exec, insert, related...)
+}
+
+// RemoveAuditEventsG relationships from objects passed in.
+// Removes related items from R.AuditEvents (uses pointer comparison, removal does not keep order)
+// Sets related.R.Printer.
+// Uses the global database handle.
+func (o *Printer) RemoveAuditEventsG(related ...*AuditEvent) error {
+	return o.RemoveAuditEvents(boil.GetDB(), related...)
+}
+
+// RemoveAuditEvents relationships from objects passed in.
+// Removes related items from R.AuditEvents (uses pointer comparison, removal does not keep order)
+// Sets related.R.Printer.
+func (o *Printer) RemoveAuditEvents(exec boil.Executor, related ...*AuditEvent) error {
+	var err error
+	for _, rel := range related {
+		queries.SetScanner(&rel.PrinterID, nil)
+		if rel.R != nil {
+			rel.R.Printer = nil
+		}
+		if _, err = rel.Update(exec, boil.Whitelist("printer_id")); err != nil {
+			return err
+		}
+	}
+	if o.R == nil {
+		return nil
+	}
+
+	for _, rel := range related {
+		for i, ri := range o.R.AuditEvents {
+			if rel != ri {
+				continue
+			}
+
+			ln := len(o.R.AuditEvents)
+			if ln > 1 && i < ln-1 {
+				o.R.AuditEvents[i] = o.R.AuditEvents[ln-1]
+			}
+			o.R.AuditEvents = o.R.AuditEvents[:ln-1]
+			break
+		}
+	}
+
+	return nil
+}
+
+// Printers retrieves all the records using an executor.
+func Printers(mods ...qm.QueryMod) printerQuery {
+	mods = append(mods, qm.From("\"printers\""))
+	return printerQuery{NewQuery(mods...)}
+}
+
+// FindPrinterG retrieves a single record by ID.
+func FindPrinterG(iD string, selectCols ...string) (*Printer, error) {
+	return FindPrinter(boil.GetDB(), iD, selectCols...)
+}
+
+// FindPrinter retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindPrinter(exec boil.Executor, iD string, selectCols ...string) (*Printer, error) {
+	printerObj := &Printer{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"printers\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, printerObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from printers")
+	}
+
+	return printerObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *Printer) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *Printer) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no printers provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.UpdatedAt.IsZero() {
+		o.UpdatedAt = currTime
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(printerColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	printerInsertCacheMut.RLock()
+	cache, cached := printerInsertCache[key]
+	printerInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			printerAllColumns,
+			printerColumnsWithDefault,
+			printerColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(printerType, printerMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(printerType, printerMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"printers\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"printers\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into printers")
+	}
+
+	if !cached {
+		printerInsertCacheMut.Lock()
+		printerInsertCache[key] = cache
+		printerInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single Printer record using the global executor.
+// See Update for more documentation.
+func (o *Printer) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the Printer.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *Printer) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	currTime := time.Now().In(boil.GetLocation())
+
+	o.UpdatedAt = currTime
+
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	printerUpdateCacheMut.RLock()
+	cache, cached := printerUpdateCache[key]
+	printerUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			printerAllColumns,
+			printerPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update printers, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"printers\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, printerPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(printerType, printerMapping, append(wl, printerPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update printers row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for printers")
+	}
+
+	if !cached {
+		printerUpdateCacheMut.Lock()
+		printerUpdateCache[key] = cache
+		printerUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q printerQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q printerQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for printers")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for printers")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o PrinterSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o PrinterSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), printerPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"printers\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, printerPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in printer slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all printer")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *Printer) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *Printer) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no printers provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	o.UpdatedAt = currTime
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(printerColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	printerUpsertCacheMut.RLock()
+	cache, cached := printerUpsertCache[key]
+	printerUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			printerAllColumns,
+			printerColumnsWithDefault,
+			printerColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			printerAllColumns,
+			printerPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert printers, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(printerPrimaryKeyColumns))
+			copy(conflict, printerPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"printers\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(printerType, printerMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(printerType, printerMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert printers")
+	}
+
+	if !cached {
+		printerUpsertCacheMut.Lock()
+		printerUpsertCache[key] = cache
+		printerUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single Printer record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *Printer) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single Printer record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *Printer) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no Printer provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), printerPrimaryKeyMapping)
+	sql := "DELETE FROM \"printers\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from printers")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for printers")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q printerQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q printerQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no printerQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from printers")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for printers")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o PrinterSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o PrinterSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(printerBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), printerPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"printers\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, printerPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from printer slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for printers")
+	}
+
+	if len(printerAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *Printer) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no Printer provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *Printer) Reload(exec boil.Executor) error {
+	ret, err := FindPrinter(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *PrinterSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty PrinterSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *PrinterSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := PrinterSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), printerPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"printers\".* FROM \"printers\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, printerPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in PrinterSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// PrinterExistsG checks if the Printer row exists.
+func PrinterExistsG(iD string) (bool, error) {
+	return PrinterExists(boil.GetDB(), iD)
+}
+
+// PrinterExists checks if the Printer row exists.
+func PrinterExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"printers\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if printers exists")
+	}
+
+	return exists, nil
+}
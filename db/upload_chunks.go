@@ -0,0 +1,1115 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/strmangle"
+)
+
+// UploadChunk is an object representing the database table.
+type UploadChunk struct {
+	ID          string    `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	UploadID    string    `db:"upload_id" boil:"upload_id" json:"upload_id" toml:"upload_id" yaml:"upload_id"`
+	StartOffset int64     `db:"start_offset" boil:"start_offset" json:"start_offset" toml:"start_offset" yaml:"start_offset"`
+	Data        []byte    `db:"data" boil:"data" json:"data" toml:"data" yaml:"data"`
+	CreatedAt   time.Time `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *uploadChunkR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L uploadChunkL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var UploadChunkColumns = struct {
+	ID          string
+	UploadID    string
+	StartOffset string
+	Data        string
+	CreatedAt   string
+}{
+	ID:          "id",
+	UploadID:    "upload_id",
+	StartOffset: "start_offset",
+	Data:        "data",
+	CreatedAt:   "created_at",
+}
+
+// Generated where
+
+var UploadChunkWhere = struct {
+	ID          whereHelperstring
+	UploadID    whereHelperstring
+	StartOffset whereHelperint64
+	Data        whereHelper__byte
+	CreatedAt   whereHelpertime_Time
+}{
+	ID:          whereHelperstring{field: "\"upload_chunks\".\"id\""},
+	UploadID:    whereHelperstring{field: "\"upload_chunks\".\"upload_id\""},
+	StartOffset: whereHelperint64{field: "\"upload_chunks\".\"start_offset\""},
+	Data:        whereHelper__byte{field: "\"upload_chunks\".\"data\""},
+	CreatedAt:   whereHelpertime_Time{field: "\"upload_chunks\".\"created_at\""},
+}
+
+// UploadChunkRels is where relationship names are stored.
+var UploadChunkRels = struct {
+	Upload string
+}{
+	Upload: "Upload",
+}
+
+// uploadChunkR is where relationships are stored.
+type uploadChunkR struct {
+	Upload *Upload `db:"Upload" boil:"Upload" json:"Upload" toml:"Upload" yaml:"Upload"`
+}
+
+// NewStruct creates a new relationship struct
+func (*uploadChunkR) NewStruct() *uploadChunkR {
+	return &uploadChunkR{}
+}
+
+// uploadChunkL is where Load methods for each relationship are stored.
+type uploadChunkL struct{}
+
+var (
+	uploadChunkAllColumns            = []string{"id", "upload_id", "start_offset", "data", "created_at"}
+	uploadChunkColumnsWithoutDefault = []string{"upload_id", "start_offset", "data"}
+	uploadChunkColumnsWithDefault    = []string{"id", "created_at"}
+	uploadChunkPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// UploadChunkSlice is an alias for a slice of pointers to UploadChunk.
+	// This should generally be used opposed to []UploadChunk.
+	UploadChunkSlice []*UploadChunk
+	// UploadChunkHook is the signature for custom UploadChunk hook methods
+	UploadChunkHook func(boil.Executor, *UploadChunk) error
+
+	uploadChunkQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	uploadChunkType                 = reflect.TypeOf(&UploadChunk{})
+	uploadChunkMapping              = queries.MakeStructMapping(uploadChunkType)
+	uploadChunkPrimaryKeyMapping, _ = queries.BindMapping(uploadChunkType, uploadChunkMapping, uploadChunkPrimaryKeyColumns)
+	uploadChunkInsertCacheMut       sync.RWMutex
+	uploadChunkInsertCache          = make(map[string]insertCache)
+	uploadChunkUpdateCacheMut       sync.RWMutex
+	uploadChunkUpdateCache          = make(map[string]updateCache)
+	uploadChunkUpsertCacheMut       sync.RWMutex
+	uploadChunkUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var uploadChunkBeforeInsertHooks []UploadChunkHook
+var uploadChunkBeforeUpdateHooks []UploadChunkHook
+var uploadChunkBeforeDeleteHooks []UploadChunkHook
+var uploadChunkBeforeUpsertHooks []UploadChunkHook
+
+var uploadChunkAfterInsertHooks []UploadChunkHook
+var uploadChunkAfterSelectHooks []UploadChunkHook
+var uploadChunkAfterUpdateHooks []UploadChunkHook
+var uploadChunkAfterDeleteHooks []UploadChunkHook
+var uploadChunkAfterUpsertHooks []UploadChunkHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *UploadChunk) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *UploadChunk) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *UploadChunk) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *UploadChunk) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *UploadChunk) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *UploadChunk) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *UploadChunk) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *UploadChunk) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *UploadChunk) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadChunkAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddUploadChunkHook registers your hook function for all future operations.
+func AddUploadChunkHook(hookPoint boil.HookPoint, uploadChunkHook UploadChunkHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		uploadChunkBeforeInsertHooks = append(uploadChunkBeforeInsertHooks, uploadChunkHook)
+	case boil.BeforeUpdateHook:
+		uploadChunkBeforeUpdateHooks = append(uploadChunkBeforeUpdateHooks, uploadChunkHook)
+	case boil.BeforeDeleteHook:
+		uploadChunkBeforeDeleteHooks = append(uploadChunkBeforeDeleteHooks, uploadChunkHook)
+	case boil.BeforeUpsertHook:
+		uploadChunkBeforeUpsertHooks = append(uploadChunkBeforeUpsertHooks, uploadChunkHook)
+	case boil.AfterInsertHook:
+		uploadChunkAfterInsertHooks = append(uploadChunkAfterInsertHooks, uploadChunkHook)
+	case boil.AfterSelectHook:
+		uploadChunkAfterSelectHooks = append(uploadChunkAfterSelectHooks, uploadChunkHook)
+	case boil.AfterUpdateHook:
+		uploadChunkAfterUpdateHooks = append(uploadChunkAfterUpdateHooks, uploadChunkHook)
+	case boil.AfterDeleteHook:
+		uploadChunkAfterDeleteHooks = append(uploadChunkAfterDeleteHooks, uploadChunkHook)
+	case boil.AfterUpsertHook:
+		uploadChunkAfterUpsertHooks = append(uploadChunkAfterUpsertHooks, uploadChunkHook)
+	}
+}
+
+// OneG returns a single uploadChunk record from the query using the global executor.
+func (q uploadChunkQuery) OneG() (*UploadChunk, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single uploadChunk record from the query.
+func (q uploadChunkQuery) One(exec boil.Executor) (*UploadChunk, error) {
+	o := &UploadChunk{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for upload_chunks")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all UploadChunk records from the query using the global executor.
+func (q uploadChunkQuery) AllG() (UploadChunkSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all UploadChunk records from the query.
+func (q uploadChunkQuery) All(exec boil.Executor) (UploadChunkSlice, error) {
+	var o []*UploadChunk
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to UploadChunk slice")
+	}
+
+	if len(uploadChunkAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all UploadChunk records in the query, and panics on error.
+func (q uploadChunkQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all UploadChunk records in the query.
+func (q uploadChunkQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count upload_chunks rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q uploadChunkQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q uploadChunkQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if upload_chunks exists")
+	}
+
+	return count > 0, nil
+}
+
+// Upload pointed to by the foreign key.
+func (o *UploadChunk) Upload(mods ...qm.QueryMod) uploadQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.UploadID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Uploads(queryMods...)
+	queries.SetFrom(query.Query, "\"uploads\"")
+
+	return query
+}
+
+// LoadUpload allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (uploadChunkL) LoadUpload(e boil.Executor, singular bool, maybeUploadChunk interface{}, mods queries.Applicator) error {
+	var slice []*UploadChunk
+	var object *UploadChunk
+
+	if singular {
+		object = maybeUploadChunk.(*UploadChunk)
+	} else {
+		slice = *maybeUploadChunk.(*[]*UploadChunk)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &uploadChunkR{}
+		}
+		args = append(args, object.UploadID)
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &uploadChunkR{}
+			}
+
+			for _, a := range args {
+				if a == obj.UploadID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.UploadID)
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`uploads`),
+		qm.WhereIn(`uploads.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Upload")
+	}
+
+	var resultSlice []*Upload
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Upload")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for uploads")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for uploads")
+	}
+
+	if len(uploadChunkAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Upload = foreign
+		if foreign.R == nil {
+			foreign.R = &uploadR{}
+		}
+		foreign.R.UploadChunks = append(foreign.R.UploadChunks, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if local.UploadID == foreign.ID {
+				local.R.Upload = foreign
+				if foreign.R == nil {
+					foreign.R = &uploadR{}
+				}
+				foreign.R.UploadChunks = append(foreign.R.UploadChunks, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetUploadG of the uploadChunk to the related item.
+// Sets o.R.Upload to related.
+// Adds o to related.R.UploadChunks.
+// Uses the global database handle.
+func (o *UploadChunk) SetUploadG(insert bool, related *Upload) error {
+	return o.SetUpload(boil.GetDB(), insert, related)
+}
+
+// SetUpload of the uploadChunk to the related item.
+// Sets o.R.Upload to related.
+// Adds o to related.R.UploadChunks.
+func (o *UploadChunk) SetUpload(exec boil.Executor, insert bool, related *Upload) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"upload_chunks\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"upload_id"}),
+		strmangle.WhereClause("\"", "\"", 2, uploadChunkPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	o.UploadID = related.ID
+	if o.R == nil {
+		o.R = &uploadChunkR{
+			Upload: related,
+		}
+	} else {
+		o.R.Upload = related
+	}
+
+	if related.R == nil {
+		related.R = &uploadR{
+			UploadChunks: UploadChunkSlice{o},
+		}
+	} else {
+		related.R.UploadChunks = append(related.R.UploadChunks, o)
+	}
+
+	return nil
+}
+
+// UploadChunks retrieves all the records using an executor.
+func UploadChunks(mods ...qm.QueryMod) uploadChunkQuery {
+	mods = append(mods, qm.From("\"upload_chunks\""))
+	return uploadChunkQuery{NewQuery(mods...)}
+}
+
+// FindUploadChunkG retrieves a single record by ID.
+func FindUploadChunkG(iD string, selectCols ...string) (*UploadChunk, error) {
+	return FindUploadChunk(boil.GetDB(), iD, selectCols...)
+}
+
+// FindUploadChunk retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindUploadChunk(exec boil.Executor, iD string, selectCols ...string) (*UploadChunk, error) {
+	uploadChunkObj := &UploadChunk{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"upload_chunks\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, uploadChunkObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from upload_chunks")
+	}
+
+	return uploadChunkObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *UploadChunk) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *UploadChunk) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no upload_chunks provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(uploadChunkColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	uploadChunkInsertCacheMut.RLock()
+	cache, cached := uploadChunkInsertCache[key]
+	uploadChunkInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			uploadChunkAllColumns,
+			uploadChunkColumnsWithDefault,
+			uploadChunkColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(uploadChunkType, uploadChunkMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(uploadChunkType, uploadChunkMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"upload_chunks\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"upload_chunks\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into upload_chunks")
+	}
+
+	if !cached {
+		uploadChunkInsertCacheMut.Lock()
+		uploadChunkInsertCache[key] = cache
+		uploadChunkInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single UploadChunk record using the global executor.
+// See Update for more documentation.
+func (o *UploadChunk) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the UploadChunk.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *UploadChunk) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	uploadChunkUpdateCacheMut.RLock()
+	cache, cached := uploadChunkUpdateCache[key]
+	uploadChunkUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			uploadChunkAllColumns,
+			uploadChunkPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update upload_chunks, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"upload_chunks\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, uploadChunkPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(uploadChunkType, uploadChunkMapping, append(wl, uploadChunkPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update upload_chunks row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for upload_chunks")
+	}
+
+	if !cached {
+		uploadChunkUpdateCacheMut.Lock()
+		uploadChunkUpdateCache[key] = cache
+		uploadChunkUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q uploadChunkQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q uploadChunkQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for upload_chunks")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for upload_chunks")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o UploadChunkSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o UploadChunkSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), uploadChunkPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"upload_chunks\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, uploadChunkPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in uploadChunk slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all uploadChunk")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *UploadChunk) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *UploadChunk) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no upload_chunks provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(uploadChunkColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	uploadChunkUpsertCacheMut.RLock()
+	cache, cached := uploadChunkUpsertCache[key]
+	uploadChunkUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			uploadChunkAllColumns,
+			uploadChunkColumnsWithDefault,
+			uploadChunkColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			uploadChunkAllColumns,
+			uploadChunkPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert upload_chunks, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(uploadChunkPrimaryKeyColumns))
+			copy(conflict, uploadChunkPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"upload_chunks\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(uploadChunkType, uploadChunkMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(uploadChunkType, uploadChunkMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert upload_chunks")
+	}
+
+	if !cached {
+		uploadChunkUpsertCacheMut.Lock()
+		uploadChunkUpsertCache[key] = cache
+		uploadChunkUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single UploadChunk record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *UploadChunk) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single UploadChunk record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *UploadChunk) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no UploadChunk provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), uploadChunkPrimaryKeyMapping)
+	sql := "DELETE FROM \"upload_chunks\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from upload_chunks")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for upload_chunks")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q uploadChunkQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q uploadChunkQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no uploadChunkQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from upload_chunks")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for upload_chunks")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o UploadChunkSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o UploadChunkSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(uploadChunkBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), uploadChunkPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"upload_chunks\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, uploadChunkPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from uploadChunk slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for upload_chunks")
+	}
+
+	if len(uploadChunkAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *UploadChunk) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no UploadChunk provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *UploadChunk) Reload(exec boil.Executor) error {
+	ret, err := FindUploadChunk(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *UploadChunkSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty UploadChunkSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *UploadChunkSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := UploadChunkSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), uploadChunkPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"upload_chunks\".* FROM \"upload_chunks\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, uploadChunkPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in UploadChunkSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// UploadChunkExistsG checks if the UploadChunk row exists.
+func UploadChunkExistsG(iD string) (bool, error) {
+	return UploadChunkExists(boil.GetDB(), iD)
+}
+
+// UploadChunkExists checks if the UploadChunk row exists.
+func UploadChunkExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"upload_chunks\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if upload_chunks exists")
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,1388 @@
+// Code generated by SQLBoiler 4.3.1 (https://github.com/volatiletech/sqlboiler). DO NOT EDIT.
+// This file is meant to be re-generated in place and/or deleted at any time.
+
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
+	"github.com/volatiletech/strmangle"
+)
+
+// Upload is an object representing the database table.
+type Upload struct {
+	ID            string            `db:"id" boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name          string            `db:"name" boil:"name" json:"name" toml:"name" yaml:"name"`
+	SizeBytes     int64             `db:"size_bytes" boil:"size_bytes" json:"size_bytes" toml:"size_bytes" yaml:"size_bytes"`
+	ReceivedBytes int64             `db:"received_bytes" boil:"received_bytes" json:"received_bytes" toml:"received_bytes" yaml:"received_bytes"`
+	Sha256        null.String       `db:"sha256" boil:"sha256" json:"sha256,omitempty" toml:"sha256" yaml:"sha256,omitempty"`
+	FolderID      null.String       `db:"folder_id" boil:"folder_id" json:"folder_id,omitempty" toml:"folder_id" yaml:"folder_id,omitempty"`
+	Tags          types.StringArray `db:"tags" boil:"tags" json:"tags" toml:"tags" yaml:"tags"`
+	NewVersion    bool              `db:"new_version" boil:"new_version" json:"new_version" toml:"new_version" yaml:"new_version"`
+	CreatedBy     string            `db:"created_by" boil:"created_by" json:"created_by" toml:"created_by" yaml:"created_by"`
+	ExpiresAt     time.Time         `db:"expires_at" boil:"expires_at" json:"expires_at" toml:"expires_at" yaml:"expires_at"`
+	UpdatedAt     time.Time         `db:"updated_at" boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	CreatedAt     time.Time         `db:"created_at" boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+
+	R *uploadR `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+	L uploadL  `db:"-" boil:"-" json:"-" toml:"-" yaml:"-"`
+}
+
+var UploadColumns = struct {
+	ID            string
+	Name          string
+	SizeBytes     string
+	ReceivedBytes string
+	Sha256        string
+	FolderID      string
+	Tags          string
+	NewVersion    string
+	CreatedBy     string
+	ExpiresAt     string
+	UpdatedAt     string
+	CreatedAt     string
+}{
+	ID:            "id",
+	Name:          "name",
+	SizeBytes:     "size_bytes",
+	ReceivedBytes: "received_bytes",
+	Sha256:        "sha256",
+	FolderID:      "folder_id",
+	Tags:          "tags",
+	NewVersion:    "new_version",
+	CreatedBy:     "created_by",
+	ExpiresAt:     "expires_at",
+	UpdatedAt:     "updated_at",
+	CreatedAt:     "created_at",
+}
+
+// Generated where
+
+var UploadWhere = struct {
+	ID            whereHelperstring
+	Name          whereHelperstring
+	SizeBytes     whereHelperint64
+	ReceivedBytes whereHelperint64
+	Sha256        whereHelpernull_String
+	FolderID      whereHelpernull_String
+	Tags          whereHelpertypes_StringArray
+	NewVersion    whereHelperbool
+	CreatedBy     whereHelperstring
+	ExpiresAt     whereHelpertime_Time
+	UpdatedAt     whereHelpertime_Time
+	CreatedAt     whereHelpertime_Time
+}{
+	ID:            whereHelperstring{field: "\"uploads\".\"id\""},
+	Name:          whereHelperstring{field: "\"uploads\".\"name\""},
+	SizeBytes:     whereHelperint64{field: "\"uploads\".\"size_bytes\""},
+	ReceivedBytes: whereHelperint64{field: "\"uploads\".\"received_bytes\""},
+	Sha256:        whereHelpernull_String{field: "\"uploads\".\"sha256\""},
+	FolderID:      whereHelpernull_String{field: "\"uploads\".\"folder_id\""},
+	Tags:          whereHelpertypes_StringArray{field: "\"uploads\".\"tags\""},
+	NewVersion:    whereHelperbool{field: "\"uploads\".\"new_version\""},
+	CreatedBy:     whereHelperstring{field: "\"uploads\".\"created_by\""},
+	ExpiresAt:     whereHelpertime_Time{field: "\"uploads\".\"expires_at\""},
+	UpdatedAt:     whereHelpertime_Time{field: "\"uploads\".\"updated_at\""},
+	CreatedAt:     whereHelpertime_Time{field: "\"uploads\".\"created_at\""},
+}
+
+// UploadRels is where relationship names are stored.
+var UploadRels = struct {
+	Folder       string
+	UploadChunks string
+}{
+	Folder:       "Folder",
+	UploadChunks: "UploadChunks",
+}
+
+// uploadR is where relationships are stored.
+type uploadR struct {
+	Folder       *Folder          `db:"Folder" boil:"Folder" json:"Folder" toml:"Folder" yaml:"Folder"`
+	UploadChunks UploadChunkSlice `db:"UploadChunks" boil:"UploadChunks" json:"UploadChunks" toml:"UploadChunks" yaml:"UploadChunks"`
+}
+
+// NewStruct creates a new relationship struct
+func (*uploadR) NewStruct() *uploadR {
+	return &uploadR{}
+}
+
+// uploadL is where Load methods for each relationship are stored.
+type uploadL struct{}
+
+var (
+	uploadAllColumns            = []string{"id", "name", "size_bytes", "received_bytes", "sha256", "folder_id", "tags", "new_version", "created_by", "expires_at", "updated_at", "created_at"}
+	uploadColumnsWithoutDefault = []string{"name", "size_bytes", "sha256", "folder_id", "created_by", "expires_at"}
+	uploadColumnsWithDefault    = []string{"id", "received_bytes", "tags", "new_version", "updated_at", "created_at"}
+	uploadPrimaryKeyColumns     = []string{"id"}
+)
+
+type (
+	// UploadSlice is an alias for a slice of pointers to Upload.
+	// This should generally be used opposed to []Upload.
+	UploadSlice []*Upload
+	// UploadHook is the signature for custom Upload hook methods
+	UploadHook func(boil.Executor, *Upload) error
+
+	uploadQuery struct {
+		*queries.Query
+	}
+)
+
+// Cache for insert, update and upsert
+var (
+	uploadType                 = reflect.TypeOf(&Upload{})
+	uploadMapping              = queries.MakeStructMapping(uploadType)
+	uploadPrimaryKeyMapping, _ = queries.BindMapping(uploadType, uploadMapping, uploadPrimaryKeyColumns)
+	uploadInsertCacheMut       sync.RWMutex
+	uploadInsertCache          = make(map[string]insertCache)
+	uploadUpdateCacheMut       sync.RWMutex
+	uploadUpdateCache          = make(map[string]updateCache)
+	uploadUpsertCacheMut       sync.RWMutex
+	uploadUpsertCache          = make(map[string]insertCache)
+)
+
+var (
+	// Force time package dependency for automated UpdatedAt/CreatedAt.
+	_ = time.Second
+	// Force qmhelper dependency for where clause generation (which doesn't
+	// always happen)
+	_ = qmhelper.Where
+)
+
+var uploadBeforeInsertHooks []UploadHook
+var uploadBeforeUpdateHooks []UploadHook
+var uploadBeforeDeleteHooks []UploadHook
+var uploadBeforeUpsertHooks []UploadHook
+
+var uploadAfterInsertHooks []UploadHook
+var uploadAfterSelectHooks []UploadHook
+var uploadAfterUpdateHooks []UploadHook
+var uploadAfterDeleteHooks []UploadHook
+var uploadAfterUpsertHooks []UploadHook
+
+// doBeforeInsertHooks executes all "before insert" hooks.
+func (o *Upload) doBeforeInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadBeforeInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpdateHooks executes all "before Update" hooks.
+func (o *Upload) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadBeforeUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeDeleteHooks executes all "before Delete" hooks.
+func (o *Upload) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadBeforeDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doBeforeUpsertHooks executes all "before Upsert" hooks.
+func (o *Upload) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadBeforeUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterInsertHooks executes all "after Insert" hooks.
+func (o *Upload) doAfterInsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadAfterInsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterSelectHooks executes all "after Select" hooks.
+func (o *Upload) doAfterSelectHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadAfterSelectHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpdateHooks executes all "after Update" hooks.
+func (o *Upload) doAfterUpdateHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadAfterUpdateHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterDeleteHooks executes all "after Delete" hooks.
+func (o *Upload) doAfterDeleteHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadAfterDeleteHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// doAfterUpsertHooks executes all "after Upsert" hooks.
+func (o *Upload) doAfterUpsertHooks(exec boil.Executor) (err error) {
+	for _, hook := range uploadAfterUpsertHooks {
+		if err := hook(exec, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddUploadHook registers your hook function for all future operations.
+func AddUploadHook(hookPoint boil.HookPoint, uploadHook UploadHook) {
+	switch hookPoint {
+	case boil.BeforeInsertHook:
+		uploadBeforeInsertHooks = append(uploadBeforeInsertHooks, uploadHook)
+	case boil.BeforeUpdateHook:
+		uploadBeforeUpdateHooks = append(uploadBeforeUpdateHooks, uploadHook)
+	case boil.BeforeDeleteHook:
+		uploadBeforeDeleteHooks = append(uploadBeforeDeleteHooks, uploadHook)
+	case boil.BeforeUpsertHook:
+		uploadBeforeUpsertHooks = append(uploadBeforeUpsertHooks, uploadHook)
+	case boil.AfterInsertHook:
+		uploadAfterInsertHooks = append(uploadAfterInsertHooks, uploadHook)
+	case boil.AfterSelectHook:
+		uploadAfterSelectHooks = append(uploadAfterSelectHooks, uploadHook)
+	case boil.AfterUpdateHook:
+		uploadAfterUpdateHooks = append(uploadAfterUpdateHooks, uploadHook)
+	case boil.AfterDeleteHook:
+		uploadAfterDeleteHooks = append(uploadAfterDeleteHooks, uploadHook)
+	case boil.AfterUpsertHook:
+		uploadAfterUpsertHooks = append(uploadAfterUpsertHooks, uploadHook)
+	}
+}
+
+// OneG returns a single upload record from the query using the global executor.
+func (q uploadQuery) OneG() (*Upload, error) {
+	return q.One(boil.GetDB())
+}
+
+// One returns a single upload record from the query.
+func (q uploadQuery) One(exec boil.Executor) (*Upload, error) {
+	o := &Upload{}
+
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Bind(nil, exec, o)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: failed to execute a one query for uploads")
+	}
+
+	if err := o.doAfterSelectHooks(exec); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// AllG returns all Upload records from the query using the global executor.
+func (q uploadQuery) AllG() (UploadSlice, error) {
+	return q.All(boil.GetDB())
+}
+
+// All returns all Upload records from the query.
+func (q uploadQuery) All(exec boil.Executor) (UploadSlice, error) {
+	var o []*Upload
+
+	err := q.Bind(nil, exec, &o)
+	if err != nil {
+		return nil, errors.Wrap(err, "db: failed to assign all query results to Upload slice")
+	}
+
+	if len(uploadAfterSelectHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterSelectHooks(exec); err != nil {
+				return o, err
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// CountG returns the count of all Upload records in the query, and panics on error.
+func (q uploadQuery) CountG() (int64, error) {
+	return q.Count(boil.GetDB())
+}
+
+// Count returns the count of all Upload records in the query.
+func (q uploadQuery) Count(exec boil.Executor) (int64, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to count uploads rows")
+	}
+
+	return count, nil
+}
+
+// ExistsG checks if the row exists in the table, and panics on error.
+func (q uploadQuery) ExistsG() (bool, error) {
+	return q.Exists(boil.GetDB())
+}
+
+// Exists checks if the row exists in the table.
+func (q uploadQuery) Exists(exec boil.Executor) (bool, error) {
+	var count int64
+
+	queries.SetSelect(q.Query, nil)
+	queries.SetCount(q.Query)
+	queries.SetLimit(q.Query, 1)
+
+	err := q.Query.QueryRow(exec).Scan(&count)
+	if err != nil {
+		return false, errors.Wrap(err, "db: failed to check if uploads exists")
+	}
+
+	return count > 0, nil
+}
+
+// Folder pointed to by the foreign key.
+func (o *Upload) Folder(mods ...qm.QueryMod) folderQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"id\" = ?", o.FolderID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	query := Folders(queryMods...)
+	queries.SetFrom(query.Query, "\"folders\"")
+
+	return query
+}
+
+// UploadChunks retrieves all the upload_chunk's UploadChunks with an executor.
+func (o *Upload) UploadChunks(mods ...qm.QueryMod) uploadChunkQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"upload_chunks\".\"upload_id\"=?", o.ID),
+	)
+
+	query := UploadChunks(queryMods...)
+	queries.SetFrom(query.Query, "\"upload_chunks\"")
+
+	if len(queries.GetSelect(query.Query)) == 0 {
+		queries.SetSelect(query.Query, []string{"\"upload_chunks\".*"})
+	}
+
+	return query
+}
+
+// LoadFolder allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for an N-1 relationship.
+func (uploadL) LoadFolder(e boil.Executor, singular bool, maybeUpload interface{}, mods queries.Applicator) error {
+	var slice []*Upload
+	var object *Upload
+
+	if singular {
+		object = maybeUpload.(*Upload)
+	} else {
+		slice = *maybeUpload.(*[]*Upload)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &uploadR{}
+		}
+		if !queries.IsNil(object.FolderID) {
+			args = append(args, object.FolderID)
+		}
+
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &uploadR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.FolderID) {
+					continue Outer
+				}
+			}
+
+			if !queries.IsNil(obj.FolderID) {
+				args = append(args, obj.FolderID)
+			}
+
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`folders`),
+		qm.WhereIn(`folders.id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Folder")
+	}
+
+	var resultSlice []*Folder
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Folder")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for folders")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for folders")
+	}
+
+	if len(uploadAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.Folder = foreign
+		if foreign.R == nil {
+			foreign.R = &folderR{}
+		}
+		foreign.R.Uploads = append(foreign.R.Uploads, object)
+		return nil
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.FolderID, foreign.ID) {
+				local.R.Folder = foreign
+				if foreign.R == nil {
+					foreign.R = &folderR{}
+				}
+				foreign.R.Uploads = append(foreign.R.Uploads, local)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadUploadChunks allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (uploadL) LoadUploadChunks(e boil.Executor, singular bool, maybeUpload interface{}, mods queries.Applicator) error {
+	var slice []*Upload
+	var object *Upload
+
+	if singular {
+		object = maybeUpload.(*Upload)
+	} else {
+		slice = *maybeUpload.(*[]*Upload)
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &uploadR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &uploadR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`upload_chunks`),
+		qm.WhereIn(`upload_chunks.upload_id in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.Query(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load upload_chunks")
+	}
+
+	var resultSlice []*UploadChunk
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice upload_chunks")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on upload_chunks")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for upload_chunks")
+	}
+
+	if len(uploadChunkAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.UploadChunks = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &uploadChunkR{}
+			}
+			foreign.R.Upload = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.UploadID {
+				local.R.UploadChunks = append(local.R.UploadChunks, foreign)
+				if foreign.R == nil {
+					foreign.R = &uploadChunkR{}
+				}
+				foreign.R.Upload = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetFolderG of the upload to the related item.
+// Sets o.R.Folder to related.
+// Adds o to related.R.Uploads.
+// Uses the global database handle.
+func (o *Upload) SetFolderG(insert bool, related *Folder) error {
+	return o.SetFolder(boil.GetDB(), insert, related)
+}
+
+// SetFolder of the upload to the related item.
+// Sets o.R.Folder to related.
+// Adds o to related.R.Uploads.
+func (o *Upload) SetFolder(exec boil.Executor, insert bool, related *Folder) error {
+	var err error
+	if insert {
+		if err = related.Insert(exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	}
+
+	updateQuery := fmt.Sprintf(
+		"UPDATE \"uploads\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, []string{"folder_id"}),
+		strmangle.WhereClause("\"", "\"", 2, uploadPrimaryKeyColumns),
+	)
+	values := []interface{}{related.ID, o.ID}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, updateQuery)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	if _, err = exec.Exec(updateQuery, values...); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	queries.Assign(&o.FolderID, related.ID)
+	if o.R == nil {
+		o.R = &uploadR{
+			Folder: related,
+		}
+	} else {
+		o.R.Folder = related
+	}
+
+	if related.R == nil {
+		related.R = &folderR{
+			Uploads: UploadSlice{o},
+		}
+	} else {
+		related.R.Uploads = append(related.R.Uploads, o)
+	}
+
+	return nil
+}
+
+// RemoveFolderG relationship.
+// Sets o.R.Folder to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+// Uses the global database handle.
+func (o *Upload) RemoveFolderG(related *Folder) error {
+	return o.RemoveFolder(boil.GetDB(), related)
+}
+
+// RemoveFolder relationship.
+// Sets o.R.Folder to nil.
+// Removes o from all passed in related items' relationships struct (Optional).
+func (o *Upload) RemoveFolder(exec boil.Executor, related *Folder) error {
+	var err error
+
+	queries.SetScanner(&o.FolderID, nil)
+	if _, err = o.Update(exec, boil.Whitelist("folder_id")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.Folder = nil
+	}
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	for i, ri := range related.R.Uploads {
+		if queries.Equal(o.FolderID, ri.FolderID) {
+			continue
+		}
+
+		ln := len(related.R.Uploads)
+		if ln > 1 && i < ln-1 {
+			related.R.Uploads[i] = related.R.Uploads[ln-1]
+		}
+		related.R.Uploads = related.R.Uploads[:ln-1]
+		break
+	}
+	return nil
+}
+
+// AddUploadChunksG adds the given related objects to the existing relationships
+// of the upload, optionally inserting them as new records.
+// Appends related to o.R.UploadChunks.
+// Sets related.R.Upload appropriately.
+// Uses the global database handle.
+func (o *Upload) AddUploadChunksG(insert bool, related ...*UploadChunk) error {
+	return o.AddUploadChunks(boil.GetDB(), insert, related...)
+}
+
+// AddUploadChunks adds the given related objects to the existing relationships
+// of the upload, optionally inserting them as new records.
+// Appends related to o.R.UploadChunks.
+// Sets related.R.Upload appropriately.
+func (o *Upload) AddUploadChunks(exec boil.Executor, insert bool, related ...*UploadChunk) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.UploadID = o.ID
+			if err = rel.Insert(exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"upload_chunks\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"upload_id"}),
+				strmangle.WhereClause("\"", "\"", 2, uploadChunkPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.DebugMode {
+				fmt.Fprintln(boil.DebugWriter, updateQuery)
+				fmt.Fprintln(boil.DebugWriter, values)
+			}
+			if _, err = exec.Exec(updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.UploadID = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &uploadR{
+			UploadChunks: related,
+		}
+	} else {
+		o.R.UploadChunks = append(o.R.UploadChunks, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &uploadChunkR{
+				Upload: o,
+			}
+		} else {
+			rel.R.Upload = o
+		}
+	}
+	return nil
+}
+
+// Uploads retrieves all the records using an executor.
+func Uploads(mods ...qm.QueryMod) uploadQuery {
+	mods = append(mods, qm.From("\"uploads\""))
+	return uploadQuery{NewQuery(mods...)}
+}
+
+// FindUploadG retrieves a single record by ID.
+func FindUploadG(iD string, selectCols ...string) (*Upload, error) {
+	return FindUpload(boil.GetDB(), iD, selectCols...)
+}
+
+// FindUpload retrieves a single record by ID with an executor.
+// If selectCols is empty Find will return all columns.
+func FindUpload(exec boil.Executor, iD string, selectCols ...string) (*Upload, error) {
+	uploadObj := &Upload{}
+
+	sel := "*"
+	if len(selectCols) > 0 {
+		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
+	}
+	query := fmt.Sprintf(
+		"select %s from \"uploads\" where \"id\"=$1", sel,
+	)
+
+	q := queries.Raw(query, iD)
+
+	err := q.Bind(nil, exec, uploadObj)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, errors.Wrap(err, "db: unable to select from uploads")
+	}
+
+	return uploadObj, nil
+}
+
+// InsertG a single record. See Insert for whitelist behavior description.
+func (o *Upload) InsertG(columns boil.Columns) error {
+	return o.Insert(boil.GetDB(), columns)
+}
+
+// Insert a single record using an executor.
+// See boil.Columns.InsertColumnSet documentation to understand column list inference for inserts.
+func (o *Upload) Insert(exec boil.Executor, columns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no uploads provided for insertion")
+	}
+
+	var err error
+	currTime := time.Now().In(boil.GetLocation())
+
+	if o.UpdatedAt.IsZero() {
+		o.UpdatedAt = currTime
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeInsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(uploadColumnsWithDefault, o)
+
+	key := makeCacheKey(columns, nzDefaults)
+	uploadInsertCacheMut.RLock()
+	cache, cached := uploadInsertCache[key]
+	uploadInsertCacheMut.RUnlock()
+
+	if !cached {
+		wl, returnColumns := columns.InsertColumnSet(
+			uploadAllColumns,
+			uploadColumnsWithDefault,
+			uploadColumnsWithoutDefault,
+			nzDefaults,
+		)
+
+		cache.valueMapping, err = queries.BindMapping(uploadType, uploadMapping, wl)
+		if err != nil {
+			return err
+		}
+		cache.retMapping, err = queries.BindMapping(uploadType, uploadMapping, returnColumns)
+		if err != nil {
+			return err
+		}
+		if len(wl) != 0 {
+			cache.query = fmt.Sprintf("INSERT INTO \"uploads\" (\"%s\") %%sVALUES (%s)%%s", strings.Join(wl, "\",\""), strmangle.Placeholders(dialect.UseIndexPlaceholders, len(wl), 1, 1))
+		} else {
+			cache.query = "INSERT INTO \"uploads\" %sDEFAULT VALUES%s"
+		}
+
+		var queryOutput, queryReturning string
+
+		if len(cache.retMapping) != 0 {
+			queryReturning = fmt.Sprintf(" RETURNING \"%s\"", strings.Join(returnColumns, "\",\""))
+		}
+
+		cache.query = fmt.Sprintf(cache.query, queryOutput, queryReturning)
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "db: unable to insert into uploads")
+	}
+
+	if !cached {
+		uploadInsertCacheMut.Lock()
+		uploadInsertCache[key] = cache
+		uploadInsertCacheMut.Unlock()
+	}
+
+	return o.doAfterInsertHooks(exec)
+}
+
+// UpdateG a single Upload record using the global executor.
+// See Update for more documentation.
+func (o *Upload) UpdateG(columns boil.Columns) (int64, error) {
+	return o.Update(boil.GetDB(), columns)
+}
+
+// Update uses an executor to update the Upload.
+// See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
+// Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
+func (o *Upload) Update(exec boil.Executor, columns boil.Columns) (int64, error) {
+	currTime := time.Now().In(boil.GetLocation())
+
+	o.UpdatedAt = currTime
+
+	var err error
+	if err = o.doBeforeUpdateHooks(exec); err != nil {
+		return 0, err
+	}
+	key := makeCacheKey(columns, nil)
+	uploadUpdateCacheMut.RLock()
+	cache, cached := uploadUpdateCache[key]
+	uploadUpdateCacheMut.RUnlock()
+
+	if !cached {
+		wl := columns.UpdateColumnSet(
+			uploadAllColumns,
+			uploadPrimaryKeyColumns,
+		)
+
+		if !columns.IsWhitelist() {
+			wl = strmangle.SetComplement(wl, []string{"created_at"})
+		}
+		if len(wl) == 0 {
+			return 0, errors.New("db: unable to update uploads, could not build whitelist")
+		}
+
+		cache.query = fmt.Sprintf("UPDATE \"uploads\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+			strmangle.WhereClause("\"", "\"", len(wl)+1, uploadPrimaryKeyColumns),
+		)
+		cache.valueMapping, err = queries.BindMapping(uploadType, uploadMapping, append(wl, uploadPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, values)
+	}
+	var result sql.Result
+	result, err = exec.Exec(cache.query, values...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update uploads row")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by update for uploads")
+	}
+
+	if !cached {
+		uploadUpdateCacheMut.Lock()
+		uploadUpdateCache[key] = cache
+		uploadUpdateCacheMut.Unlock()
+	}
+
+	return rowsAff, o.doAfterUpdateHooks(exec)
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (q uploadQuery) UpdateAllG(cols M) (int64, error) {
+	return q.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values.
+func (q uploadQuery) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	queries.SetUpdate(q.Query, cols)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all for uploads")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected for uploads")
+	}
+
+	return rowsAff, nil
+}
+
+// UpdateAllG updates all rows with the specified column values.
+func (o UploadSlice) UpdateAllG(cols M) (int64, error) {
+	return o.UpdateAll(boil.GetDB(), cols)
+}
+
+// UpdateAll updates all rows with the specified column values, using an executor.
+func (o UploadSlice) UpdateAll(exec boil.Executor, cols M) (int64, error) {
+	ln := int64(len(o))
+	if ln == 0 {
+		return 0, nil
+	}
+
+	if len(cols) == 0 {
+		return 0, errors.New("db: update all requires at least one column argument")
+	}
+
+	colNames := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+
+	i := 0
+	for name, value := range cols {
+		colNames[i] = name
+		args[i] = value
+		i++
+	}
+
+	// Append all of the primary key values for each column
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), uploadPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := fmt.Sprintf("UPDATE \"uploads\" SET %s WHERE %s",
+		strmangle.SetParamNames("\"", "\"", 1, colNames),
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), len(colNames)+1, uploadPrimaryKeyColumns, len(o)))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to update all in upload slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to retrieve rows affected all in update all upload")
+	}
+	return rowsAff, nil
+}
+
+// UpsertG attempts an insert, and does an update or ignore on conflict.
+func (o *Upload) UpsertG(updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	return o.Upsert(boil.GetDB(), updateOnConflict, conflictColumns, updateColumns, insertColumns)
+}
+
+// Upsert attempts an insert using an executor, and does an update or ignore on conflict.
+// See boil.Columns documentation for how to properly use updateColumns and insertColumns.
+func (o *Upload) Upsert(exec boil.Executor, updateOnConflict bool, conflictColumns []string, updateColumns, insertColumns boil.Columns) error {
+	if o == nil {
+		return errors.New("db: no uploads provided for upsert")
+	}
+	currTime := time.Now().In(boil.GetLocation())
+
+	o.UpdatedAt = currTime
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = currTime
+	}
+
+	if err := o.doBeforeUpsertHooks(exec); err != nil {
+		return err
+	}
+
+	nzDefaults := queries.NonZeroDefaultSet(uploadColumnsWithDefault, o)
+
+	// Build cache key in-line uglily - mysql vs psql problems
+	buf := strmangle.GetBuffer()
+	if updateOnConflict {
+		buf.WriteByte('t')
+	} else {
+		buf.WriteByte('f')
+	}
+	buf.WriteByte('.')
+	for _, c := range conflictColumns {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(updateColumns.Kind))
+	for _, c := range updateColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	buf.WriteString(strconv.Itoa(insertColumns.Kind))
+	for _, c := range insertColumns.Cols {
+		buf.WriteString(c)
+	}
+	buf.WriteByte('.')
+	for _, c := range nzDefaults {
+		buf.WriteString(c)
+	}
+	key := buf.String()
+	strmangle.PutBuffer(buf)
+
+	uploadUpsertCacheMut.RLock()
+	cache, cached := uploadUpsertCache[key]
+	uploadUpsertCacheMut.RUnlock()
+
+	var err error
+
+	if !cached {
+		insert, ret := insertColumns.InsertColumnSet(
+			uploadAllColumns,
+			uploadColumnsWithDefault,
+			uploadColumnsWithoutDefault,
+			nzDefaults,
+		)
+		update := updateColumns.UpdateColumnSet(
+			uploadAllColumns,
+			uploadPrimaryKeyColumns,
+		)
+
+		if updateOnConflict && len(update) == 0 {
+			return errors.New("db: unable to upsert uploads, could not build update column list")
+		}
+
+		conflict := conflictColumns
+		if len(conflict) == 0 {
+			conflict = make([]string, len(uploadPrimaryKeyColumns))
+			copy(conflict, uploadPrimaryKeyColumns)
+		}
+		cache.query = buildUpsertQueryPostgres(dialect, "\"uploads\"", updateOnConflict, ret, update, conflict, insert)
+
+		cache.valueMapping, err = queries.BindMapping(uploadType, uploadMapping, insert)
+		if err != nil {
+			return err
+		}
+		if len(ret) != 0 {
+			cache.retMapping, err = queries.BindMapping(uploadType, uploadMapping, ret)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(o))
+	vals := queries.ValuesFromMapping(value, cache.valueMapping)
+	var returns []interface{}
+	if len(cache.retMapping) != 0 {
+		returns = queries.PtrsFromMapping(value, cache.retMapping)
+	}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, vals)
+	}
+	if len(cache.retMapping) != 0 {
+		err = exec.QueryRow(cache.query, vals...).Scan(returns...)
+		if err == sql.ErrNoRows {
+			err = nil // Postgres doesn't return anything when there's no update
+		}
+	} else {
+		_, err = exec.Exec(cache.query, vals...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db: unable to upsert uploads")
+	}
+
+	if !cached {
+		uploadUpsertCacheMut.Lock()
+		uploadUpsertCache[key] = cache
+		uploadUpsertCacheMut.Unlock()
+	}
+
+	return o.doAfterUpsertHooks(exec)
+}
+
+// DeleteG deletes a single Upload record.
+// DeleteG will match against the primary key column to find the record to delete.
+func (o *Upload) DeleteG() (int64, error) {
+	return o.Delete(boil.GetDB())
+}
+
+// Delete deletes a single Upload record with an executor.
+// Delete will match against the primary key column to find the record to delete.
+func (o *Upload) Delete(exec boil.Executor) (int64, error) {
+	if o == nil {
+		return 0, errors.New("db: no Upload provided for delete")
+	}
+
+	if err := o.doBeforeDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), uploadPrimaryKeyMapping)
+	sql := "DELETE FROM \"uploads\" WHERE \"id\"=$1"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete from uploads")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by delete for uploads")
+	}
+
+	if err := o.doAfterDeleteHooks(exec); err != nil {
+		return 0, err
+	}
+
+	return rowsAff, nil
+}
+
+func (q uploadQuery) DeleteAllG() (int64, error) {
+	return q.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all matching rows.
+func (q uploadQuery) DeleteAll(exec boil.Executor) (int64, error) {
+	if q.Query == nil {
+		return 0, errors.New("db: no uploadQuery provided for delete all")
+	}
+
+	queries.SetDelete(q.Query)
+
+	result, err := q.Query.Exec(exec)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from uploads")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for uploads")
+	}
+
+	return rowsAff, nil
+}
+
+// DeleteAllG deletes all rows in the slice.
+func (o UploadSlice) DeleteAllG() (int64, error) {
+	return o.DeleteAll(boil.GetDB())
+}
+
+// DeleteAll deletes all rows in the slice, using an executor.
+func (o UploadSlice) DeleteAll(exec boil.Executor) (int64, error) {
+	if len(o) == 0 {
+		return 0, nil
+	}
+
+	if len(uploadBeforeDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var args []interface{}
+	for _, obj := range o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), uploadPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "DELETE FROM \"uploads\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, uploadPrimaryKeyColumns, len(o))
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+	result, err := exec.Exec(sql, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "db: unable to delete all from upload slice")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "db: failed to get rows affected by deleteall for uploads")
+	}
+
+	if len(uploadAfterDeleteHooks) != 0 {
+		for _, obj := range o {
+			if err := obj.doAfterDeleteHooks(exec); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return rowsAff, nil
+}
+
+// ReloadG refetches the object from the database using the primary keys.
+func (o *Upload) ReloadG() error {
+	if o == nil {
+		return errors.New("db: no Upload provided for reload")
+	}
+
+	return o.Reload(boil.GetDB())
+}
+
+// Reload refetches the object from the database
+// using the primary keys with an executor.
+func (o *Upload) Reload(exec boil.Executor) error {
+	ret, err := FindUpload(exec, o.ID)
+	if err != nil {
+		return err
+	}
+
+	*o = *ret
+	return nil
+}
+
+// ReloadAllG refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *UploadSlice) ReloadAllG() error {
+	if o == nil {
+		return errors.New("db: empty UploadSlice provided for reload all")
+	}
+
+	return o.ReloadAll(boil.GetDB())
+}
+
+// ReloadAll refetches every row with matching primary key column values
+// and overwrites the original object slice with the newly updated slice.
+func (o *UploadSlice) ReloadAll(exec boil.Executor) error {
+	if o == nil || len(*o) == 0 {
+		return nil
+	}
+
+	slice := UploadSlice{}
+	var args []interface{}
+	for _, obj := range *o {
+		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), uploadPrimaryKeyMapping)
+		args = append(args, pkeyArgs...)
+	}
+
+	sql := "SELECT \"uploads\".* FROM \"uploads\" WHERE " +
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, uploadPrimaryKeyColumns, len(*o))
+
+	q := queries.Raw(sql, args...)
+
+	err := q.Bind(nil, exec, &slice)
+	if err != nil {
+		return errors.Wrap(err, "db: unable to reload all in UploadSlice")
+	}
+
+	*o = slice
+
+	return nil
+}
+
+// UploadExistsG checks if the Upload row exists.
+func UploadExistsG(iD string) (bool, error) {
+	return UploadExists(boil.GetDB(), iD)
+}
+
+// UploadExists checks if the Upload row exists.
+func UploadExists(exec boil.Executor, iD string) (bool, error) {
+	var exists bool
+	sql := "select exists(select 1 from \"uploads\" where \"id\"=$1 limit 1)"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, iD)
+	}
+	row := exec.QueryRow(sql, iD)
+
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, errors.Wrap(err, "db: unable to check if uploads exists")
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,235 @@
+package gcodefile
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// bgcodeMagic starts every binary gcode file
+var bgcodeMagic = []byte("GCDE")
+
+// Block types in a binary gcode file
+const (
+	blockFileMetadata    = 0
+	blockGcode           = 1
+	blockSlicerMetadata  = 2
+	blockPrinterMetadata = 3
+	blockPrintMetadata   = 4
+	blockThumbnail       = 5
+)
+
+// Ways a block can be compressed
+const (
+	compressNone         = 0
+	compressDeflate      = 1
+	compressHeatshrink11 = 2 // Window of 2^11 bytes, lookahead of 2^4
+	compressHeatshrink12 = 3 // Window of 2^12 bytes, lookahead of 2^4
+)
+
+// Ways a gcode block can be encoded
+const (
+	encodingNone             = 0
+	encodingMeatPack         = 1
+	encodingMeatPackComments = 2 // MeatPack with the comments left in
+)
+
+// metadataEncodingINI is the only encoding metadata blocks have, key=value lines
+const metadataEncodingINI = 0
+
+// checksumCRC32 is a file whose blocks each end with a CRC32 of the block
+const checksumCRC32 = 1
+
+// Sizes of the fixed parts of the file
+const (
+	fileHeaderSize            = 10 // Magic, version, checksum type
+	blockHeaderSize           = 8  // Type, compression, uncompressed size
+	compressedBlockHeaderSize = 12 // Then the compressed size
+	blockParamsSize           = 2  // Encoding
+	thumbnailParamsSize       = 6  // Format, width, height
+)
+
+// maxBlockSize caps the size a block says it has, packed or unpacked. PrusaSlicer writes
+// gcode in 64 KiB blocks, only a thumbnail gets anywhere near this.
+const maxBlockSize = 16 << 20
+
+// thumbnailFormats are the image formats thumbnail blocks can hold
+var thumbnailFormats = map[uint16]string{0: "png", 1: "jpg", 2: "qoi"}
+
+// decodeBgcode reads the blocks of a binary gcode file. The gcode blocks are joined into plain gcode,
+// with the printer metadata ahead of it and the print metadata and slicer settings after it in
+// comments, the way PrusaSlicer writes a text file.
+func decodeBgcode(data []byte, limit int64) (*File, error) {
+	if len(data) < fileHeaderSize {
+		return nil, errors.New("bgcode file header is cut short")
+	}
+	version := binary.LittleEndian.Uint32(data[4:])
+	if version != 1 {
+		return nil, fmt.Errorf("bgcode version %d is not supported", version)
+	}
+	checksum := binary.LittleEndian.Uint16(data[8:])
+
+	file := &File{Format: FormatBgcode, Metadata: map[string]string{}}
+	var gcode, printerMeta, printMeta, slicerMeta bytes.Buffer
+	for pos := fileHeaderSize; pos < len(data); {
+		if len(data)-pos < blockHeaderSize {
+			return nil, fmt.Errorf("bgcode block header at %d is cut short", pos)
+		}
+		start := pos
+		blockType := binary.LittleEndian.Uint16(data[pos:])
+		compression := binary.LittleEndian.Uint16(data[pos+2:])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		stored := size
+		pos += blockHeaderSize
+		if compression != compressNone {
+			if len(data)-pos < compressedBlockHeaderSize-blockHeaderSize {
+				return nil, fmt.Errorf("bgcode block header at %d is cut short", start)
+			}
+			stored = int(binary.LittleEndian.Uint32(data[pos:]))
+			pos = start + compressedBlockHeaderSize
+		}
+		if size > maxBlockSize || stored > maxBlockSize {
+			return nil, fmt.Errorf("bgcode block at %d is over %d bytes", start, maxBlockSize)
+		}
+		paramsSize := blockParamsSize
+		if blockType == blockThumbnail {
+			paramsSize = thumbnailParamsSize
+		}
+		end := pos + paramsSize + stored
+		if stored < 0 || end > len(data) || end < pos {
+			return nil, fmt.Errorf("bgcode block at %d runs past the end of the file", start)
+		}
+		params := data[pos : pos+paramsSize]
+		payload := data[pos+paramsSize : end]
+		pos = end
+		if checksum == checksumCRC32 {
+			if len(data)-pos < 4 {
+				return nil, fmt.Errorf("bgcode block at %d is missing its checksum", start)
+			}
+			if crc32.ChecksumIEEE(data[start:end]) != binary.LittleEndian.Uint32(data[pos:]) {
+				return nil, fmt.Errorf("bgcode block at %d fails its checksum", start)
+			}
+			pos += 4
+		}
+
+		if limit > 0 && int64(gcode.Len()+size) > limit {
+			return nil, fmt.Errorf("bgcode unpacks to more than %d bytes", limit)
+		}
+		block, err := decompressBlock(compression, payload, size)
+		if err != nil {
+			return nil, fmt.Errorf("bgcode block at %d: %w", start, err)
+		}
+		encoding := binary.LittleEndian.Uint16(params)
+		switch blockType {
+		case blockGcode:
+			switch encoding {
+			case encodingNone:
+				gcode.Write(block)
+			case encodingMeatPack, encodingMeatPackComments:
+				gcode.Write(unpackMeatPack(block))
+			default:
+				return nil, fmt.Errorf("bgcode block at %d has unknown gcode encoding %d", start, encoding)
+			}
+			// MeatPack packs two characters to a byte, so check again once it's unpacked
+			if limit > 0 && int64(gcode.Len()) > limit {
+				return nil, fmt.Errorf("bgcode unpacks to more than %d bytes", limit)
+			}
+		case blockFileMetadata, blockPrinterMetadata, blockPrintMetadata, blockSlicerMetadata:
+			if encoding != metadataEncodingINI {
+				return nil, fmt.Errorf("bgcode block at %d has unknown metadata encoding %d", start, encoding)
+			}
+			pairs := parseINI(block)
+			switch blockType {
+			case blockPrinterMetadata:
+				writeComments(&printerMeta, pairs)
+			case blockPrintMetadata:
+				writeComments(&printMeta, pairs)
+			case blockSlicerMetadata:
+				writeComments(&slicerMeta, pairs)
+				// The whole config is too much to keep as metadata, it's in the gcode's comments
+				continue
+			}
+			for _, kv := range pairs {
+				file.Metadata[kv[0]] = kv[1]
+			}
+		case blockThumbnail:
+			file.Thumbnails = append(file.Thumbnails, &Thumbnail{
+				Format: thumbnailFormats[encoding],
+				Width:  int(binary.LittleEndian.Uint16(params[2:])),
+				Height: int(binary.LittleEndian.Uint16(params[4:])),
+				Data:   block,
+			})
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(printerMeta.Bytes())
+	out.WriteString("\n")
+	out.Write(gcode.Bytes())
+	out.WriteString("\n")
+	out.Write(printMeta.Bytes())
+	if slicerMeta.Len() > 0 {
+		out.WriteString("\n; prusaslicer_config = begin\n")
+		out.Write(slicerMeta.Bytes())
+		out.WriteString("; prusaslicer_config = end\n")
+	}
+	file.Gcode = out.Bytes()
+	return file, nil
+}
+
+// decompressBlock unpacks a block to the size its header gives
+func decompressBlock(compression uint16, payload []byte, size int) ([]byte, error) {
+	var out []byte
+	var err error
+	switch compression {
+	case compressNone:
+		out = payload
+	case compressDeflate:
+		var r io.ReadCloser
+		r, err = zlib.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		out, err = ioutil.ReadAll(io.LimitReader(r, int64(size)+1))
+	case compressHeatshrink11:
+		out, err = unheatshrink(payload, 11, 4, size)
+	case compressHeatshrink12:
+		out, err = unheatshrink(payload, 12, 4, size)
+	default:
+		return nil, fmt.Errorf("unknown compression %d", compression)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != size {
+		return nil, fmt.Errorf("unpacked to %d bytes, expected %d", len(out), size)
+	}
+	return out, nil
+}
+
+// parseINI reads key=value lines in the order they come
+func parseINI(data []byte) [][2]string {
+	pairs := [][2]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		i := strings.Index(line, "=")
+		if i < 1 {
+			continue
+		}
+		pairs = append(pairs, [2]string{strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])})
+	}
+	return pairs
+}
+
+// writeComments writes key = value comments, the way text gcode carries metadata
+func writeComments(w *bytes.Buffer, pairs [][2]string) {
+	for _, kv := range pairs {
+		fmt.Fprintf(w, "; %s = %s\n", kv[0], kv[1])
+	}
+}
@@ -0,0 +1,178 @@
+package gcodefile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"strings"
+	"testing"
+)
+
+func le16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	return b
+}
+
+func le32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+// bgcodeHeader starts a version 1 file whose blocks carry a CRC32
+func bgcodeHeader() []byte {
+	return join(bgcodeMagic, le32(1), le16(checksumCRC32))
+}
+
+// bgcodeBlock builds a block with its checksum. size is what it unpacks to, payload is as stored.
+func bgcodeBlock(blockType, compression uint16, size int, params, payload []byte) []byte {
+	b := join(le16(blockType), le16(compression), le32(uint32(size)))
+	if compression != compressNone {
+		b = join(b, le32(uint32(len(payload))))
+	}
+	b = join(b, params, payload)
+	return join(b, le32(crc32.ChecksumIEEE(b)))
+}
+
+// plainBlock is an uncompressed block with the given encoding
+func plainBlock(blockType, encoding uint16, payload string) []byte {
+	return bgcodeBlock(blockType, compressNone, len(payload), le16(encoding), []byte(payload))
+}
+
+func TestDecodeBgcode(t *testing.T) {
+	thumbnailParams := []byte{0, 0, 16, 0, 9, 0} // png, 16x9
+	meatPacked := join(enablePacking, []byte{0x1d, 0xeb, 0x01, 0x0c})
+	gcodeBlock := plainBlock(blockGcode, encodingNone, "G28\nG1 X10\n")
+	badCRC := append([]byte{}, gcodeBlock...)
+	badCRC[len(badCRC)-1] ^= 0xff
+	tooBig := join(le16(blockGcode), le16(compressNone), le32(maxBlockSize+1))
+
+	tests := []struct {
+		name       string
+		data       []byte
+		limit      int64
+		wantGcode  []string
+		wantMeta   map[string]string
+		wantThumbs int
+		wantErr    string
+	}{
+		{
+			name: "metadata, gcode and thumbnail",
+			data: join(bgcodeHeader(),
+				plainBlock(blockFileMetadata, metadataEncodingINI, "Producer=PrusaSlicer 2.6.0\n"),
+				plainBlock(blockPrinterMetadata, metadataEncodingINI, "printer_model=MK4\nnozzle_diameter=0.4\n"),
+				bgcodeBlock(blockThumbnail, compressNone, 4, thumbnailParams, []byte("\x89PNG")),
+				gcodeBlock,
+				plainBlock(blockPrintMetadata, metadataEncodingINI, "estimated printing time (normal mode)=1h 2m\n"),
+				plainBlock(blockSlicerMetadata, metadataEncodingINI, "layer_height=0.2\n"),
+			),
+			wantGcode: []string{
+				"; printer_model = MK4\n",
+				"G28\nG1 X10\n",
+				"; estimated printing time (normal mode) = 1h 2m\n",
+				"; prusaslicer_config = begin\n; layer_height = 0.2\n; prusaslicer_config = end\n",
+			},
+			wantMeta: map[string]string{
+				"Producer":                              "PrusaSlicer 2.6.0",
+				"printer_model":                         "MK4",
+				"estimated printing time (normal mode)": "1h 2m",
+			},
+			wantThumbs: 1,
+		},
+		{
+			name: "heatshrink compressed",
+			data: join(bgcodeHeader(),
+				bgcodeBlock(blockGcode, compressHeatshrink11, 6, []byte{encodingNone, 0}, bits("1 01100001 1 01100010 0 00000000001 0011")),
+			),
+			wantGcode: []string{"ababab"},
+		},
+		{
+			name:      "meatpacked",
+			data:      join(bgcodeHeader(), plainBlock(blockGcode, encodingMeatPack, string(meatPacked))),
+			wantGcode: []string{"G1 X10\n"},
+		},
+		{
+			name:    "bad checksum",
+			data:    join(bgcodeHeader(), badCRC),
+			wantErr: "fails its checksum",
+		},
+		{
+			name:    "truncated block",
+			data:    join(bgcodeHeader(), gcodeBlock[:len(gcodeBlock)-6]),
+			wantErr: "runs past the end",
+		},
+		{
+			name:    "missing checksum",
+			data:    join(bgcodeHeader(), gcodeBlock[:len(gcodeBlock)-4]),
+			wantErr: "missing its checksum",
+		},
+		{
+			name:    "truncated header",
+			data:    join(bgcodeHeader(), gcodeBlock[:5]),
+			wantErr: "header at 10 is cut short",
+		},
+		{
+			name:    "block too big",
+			data:    join(bgcodeHeader(), tooBig),
+			wantErr: "is over",
+		},
+		{
+			name: "heatshrink short of its size",
+			data: join(bgcodeHeader(),
+				bgcodeBlock(blockGcode, compressHeatshrink11, 8, []byte{encodingNone, 0}, bits("1 01100001")),
+			),
+			wantErr: "expected 8",
+		},
+		{
+			name:    "over the limit",
+			data:    join(bgcodeHeader(), gcodeBlock),
+			limit:   4,
+			wantErr: "more than 4 bytes",
+		},
+		{
+			name:    "unknown version",
+			data:    join(bgcodeMagic, le32(2), le16(checksumCRC32)),
+			wantErr: "version 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := Decode(tt.data, tt.limit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.Format != FormatBgcode {
+				t.Errorf("got format %s", file.Format)
+			}
+			for _, want := range tt.wantGcode {
+				if !bytes.Contains(file.Gcode, []byte(want)) {
+					t.Errorf("gcode %q doesn't contain %q", file.Gcode, want)
+				}
+			}
+			for k, v := range tt.wantMeta {
+				if file.Metadata[k] != v {
+					t.Errorf("metadata %s is %q, want %q", k, file.Metadata[k], v)
+				}
+			}
+			if _, ok := file.Metadata["layer_height"]; ok {
+				t.Error("slicer settings were kept as metadata")
+			}
+			if len(file.Thumbnails) != tt.wantThumbs {
+				t.Fatalf("got %d thumbnails, want %d", len(file.Thumbnails), tt.wantThumbs)
+			}
+			if tt.wantThumbs > 0 {
+				thumb := file.Thumbnails[0]
+				if thumb.Format != "png" || thumb.Width != 16 || thumb.Height != 9 {
+					t.Errorf("got thumbnail %s %dx%d", thumb.Format, thumb.Width, thumb.Height)
+				}
+			}
+		})
+	}
+}
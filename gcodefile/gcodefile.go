@@ -0,0 +1,77 @@
+// Package gcodefile unpacks what slicers write into plain gcode a printer can be sent line by line.
+// It reads PrusaSlicer's binary gcode (.bgcode) and the plate gcode inside sliced 3MF archives,
+// along with the metadata and thumbnails they carry. Anything else is taken to be plain gcode.
+package gcodefile
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// FormatGcode is plain text gcode
+const FormatGcode = "gcode"
+
+// FormatBgcode is PrusaSlicer's binary gcode
+const FormatBgcode = "bgcode"
+
+// Format3MF is a 3MF archive with sliced plates in it
+const Format3MF = "3mf"
+
+// Thumbnail is a preview image the slicer rendered
+type Thumbnail struct {
+	Format string `json:"format"` // png, jpg or qoi
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Data   []byte `json:"-"`
+}
+
+// File is an unpacked file
+type File struct {
+	Format     string
+	Gcode      []byte            // Plain gcode, with the slicer's settings in comments as PrusaSlicer writes them
+	Metadata   map[string]string // What the slicer says about the print and the printer, empty for plain gcode
+	Thumbnails []*Thumbnail
+}
+
+// Decode unpacks data into plain gcode, telling the format from its first bytes.
+// The gcode can't be more than limit bytes so a small file can't fill memory, 0 is no limit.
+func Decode(data []byte, limit int64) (*File, error) {
+	switch {
+	case bytes.HasPrefix(data, bgcodeMagic):
+		return decodeBgcode(data, limit)
+	case bytes.HasPrefix(data, zipMagic):
+		return decode3MF(data, limit)
+	}
+	return &File{Format: FormatGcode, Gcode: data, Metadata: map[string]string{}}, nil
+}
+
+// GcodeName is the name the unpacked gcode goes by, .bgcode and .3mf become .gcode
+func GcodeName(name, format string) string {
+	lower := strings.ToLower(name)
+	switch format {
+	case FormatBgcode:
+		if strings.HasSuffix(lower, ".bgcode") {
+			return name[:len(name)-len(".bgcode")] + ".gcode"
+		}
+	case Format3MF:
+		base := strings.TrimSuffix(strings.TrimSuffix(lower, ".3mf"), ".gcode")
+		return name[:len(base)] + ".gcode"
+	}
+	return name
+}
+
+// Largest is the biggest thumbnail a browser can show, nil when there isn't one
+func (f *File) Largest() *Thumbnail {
+	thumbs := []*Thumbnail{}
+	for _, t := range f.Thumbnails {
+		if t.Format == "png" || t.Format == "jpg" {
+			thumbs = append(thumbs, t)
+		}
+	}
+	if len(thumbs) == 0 {
+		return nil
+	}
+	sort.Slice(thumbs, func(i, j int) bool { return thumbs[i].Width*thumbs[i].Height > thumbs[j].Width*thumbs[j].Height })
+	return thumbs[0]
+}
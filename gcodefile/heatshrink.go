@@ -0,0 +1,71 @@
+package gcodefile
+
+import "errors"
+
+// bitReader reads a byte slice a bit at a time, most significant bit first
+type bitReader struct {
+	data []byte
+	pos  int // In bits
+}
+
+// read takes the next n bits, false when there aren't that many left
+func (b *bitReader) read(n int) (int, bool) {
+	if b.pos+n > len(b.data)*8 {
+		return 0, false
+	}
+	v := 0
+	for i := 0; i < n; i++ {
+		bit := b.data[b.pos/8] >> (7 - uint(b.pos%8)) & 1
+		v = v<<1 | int(bit)
+		b.pos++
+	}
+	return v, true
+}
+
+// unheatshrink decompresses heatshrink data. Each token is a flag bit, then either a literal byte
+// or a back reference of window bits for the distance and lookahead bits for the length, both less one.
+// The encoder pads the last byte with zero bits, which never make up a whole token.
+func unheatshrink(data []byte, window, lookahead, size int) ([]byte, error) {
+	// The size comes from the file, only trust it as far as the data could plausibly unpack to
+	capacity := 64 * len(data)
+	if size < capacity {
+		capacity = size
+	}
+	out := make([]byte, 0, capacity)
+	r := &bitReader{data: data}
+	for {
+		literal, ok := r.read(1)
+		if !ok {
+			break
+		}
+		if literal == 1 {
+			b, ok := r.read(8)
+			if !ok {
+				break
+			}
+			out = append(out, byte(b))
+		} else {
+			index, ok := r.read(window)
+			if !ok {
+				break
+			}
+			count, ok := r.read(lookahead)
+			if !ok {
+				break
+			}
+			distance := index + 1
+			for i := 0; i <= count; i++ {
+				// The window starts out zeroed
+				var b byte
+				if distance <= len(out) {
+					b = out[len(out)-distance]
+				}
+				out = append(out, b)
+			}
+		}
+		if len(out) > size {
+			return nil, errors.New("heatshrink data unpacks past the block's size")
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package gcodefile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// bits packs a string of 0s and 1s into bytes, most significant bit first, padding the last
+// byte with zeros the way the encoder does. Spaces are ignored so tokens can be told apart.
+func bits(s string) []byte {
+	s = strings.Replace(s, " ", "", -1)
+	out := make([]byte, (len(s)+7)/8)
+	for i, c := range s {
+		if c == '1' {
+			out[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	return out
+}
+
+func TestUnheatshrink(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		size    int
+		want    []byte
+		wantErr string
+	}{
+		{
+			name: "literals",
+			data: bits("1 01100001 1 01100010"),
+			size: 2,
+			want: []byte("ab"),
+		},
+		{
+			name: "back reference",
+			// ab, then 4 bytes from 2 back
+			data: bits("1 01100001 1 01100010 0 00000000001 0011"),
+			size: 6,
+			want: []byte("ababab"),
+		},
+		{
+			name: "reference into the zeroed window",
+			data: bits("0 00000000000 0001"),
+			size: 2,
+			want: []byte{0, 0},
+		},
+		{
+			name: "partial token at the end is padding",
+			data: bits("1 01100001 1 0110"),
+			size: 2,
+			want: []byte("a"),
+		},
+		{
+			name:    "unpacks past the size",
+			data:    bits("1 01100001 1 01100010"),
+			size:    1,
+			wantErr: "past the block's size",
+		},
+		{
+			name:    "reference runs past the size",
+			data:    bits("1 01100001 0 00000000000 1111"),
+			size:    4,
+			wantErr: "past the block's size",
+		},
+		{
+			name: "huge size with little data",
+			data: bits("1 01100001"),
+			size: 1 << 30,
+			want: []byte("a"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unheatshrink(tt.data, 11, 4, tt.size)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if cap(got) > 64*len(tt.data)+len(tt.want) {
+				t.Errorf("allocated %d bytes for %d bytes of data", cap(got), len(tt.data))
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package gcodefile
+
+import "bytes"
+
+// MeatPack packs the characters gcode uses most into 4 bits each, two to a byte, low nibble first.
+// A nibble of all ones means a whole byte character follows. Commands to the unpacker are
+// two signal bytes then the command.
+const (
+	meatPackSignal          = 0xff
+	meatPackEnablePacking   = 251
+	meatPackDisablePacking  = 250
+	meatPackResetAll        = 249
+	meatPackEnableNoSpaces  = 247
+	meatPackDisableNoSpaces = 246
+	meatPackFullChar        = 0xf
+)
+
+// meatPackChars are the characters the nibbles stand for. In no spaces mode the space is E instead.
+var meatPackChars = []byte("0123456789. \nGX")
+
+// gLineParameters are the parameters that need a space put back in front of them in no spaces mode
+var gLineParameters = []byte("XYZEFIJRPWHCA")
+
+// unpackMeatPack turns MeatPack encoded gcode back into text
+func unpackMeatPack(data []byte) []byte {
+	var out bytes.Buffer
+	packing := false
+	noSpaces := false
+	full := 0     // Whole byte characters still to come
+	var held byte // Packed character that comes after the next whole byte one
+	gLine := false
+
+	emit := func(c byte) {
+		// Spaces were dropped from G lines, put them back ahead of each parameter
+		switch {
+		case c == '\n':
+			gLine = false
+			// Blank lines aren't kept
+			if out.Len() > 0 && out.Bytes()[out.Len()-1] == '\n' {
+				return
+			}
+		case c == ';':
+			gLine = false
+		case c == 'G' && (out.Len() == 0 || out.Bytes()[out.Len()-1] == '\n'):
+			gLine = true
+		case gLine && noSpaces && bytes.IndexByte(gLineParameters, c) >= 0 && out.Bytes()[out.Len()-1] != ' ':
+			out.WriteByte(' ')
+		}
+		out.WriteByte(c)
+	}
+	char := func(nibble byte) byte {
+		if nibble == 11 && noSpaces {
+			return 'E'
+		}
+		return meatPackChars[nibble]
+	}
+	unpack := func(c byte) {
+		if !packing {
+			emit(c)
+			return
+		}
+		if full > 0 {
+			emit(c)
+			if held != 0 {
+				emit(held)
+				held = 0
+			}
+			full--
+			return
+		}
+		low, high := c&0xf, c>>4
+		if low == meatPackFullChar {
+			full++
+			if high == meatPackFullChar {
+				full++
+			} else {
+				held = char(high)
+			}
+			return
+		}
+		first := char(low)
+		emit(first)
+		// A line can end on the low nibble, the high one is padding
+		if first == '\n' {
+			return
+		}
+		if high == meatPackFullChar {
+			full++
+		} else {
+			emit(char(high))
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		if data[i] == meatPackSignal && i+2 < len(data) && data[i+1] == meatPackSignal {
+			switch data[i+2] {
+			case meatPackEnablePacking:
+				packing = true
+			case meatPackDisablePacking, meatPackResetAll:
+				packing = false
+			case meatPackEnableNoSpaces:
+				noSpaces = true
+			case meatPackDisableNoSpaces:
+				noSpaces = false
+			}
+			i += 2
+			continue
+		}
+		unpack(data[i])
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,73 @@
+package gcodefile
+
+import "testing"
+
+// Signals that switch the unpacker's modes
+var (
+	enablePacking  = []byte{meatPackSignal, meatPackSignal, meatPackEnablePacking}
+	disablePacking = []byte{meatPackSignal, meatPackSignal, meatPackDisablePacking}
+	enableNoSpaces = []byte{meatPackSignal, meatPackSignal, meatPackEnableNoSpaces}
+)
+
+func join(parts ...[]byte) []byte {
+	out := []byte{}
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func TestUnpackMeatPack(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "not packed",
+			data: []byte("G1 X10\n"),
+			want: "G1 X10\n",
+		},
+		{
+			name: "packed",
+			// G1, space X, 10, newline with the high nibble as padding
+			data: join(enablePacking, []byte{0x1d, 0xeb, 0x01, 0x0c}),
+			want: "G1 X10\n",
+		},
+		{
+			name: "whole byte character ahead of a packed one",
+			// M is sent whole after the byte that holds the 1 following it
+			data: join(enablePacking, []byte{0x1f, 'M', 0x0c}),
+			want: "M1\n",
+		},
+		{
+			name: "two whole byte characters",
+			data: join(enablePacking, []byte{0xff, 'M', '8', 0x0c}),
+			want: "M8\n",
+		},
+		{
+			name: "no spaces puts them back ahead of parameters",
+			// In no spaces mode the space nibble is E
+			data: join(enablePacking, enableNoSpaces, []byte{0x1d, 0x1e, 0xc0, 0x1d, 0x5b, 0x0c}),
+			want: "G1 X10\nG1 E5\n",
+		},
+		{
+			name: "packing turned off again",
+			data: join(enablePacking, []byte{0x1d, 0x0c}, disablePacking, []byte("M84\n")),
+			want: "G1\nM84\n",
+		},
+		{
+			name: "blank lines are dropped",
+			data: join(enablePacking, []byte{0x1d, 0xcc, 0x0c}),
+			want: "G1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(unpackMeatPack(tt.data))
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
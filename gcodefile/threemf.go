@@ -0,0 +1,158 @@
+package gcodefile
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// zipMagic starts a zip archive, which a 3MF file is
+var zipMagic = []byte("PK\x03\x04")
+
+// maxInfoSize caps the slice info and thumbnails read out of an archive
+const maxInfoSize = 16 << 20
+
+// plateGcode matches the gcode slicers like Bambu Studio and Orca put in a sliced 3MF, one file per plate
+var plateGcode = regexp.MustCompile(`(?i)^Metadata/plate_(\d+)\.gcode$`)
+
+// sliceInfo is Metadata/slice_info.config, what the slicer says about each plate
+type sliceInfo struct {
+	Plates []struct {
+		Metadata []struct {
+			Key   string `xml:"key,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"metadata"`
+		Filaments []struct {
+			Type  string `xml:"type,attr"`
+			Color string `xml:"color,attr"`
+			UsedM string `xml:"used_m,attr"`
+			UsedG string `xml:"used_g,attr"`
+		} `xml:"filament"`
+	} `xml:"plate"`
+}
+
+// decode3MF takes the gcode of the first sliced plate out of a 3MF archive
+func decode3MF(data []byte, limit int64) (*File, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading 3mf archive: %w", err)
+	}
+	files := map[string]*zip.File{}
+	plateFiles := map[int]*zip.File{}
+	plates := []int{}
+	for _, f := range archive.File {
+		files[strings.ToLower(f.Name)] = f
+		if m := plateGcode.FindStringSubmatch(f.Name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			plateFiles[n] = f
+			plates = append(plates, n)
+		}
+	}
+	if len(plates) == 0 {
+		return nil, errors.New("3mf archive has no sliced plates, slice it and export the plate gcode or a sliced 3mf")
+	}
+	sort.Ints(plates)
+	plate := plates[0]
+
+	gcode, err := readZipFile(plateFiles[plate], limit)
+	if err != nil {
+		return nil, err
+	}
+	file := &File{Format: Format3MF, Gcode: gcode, Metadata: map[string]string{"plate": strconv.Itoa(plate)}}
+	// Plates can hold binary gcode too
+	if bytes.HasPrefix(gcode, bgcodeMagic) {
+		inner, err := decodeBgcode(gcode, limit)
+		if err != nil {
+			return nil, err
+		}
+		file.Gcode = inner.Gcode
+		file.Thumbnails = inner.Thumbnails
+		for k, v := range inner.Metadata {
+			file.Metadata[k] = v
+		}
+	}
+
+	if f, ok := files["metadata/slice_info.config"]; ok {
+		b, err := readZipFile(f, maxInfoSize)
+		if err != nil {
+			return nil, err
+		}
+		info := &sliceInfo{}
+		err = xml.Unmarshal(b, info)
+		if err != nil {
+			return nil, fmt.Errorf("reading 3mf slice info: %w", err)
+		}
+		for _, p := range info.Plates {
+			values := map[string]string{}
+			for _, m := range p.Metadata {
+				values[m.Key] = m.Value
+			}
+			if values["index"] != strconv.Itoa(plate) {
+				continue
+			}
+			delete(values, "index")
+			for k, v := range values {
+				file.Metadata[k] = v
+			}
+			// Filaments are listed the way PrusaSlicer lists them, separated by semicolons
+			types, colors, usedM, usedG := []string{}, []string{}, []string{}, []string{}
+			for _, f := range p.Filaments {
+				types = append(types, f.Type)
+				colors = append(colors, f.Color)
+				usedM = append(usedM, f.UsedM)
+				usedG = append(usedG, f.UsedG)
+			}
+			if len(types) > 0 {
+				file.Metadata["filament_type"] = strings.Join(types, ";")
+				file.Metadata["filament_colour"] = strings.Join(colors, ";")
+				file.Metadata["filament used [m]"] = strings.Join(usedM, ";")
+				file.Metadata["filament used [g]"] = strings.Join(usedG, ";")
+			}
+		}
+	}
+
+	if f, ok := files[fmt.Sprintf("metadata/plate_%d.png", plate)]; ok {
+		b, err := readZipFile(f, maxInfoSize)
+		if err != nil {
+			return nil, err
+		}
+		thumb := &Thumbnail{Format: "png", Data: b}
+		if len(b) >= 24 {
+			// Width and height are the first fields of the IHDR chunk
+			thumb.Width = int(binary.BigEndian.Uint32(b[16:]))
+			thumb.Height = int(binary.BigEndian.Uint32(b[20:]))
+		}
+		file.Thumbnails = append(file.Thumbnails, thumb)
+	}
+	return file, nil
+}
+
+// readZipFile reads a file out of the archive, refusing to unpack more than limit bytes, 0 is no limit
+func readZipFile(f *zip.File, limit int64) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s from 3mf archive: %w", f.Name, err)
+	}
+	defer r.Close()
+	var src io.Reader = r
+	if limit > 0 {
+		src = io.LimitReader(r, limit+1)
+	}
+	b, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s from 3mf archive: %w", f.Name, err)
+	}
+	if limit > 0 && int64(len(b)) > limit {
+		return nil, fmt.Errorf("%s unpacks to more than %d bytes", f.Name, limit)
+	}
+	return b, nil
+}
@@ -0,0 +1,193 @@
+package gcodefile
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// archive zips the files up the way a slicer would
+func archive(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = f.Write([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// pngHeader is the start of a PNG, as far as the width and height in its IHDR chunk
+func pngHeader(width, height uint32) string {
+	return "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR" + string(join(be32(width), be32(height)))
+}
+
+func be32(v uint32) []byte {
+	return []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+const sliceInfoConfig = `<?xml version="1.0" encoding="UTF-8"?>
+<config>
+  <plate>
+    <metadata key="index" value="1"/>
+    <metadata key="prediction" value="3600"/>
+    <filament type="PLA" color="#FF0000" used_m="1.5" used_g="4.5"/>
+    <filament type="PETG" color="#00FF00" used_m="0.5" used_g="1.5"/>
+  </plate>
+  <plate>
+    <metadata key="index" value="2"/>
+    <metadata key="prediction" value="60"/>
+  </plate>
+</config>`
+
+func TestDecode3MF(t *testing.T) {
+	bgcode := join(bgcodeHeader(),
+		plainBlock(blockPrinterMetadata, metadataEncodingINI, "printer_model=MK4\n"),
+		plainBlock(blockGcode, encodingNone, "G28\n"),
+	)
+	tests := []struct {
+		name       string
+		files      map[string]string
+		limit      int64
+		wantGcode  string
+		wantMeta   map[string]string
+		wantThumbs int
+		wantErr    string
+	}{
+		{
+			name: "first plate with its slice info and thumbnail",
+			files: map[string]string{
+				"3D/3dmodel.model":             "<model/>",
+				"Metadata/plate_2.gcode":       "G1 X2\n",
+				"Metadata/plate_1.gcode":       "G1 X1\n",
+				"Metadata/slice_info.config":   sliceInfoConfig,
+				"Metadata/plate_1.png":         pngHeader(512, 256),
+				"Metadata/plate_2.png":         pngHeader(64, 64),
+				"Metadata/project_settings.js": "{}",
+			},
+			wantGcode: "G1 X1\n",
+			wantMeta: map[string]string{
+				"plate":             "1",
+				"prediction":        "3600",
+				"filament_type":     "PLA;PETG",
+				"filament_colour":   "#FF0000;#00FF00",
+				"filament used [m]": "1.5;0.5",
+				"filament used [g]": "4.5;1.5",
+			},
+			wantThumbs: 1,
+		},
+		{
+			name: "zero padded plate numbers",
+			files: map[string]string{
+				"Metadata/plate_02.gcode": "G1 X2\n",
+				"Metadata/plate_01.gcode": "G1 X1\n",
+			},
+			wantGcode: "G1 X1\n",
+			wantMeta:  map[string]string{"plate": "1"},
+		},
+		{
+			name:      "binary gcode plate",
+			files:     map[string]string{"Metadata/plate_1.gcode": string(bgcode)},
+			wantGcode: "G28\n",
+			wantMeta:  map[string]string{"plate": "1", "printer_model": "MK4"},
+		},
+		{
+			name:    "not sliced",
+			files:   map[string]string{"3D/3dmodel.model": "<model/>"},
+			wantErr: "no sliced plates",
+		},
+		{
+			name:    "over the limit",
+			files:   map[string]string{"Metadata/plate_1.gcode": "G1 X1\n"},
+			limit:   3,
+			wantErr: "more than 3 bytes",
+		},
+		{
+			name: "bad slice info",
+			files: map[string]string{
+				"Metadata/plate_1.gcode":     "G1 X1\n",
+				"Metadata/slice_info.config": "<config><plate>",
+			},
+			wantErr: "slice info",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := Decode(archive(t, tt.files), tt.limit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.Format != Format3MF {
+				t.Errorf("got format %s", file.Format)
+			}
+			if !strings.Contains(string(file.Gcode), tt.wantGcode) {
+				t.Errorf("gcode %q doesn't contain %q", file.Gcode, tt.wantGcode)
+			}
+			for k, v := range tt.wantMeta {
+				if file.Metadata[k] != v {
+					t.Errorf("metadata %s is %q, want %q", k, file.Metadata[k], v)
+				}
+			}
+			if len(file.Thumbnails) != tt.wantThumbs {
+				t.Fatalf("got %d thumbnails, want %d", len(file.Thumbnails), tt.wantThumbs)
+			}
+			if tt.wantThumbs > 0 {
+				thumb := file.Thumbnails[0]
+				if thumb.Width != 512 || thumb.Height != 256 {
+					t.Errorf("got thumbnail %dx%d, want 512x256", thumb.Width, thumb.Height)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodePlain(t *testing.T) {
+	file, err := Decode([]byte("G28\n"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Format != FormatGcode || string(file.Gcode) != "G28\n" {
+		t.Errorf("got %s %q", file.Format, file.Gcode)
+	}
+}
+
+func TestGcodeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{"benchy.gcode", FormatGcode, "benchy.gcode"},
+		{"benchy.bgcode", FormatBgcode, "benchy.gcode"},
+		{"Benchy.BGCODE", FormatBgcode, "Benchy.gcode"},
+		{"benchy", FormatBgcode, "benchy"},
+		{"benchy.3mf", Format3MF, "benchy.gcode"},
+		{"benchy.gcode.3mf", Format3MF, "benchy.gcode"},
+		{"benchy", Format3MF, "benchy.gcode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GcodeName(tt.name, tt.format)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ require (
 	github.com/google/martian v2.1.0+incompatible
 	github.com/hedhyw/Go-Serial-Detector v0.0.0-20180519193840-52aaeeddaad6
 	github.com/jmoiron/sqlx v1.2.0
+	github.com/klauspost/compress v1.10.3
 	github.com/lib/pq v1.8.0
 	github.com/mattn/goreman v0.3.7
 	github.com/ninja-software/terror v0.0.7
@@ -34,6 +35,7 @@ require (
 	github.com/volatiletech/strmangle v0.0.1
 	go.bug.st/serial v1.1.1
 	go.uber.org/zap v1.16.0
+	golang.org/x/crypto v0.0.0-20201117144127-c1f2f97bffc9
 	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68 // indirect
 	nhooyr.io/websocket v1.8.6
 	syreclabs.com/go/faker v1.2.3
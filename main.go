@@ -2,27 +2,29 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go-3dprint/agent"
+	"go-3dprint/client"
+	"go-3dprint/db"
 	"go-3dprint/seed"
 	"go-3dprint/server"
 	"net/http"
+	"net/url"
 	"os"
+	"text/tabwriter"
 	"time"
 
-	"github.com/avast/retry-go"
-
 	_ "github.com/lib/pq"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/ninja-software/terror"
 	"github.com/oklog/run"
 	"github.com/urfave/cli/v2"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
-	"go.bug.st/serial"
 	"go.uber.org/zap"
-	"nhooyr.io/websocket"
 )
 
 var log *zap.SugaredLogger
@@ -82,7 +84,10 @@ func main() {
 					&cli.StringFlag{Name: "websocket_host", Usage: "Set the websocket host", EnvVars: []string{"WEBSOCKET_HOST"}, Value: "localhost"},
 					&cli.StringFlag{Name: "websocket_port", Usage: "Set the websocket port", EnvVars: []string{"WEBSOCKET_PORT"}, Value: "8080"},
 					&cli.IntFlag{Name: "baud_rate", Usage: "Set the baud rate", EnvVars: []string{"BAUD_RATE"}, Value: 115200},
-					&cli.StringFlag{Name: "serial_device", Usage: "Set the serial port", EnvVars: []string{"SERIAL_PORT"}, Required: true},
+					&cli.StringFlag{Name: "serial_device", Usage: "Set the serial port, auto to find the printer", EnvVars: []string{"SERIAL_PORT"}, Required: true},
+					&cli.StringFlag{Name: "token", Usage: "Agent token issued for the printer", EnvVars: []string{"AGENT_TOKEN"}, Required: true},
+					&cli.StringFlag{Name: "flow_control", Usage: "ping-pong or advanced", EnvVars: []string{"FLOW_CONTROL"}, Value: string(agent.FlowPingPong)},
+					&cli.IntFlag{Name: "rx_buffer_size", Usage: "Firmware serial receive buffer in bytes, used by advanced flow control", EnvVars: []string{"RX_BUFFER_SIZE"}, Value: agent.DefaultRXBufferSize},
 					&cli.StringFlag{Name: "database_user", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_USER"}, Usage: "The database user"},
 					&cli.StringFlag{Name: "database_pass", Value: "dev", EnvVars: []string{"GOPRINT_DATABASE_PASS"}, Usage: "The database pass"},
 					&cli.StringFlag{Name: "database_host", Value: "localhost", EnvVars: []string{"GOPRINT_DATABASE_HOST"}, Usage: "The database host"},
@@ -107,14 +112,22 @@ func main() {
 						return terror.New(err, "")
 					}
 					boil.SetDB(conn)
+					flowMode, err := agent.ParseFlowMode(c.String("flow_control"))
+					if err != nil {
+						return terror.New(err, "")
+					}
 					return devCommand(
 						c.Context,
 						c.String(("addr")),
 						c.String("server_host"),
-						c.Int("baud_rate"),
-						c.String("serial_device"),
-						c.String("websocket_host"),
-						c.String("websocket_port"),
+						&agent.PrinterConfig{
+							SerialDevice: c.String("serial_device"),
+							Token:        c.String("token"),
+							BaudRate:     c.Int("baud_rate"),
+							FlowControl:  flowMode,
+							RXBufferSize: c.Int("rx_buffer_size"),
+						},
+						&agent.ServerConfig{URL: agent.ServerURL(c.String("websocket_host"), c.String("websocket_port"))},
 					)
 				},
 			},
@@ -125,6 +138,11 @@ func main() {
 				Flags: []cli.Flag{
 					&cli.StringFlag{Name: "server_host", Usage: "Location of the master server", EnvVars: []string{"SERVER_HOST"}, Value: "http://localhost:8080"},
 					&cli.StringFlag{Name: "addr", Usage: "Addr to host", EnvVars: []string{"SERVER_ADDR"}, Value: ":8080"},
+					&cli.StringFlag{Name: "tls_cert", Usage: "PEM certificate to serve HTTPS with", EnvVars: []string{"TLS_CERT"}},
+					&cli.StringFlag{Name: "tls_key", Usage: "PEM key for the certificate", EnvVars: []string{"TLS_KEY"}},
+					&cli.BoolFlag{Name: "tls_self_signed", Usage: "Generate a self-signed certificate at tls_cert and tls_key if there isn't one", EnvVars: []string{"TLS_SELF_SIGNED"}},
+					&cli.StringFlag{Name: "tls_client_ca", Usage: "Require agents to present a certificate signed by this PEM bundle", EnvVars: []string{"TLS_CLIENT_CA"}},
+					&cli.StringSliceFlag{Name: "allowed_origins", Usage: "Origins browsers may call the API from, defaults to server_host", EnvVars: []string{"ALLOWED_ORIGINS"}},
 					&cli.StringFlag{Name: "database_user", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_USER"}, Usage: "The database user"},
 					&cli.StringFlag{Name: "database_pass", Value: "dev", EnvVars: []string{"GOPRINT_DATABASE_PASS"}, Usage: "The database pass"},
 					&cli.StringFlag{Name: "database_host", Value: "localhost", EnvVars: []string{"GOPRINT_DATABASE_HOST"}, Usage: "The database host"},
@@ -149,7 +167,12 @@ func main() {
 					}
 					boil.SetDB(conn)
 
-					return serveCommand(c.Context, c.String("addr"), c.String("server_host"))
+					return serveCommand(c.Context, c.String("addr"), c.String("server_host"), c.StringSlice("allowed_origins"), &server.TLSOptions{
+						CertFile:     c.String("tls_cert"),
+						KeyFile:      c.String("tls_key"),
+						SelfSigned:   c.Bool("tls_self_signed"),
+						ClientCAFile: c.String("tls_client_ca"),
+					})
 				},
 			},
 			{
@@ -174,21 +197,452 @@ func main() {
 						Value:   "8080",
 					},
 					&cli.StringFlag{
-						Name:     "serial_device",
-						Usage:    "Set the serial port",
-						EnvVars:  []string{"SERIAL_PORT"},
-						Required: true,
+						Name:    "server_url",
+						Usage:   "Full websocket URL of the server, ws:// or wss://, instead of websocket_host and websocket_port",
+						EnvVars: []string{"SERVER_URL"},
+					},
+					&cli.StringFlag{
+						Name:    "ca_file",
+						Usage:   "PEM bundle to trust the server's certificate with, such as its self-signed certificate",
+						EnvVars: []string{"AGENT_CA_FILE"},
+					},
+					&cli.StringFlag{
+						Name:    "cert_file",
+						Usage:   "Client certificate for servers that require mutual TLS",
+						EnvVars: []string{"AGENT_CERT_FILE"},
+					},
+					&cli.StringFlag{
+						Name:    "key_file",
+						Usage:   "Key for the client certificate",
+						EnvVars: []string{"AGENT_KEY_FILE"},
+					},
+					&cli.StringFlag{
+						Name:    "serial_device",
+						Usage:   "Set the serial port, auto to find the printer and its baud rate",
+						EnvVars: []string{"SERIAL_PORT"},
+					},
+					&cli.StringFlag{
+						Name:    "token",
+						Usage:   "Agent token issued for the printer by the server",
+						EnvVars: []string{"AGENT_TOKEN"},
+					},
+					&cli.StringFlag{
+						Name:    "config",
+						Usage:   "JSON file listing several printers to run, instead of serial_device",
+						EnvVars: []string{"AGENT_CONFIG"},
+					},
+					&cli.StringFlag{
+						Name:    "flow_control",
+						Usage:   "ping-pong waits for each ok, advanced keeps several lines in flight",
+						EnvVars: []string{"FLOW_CONTROL"},
+						Value:   string(agent.FlowPingPong),
+					},
+					&cli.IntFlag{
+						Name:    "rx_buffer_size",
+						Usage:   "Firmware serial receive buffer in bytes, used by advanced flow control",
+						EnvVars: []string{"RX_BUFFER_SIZE"},
+						Value:   agent.DefaultRXBufferSize,
 					},
 				},
 				Usage: "Print a gcode file",
 				Action: func(c *cli.Context) error {
+					if c.String("config") != "" {
+						// Everything, the server included, comes from the config file
+						cfg, err := agent.LoadConfig(c.String("config"))
+						if err != nil {
+							return terror.New(err, "")
+						}
+						return superviseAgents(c.Context, cfg)
+					}
+					if c.String("serial_device") == "" || c.String("token") == "" {
+						return terror.New(errors.New("serial_device and token, or config, are required"), "")
+					}
+					flowMode, err := agent.ParseFlowMode(c.String("flow_control"))
+					if err != nil {
+						return terror.New(err, "")
+					}
+					serverURL := c.String("server_url")
+					if serverURL == "" {
+						serverURL = agent.ServerURL(c.String("websocket_host"), c.String("websocket_port"))
+					}
 					return agentCommand(
 						c.Context,
-						c.Int("baud_rate"),
-						c.String("serial_device"),
-						c.String("websocket_host"),
-						c.String("websocket_port"),
+						&agent.PrinterConfig{
+							SerialDevice: c.String("serial_device"),
+							Token:        c.String("token"),
+							BaudRate:     c.Int("baud_rate"),
+							FlowControl:  flowMode,
+							RXBufferSize: c.Int("rx_buffer_size"),
+						},
+						&agent.ServerConfig{
+							URL:      serverURL,
+							CAFile:   c.String("ca_file"),
+							CertFile: c.String("cert_file"),
+							KeyFile:  c.String("key_file"),
+						},
+					)
+				},
+			},
+			{
+				Name:  "printer",
+				Usage: "Register printers and manage their agent tokens",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "database_user", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_USER"}, Usage: "The database user"},
+					&cli.StringFlag{Name: "database_pass", Value: "dev", EnvVars: []string{"GOPRINT_DATABASE_PASS"}, Usage: "The database pass"},
+					&cli.StringFlag{Name: "database_host", Value: "localhost", EnvVars: []string{"GOPRINT_DATABASE_HOST"}, Usage: "The database host"},
+					&cli.StringFlag{Name: "database_port", Value: "5432", EnvVars: []string{"GOPRINT_DATABASE_PORT"}, Usage: "The database port"},
+					&cli.StringFlag{Name: "database_name", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_NAME"}, Usage: "The database name"},
+				},
+				Before: func(c *cli.Context) error {
+					conn, err := connect(
+						c.String("database_user"),
+						c.String("database_pass"),
+						c.String("database_host"),
+						c.String("database_port"),
+						c.String("database_name"),
+					)
+					if err != nil {
+						return terror.New(err, "")
+					}
+					boil.SetDB(conn)
+					return nil
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:  "add",
+						Usage: "Register a printer",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "name", Usage: "Name of the printer", Required: true},
+						},
+						Action: func(c *cli.Context) error {
+							printer, err := server.CreatePrinter(c.String("name"))
+							if err != nil {
+								return terror.New(err, "")
+							}
+							fmt.Println(printer.ID)
+							return nil
+						},
+					},
+					{
+						Name:  "list",
+						Usage: "List registered printers",
+						Action: func(c *cli.Context) error {
+							printers, err := db.Printers(db.PrinterWhere.DeletedAt.IsNull()).AllG()
+							if err != nil {
+								return terror.New(err, "")
+							}
+							tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+							fmt.Fprintln(tw, "ID\tNAME\tCREATED")
+							for _, p := range printers {
+								fmt.Fprintf(tw, "%s\t%s\t%s\n", p.ID, p.Name, p.CreatedAt.Format(time.RFC3339))
+							}
+							return tw.Flush()
+						},
+					},
+					{
+						Name:  "token",
+						Usage: "Manage agent tokens",
+						Subcommands: []*cli.Command{
+							{
+								Name:  "issue",
+								Usage: "Issue a token for a printer, shown only once",
+								Flags: []cli.Flag{
+									&cli.StringFlag{Name: "printer", Usage: "Name of the printer", Required: true},
+								},
+								Action: func(c *cli.Context) error {
+									printer, err := server.FindPrinterByName(c.String("printer"))
+									if err != nil {
+										return terror.New(err, "")
+									}
+									token, _, err := server.IssueAgentToken(printer.ID)
+									if err != nil {
+										return terror.New(err, "")
+									}
+									fmt.Println(token)
+									return nil
+								},
+							},
+							{
+								Name:  "list",
+								Usage: "List the tokens issued for a printer",
+								Flags: []cli.Flag{
+									&cli.StringFlag{Name: "printer", Usage: "Name of the printer", Required: true},
+								},
+								Action: func(c *cli.Context) error {
+									printer, err := server.FindPrinterByName(c.String("printer"))
+									if err != nil {
+										return terror.New(err, "")
+									}
+									tokens, err := server.ListAgentTokens(printer.ID)
+									if err != nil {
+										return terror.New(err, "")
+									}
+									tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+									fmt.Fprintln(tw, "ID\tCREATED\tLAST USED\tREVOKED")
+									for _, t := range tokens {
+										fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.ID, t.CreatedAt.Format(time.RFC3339), formatTime(t.LastUsedAt), formatTime(t.RevokedAt))
+									}
+									return tw.Flush()
+								},
+							},
+							{
+								Name:  "revoke",
+								Usage: "Revoke a token, agents using it can't connect again",
+								Flags: []cli.Flag{
+									&cli.StringFlag{Name: "id", Usage: "ID of the token", Required: true},
+								},
+								Action: func(c *cli.Context) error {
+									return server.RevokeAgentToken(c.String("id"))
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "user",
+				Usage: "Manage users, use add with --role admin to create the first admin",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "database_user", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_USER"}, Usage: "The database user"},
+					&cli.StringFlag{Name: "database_pass", Value: "dev", EnvVars: []string{"GOPRINT_DATABASE_PASS"}, Usage: "The database pass"},
+					&cli.StringFlag{Name: "database_host", Value: "localhost", EnvVars: []string{"GOPRINT_DATABASE_HOST"}, Usage: "The database host"},
+					&cli.StringFlag{Name: "database_port", Value: "5432", EnvVars: []string{"GOPRINT_DATABASE_PORT"}, Usage: "The database port"},
+					&cli.StringFlag{Name: "database_name", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_NAME"}, Usage: "The database name"},
+				},
+				Before: func(c *cli.Context) error {
+					conn, err := connect(
+						c.String("database_user"),
+						c.String("database_pass"),
+						c.String("database_host"),
+						c.String("database_port"),
+						c.String("database_name"),
+					)
+					if err != nil {
+						return terror.New(err, "")
+					}
+					boil.SetDB(conn)
+					return nil
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:  "add",
+						Usage: "Add a user",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "email", Usage: "Email the user logs in with", Required: true},
+							&cli.StringFlag{Name: "password", Usage: "Password for the user", EnvVars: []string{"GOPRINT_USER_PASSWORD"}, Required: true},
+							&cli.StringFlag{Name: "role", Usage: "viewer, operator or admin", Value: string(server.RoleViewer)},
+						},
+						Action: func(c *cli.Context) error {
+							role, err := server.ParseRole(c.String("role"))
+							if err != nil {
+								return terror.New(err, "")
+							}
+							user, err := server.CreateUser(c.String("email"), c.String("password"), role)
+							if err != nil {
+								return terror.New(err, "")
+							}
+							fmt.Println(user.ID)
+							return nil
+						},
+					},
+					{
+						Name:  "list",
+						Usage: "List users",
+						Action: func(c *cli.Context) error {
+							users, err := db.Users(db.UserWhere.DeletedAt.IsNull()).AllG()
+							if err != nil {
+								return terror.New(err, "")
+							}
+							tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+							fmt.Fprintln(tw, "ID\tEMAIL\tROLE\tCREATED")
+							for _, u := range users {
+								fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", u.ID, u.Email, u.Role, u.CreatedAt.Format(time.RFC3339))
+							}
+							return tw.Flush()
+						},
+					},
+					{
+						Name:  "password",
+						Usage: "Set a user's password",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "email", Usage: "Email of the user", Required: true},
+							&cli.StringFlag{Name: "password", Usage: "New password", EnvVars: []string{"GOPRINT_USER_PASSWORD"}, Required: true},
+						},
+						Action: func(c *cli.Context) error {
+							user, err := server.FindUserByEmail(c.String("email"))
+							if err != nil {
+								return terror.New(err, "")
+							}
+							return server.SetPassword(user, c.String("password"))
+						},
+					},
+					{
+						Name:  "role",
+						Usage: "Change a user's role",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "email", Usage: "Email of the user", Required: true},
+							&cli.StringFlag{Name: "role", Usage: "viewer, operator or admin", Required: true},
+						},
+						Action: func(c *cli.Context) error {
+							role, err := server.ParseRole(c.String("role"))
+							if err != nil {
+								return terror.New(err, "")
+							}
+							user, err := server.FindUserByEmail(c.String("email"))
+							if err != nil {
+								return terror.New(err, "")
+							}
+							user.Role = string(role)
+							_, err = user.UpdateG(boil.Whitelist(db.UserColumns.Role, db.UserColumns.UpdatedAt))
+							if err != nil {
+								return terror.New(err, "")
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "audit",
+				Usage: "Read the audit log of who did what to which printer",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "database_user", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_USER"}, Usage: "The database user"},
+					&cli.StringFlag{Name: "database_pass", Value: "dev", EnvVars: []string{"GOPRINT_DATABASE_PASS"}, Usage: "The database pass"},
+					&cli.StringFlag{Name: "database_host", Value: "localhost", EnvVars: []string{"GOPRINT_DATABASE_HOST"}, Usage: "The database host"},
+					&cli.StringFlag{Name: "database_port", Value: "5432", EnvVars: []string{"GOPRINT_DATABASE_PORT"}, Usage: "The database port"},
+					&cli.StringFlag{Name: "database_name", Value: "goprint", EnvVars: []string{"GOPRINT_DATABASE_NAME"}, Usage: "The database name"},
+				},
+				Before: func(c *cli.Context) error {
+					conn, err := connect(
+						c.String("database_user"),
+						c.String("database_pass"),
+						c.String("database_host"),
+						c.String("database_port"),
+						c.String("database_name"),
 					)
+					if err != nil {
+						return terror.New(err, "")
+					}
+					boil.SetDB(conn)
+					return nil
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:  "export",
+						Usage: "Export the audit log as CSV, newest first",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "printer", Usage: "Only events for the printer with this name"},
+							&cli.StringFlag{Name: "user", Usage: "Only events by the user with this email"},
+							&cli.StringFlag{Name: "action", Usage: "Only events with this action, such as cancel"},
+							&cli.StringFlag{Name: "since", Usage: "Only events at or after this RFC 3339 time"},
+							&cli.StringFlag{Name: "until", Usage: "Only events before this RFC 3339 time"},
+							&cli.IntFlag{Name: "limit", Usage: "At most this many events, 0 for all"},
+							&cli.StringFlag{Name: "output", Usage: "File to write to instead of stdout"},
+						},
+						Action: func(c *cli.Context) error {
+							return auditExportCommand(c)
+						},
+					},
+				},
+			},
+			{
+				Name:  "client",
+				Usage: "Drive a running server through its API with an API token",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "server", Usage: "Where the server is", EnvVars: []string{"GOPRINT_SERVER"}, Value: "http://localhost:8080"},
+					&cli.StringFlag{Name: "token", Usage: "API token issued to your user", EnvVars: []string{"GOPRINT_TOKEN"}, Required: true},
+					&cli.StringFlag{Name: "ca_file", Usage: "PEM bundle to trust the server's certificate with", EnvVars: []string{"GOPRINT_CA_FILE"}},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:  "upload",
+						Usage: "Upload a gcode file and print its ID",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "file", Usage: "Path of the gcode file", Required: true},
+							&cli.StringFlag{Name: "folder", Usage: "ID of the folder to put it in"},
+							&cli.StringSliceFlag{Name: "tag", Usage: "Tag to add, can be given more than once"},
+							&cli.BoolFlag{Name: "new_version", Usage: "Add a version to the file with the same name in the folder"},
+							&cli.IntFlag{Name: "retries", Value: 5, Usage: "Failed chunks in a row before giving up"},
+						},
+						Action: func(c *cli.Context) error {
+							cl, err := apiClient(c)
+							if err != nil {
+								return terror.New(err, "")
+							}
+							gcode, err := cl.UploadFile(c.Context, c.String("file"), &client.UploadOptions{
+								FolderID:   c.String("folder"),
+								Tags:       c.StringSlice("tag"),
+								NewVersion: c.Bool("new_version"),
+								Retries:    c.Int("retries"),
+							})
+							if err != nil {
+								return terror.New(err, "")
+							}
+							fmt.Println(gcode.ID)
+							return nil
+						},
+					},
+					{
+						Name:  "load",
+						Usage: "Load an uploaded gcode file onto a printer",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "printer", Usage: "ID of the printer", Required: true},
+							&cli.StringFlag{Name: "file", Usage: "ID of the gcode file", Required: true},
+						},
+						Action: func(c *cli.Context) error {
+							cl, err := apiClient(c)
+							if err != nil {
+								return terror.New(err, "")
+							}
+							_, err = cl.LoadPrinterJob(c.Context, c.String("printer"), &client.JobRequest{FileID: c.String("file")})
+							return err
+						},
+					},
+					{
+						Name:  "start",
+						Usage: "Start printing the loaded file",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "printer", Usage: "ID of the printer", Required: true},
+						},
+						Action: func(c *cli.Context) error {
+							cl, err := apiClient(c)
+							if err != nil {
+								return terror.New(err, "")
+							}
+							return cl.SendPrinterCommand(c.Context, c.String("printer"), &client.CommandRequest{Command: "start"})
+						},
+					},
+					{
+						Name:  "status",
+						Usage: "Print a printer's status as JSON",
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "printer", Usage: "ID of the printer", Required: true},
+						},
+						Action: func(c *cli.Context) error {
+							cl, err := apiClient(c)
+							if err != nil {
+								return terror.New(err, "")
+							}
+							info, err := cl.GetPrinter(c.Context, c.String("printer"))
+							if err != nil {
+								return terror.New(err, "")
+							}
+							enc := json.NewEncoder(os.Stdout)
+							enc.SetIndent("", "  ")
+							return enc.Encode(info)
+						},
+					},
+				},
+			},
+			{
+				Name:  "ports",
+				Usage: "List serial ports that could have a printer on them",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "probe", Usage: "Talk to each candidate to find its baud rate and firmware"},
+					&cli.IntFlag{Name: "baud_rate", Usage: "Baud rate to try first when probing", EnvVars: []string{"BAUD_RATE"}, Value: 115200},
+				},
+				Action: func(c *cli.Context) error {
+					return portsCommand(c.Context, c.Bool("probe"), c.Int("baud_rate"))
 				},
 			},
 		},
@@ -200,53 +654,165 @@ func main() {
 
 }
 
-func agentCommand(ctx context.Context, baudRate int, serialDevice, websocketHost, websocketPort string) error {
+func agentCommand(ctx context.Context, printer *agent.PrinterConfig, serverConfig *agent.ServerConfig) error {
 
-	logW := log.With("service", "agent")
-	return retry.Do(
-		func() error {
-			mode := &serial.Mode{BaudRate: baudRate}
-			logW.Info("Connecting to serial device...")
-			serialconn, err := serial.Open(serialDevice, mode)
-			if err != nil {
-				return terror.New(err, "")
-			}
-			logW.Info("Connecting to websocket...")
-			wsconn, _, err := websocket.Dial(ctx, fmt.Sprintf("ws://%s:%s/api/websocket", websocketHost, websocketPort), nil)
-			if err != nil {
-				return terror.New(err, "")
+	logW := log.With("service", "agent", "printer", printer.Name)
+	// The agent looks after both the serial port and the websocket, reconnecting each on its own
+	a, err := agent.New(ctx, printer, serverConfig)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	logW.Infow("Starting agent...", "server_url", a.ServerURL)
+	return a.Run(ctx)
+}
+
+// superviseAgents runs an agent for each printer in the config, each restarted on its own
+func superviseAgents(ctx context.Context, cfg *agent.Config) error {
+	return agent.Supervise(ctx, cfg.Printers, func(ctx context.Context, printer *agent.PrinterConfig) error {
+		return agentCommand(ctx, printer, &cfg.ServerConfig)
+	})
+}
+
+func portsCommand(ctx context.Context, probe bool, baudRate int) error {
+	ports, err := agent.ListPorts()
+	if err != nil {
+		return terror.New(err, "")
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PORT\tVID:PID\tVENDOR\tPRODUCT\tSERIAL\tPRINTER")
+	for _, p := range ports {
+		usb := "-"
+		if p.USB {
+			usb = p.VID + ":" + p.PID
+		}
+		printer := "no"
+		if p.Candidate() {
+			printer = "maybe"
+		}
+		if probe && p.Candidate() {
+			printer = "no answer"
+			for _, baud := range agent.ProbeOrder(baudRate) {
+				firmware, err := agent.Probe(ctx, p.Name, baud)
+				if err != nil {
+					continue
+				}
+				printer = fmt.Sprintf("%s @ %d", firmware, baud)
+				break
 			}
-			defer wsconn.Close(websocket.StatusNormalClosure, "")
-			a := agent.New(
-				ctx,
-				serialconn,
-				wsconn,
-				websocketHost,
-				websocketPort,
-			)
-			logW.Info("Starting agent...")
-			a.Subscribe(ctx)
-			return nil
-		},
-		retry.Attempts(99),
-		retry.Delay(5*time.Second),
-		retry.DelayType(retry.FixedDelay),
-	)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Name, usb, p.Vendor, p.Product, p.SerialNumber, printer)
+	}
+	return tw.Flush()
 }
-func serveCommand(ctx context.Context, addr, serverHost string) error {
-	r := server.Routes(serverHost)
-	return http.ListenAndServe(addr, r)
+
+func auditExportCommand(c *cli.Context) error {
+	filter := &server.AuditFilter{Action: server.AuditAction(c.String("action")), Limit: c.Int("limit")}
+	var err error
+	if c.String("printer") != "" {
+		printer, err := server.FindPrinterByName(c.String("printer"))
+		if err != nil {
+			return terror.New(err, "")
+		}
+		filter.PrinterID = printer.ID
+	}
+	if c.String("user") != "" {
+		user, err := server.FindUserByEmail(c.String("user"))
+		if err != nil {
+			return terror.New(err, "")
+		}
+		filter.UserID = user.ID
+	}
+	if c.String("since") != "" {
+		filter.Since, err = time.Parse(time.RFC3339, c.String("since"))
+		if err != nil {
+			return terror.New(err, "")
+		}
+	}
+	if c.String("until") != "" {
+		filter.Until, err = time.Parse(time.RFC3339, c.String("until"))
+		if err != nil {
+			return terror.New(err, "")
+		}
+	}
+	events, err := server.ListAuditEvents(filter)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	if c.String("output") == "" {
+		return server.WriteAuditCSV(os.Stdout, events)
+	}
+	f, err := os.Create(c.String("output"))
+	if err != nil {
+		return terror.New(err, "")
+	}
+	defer f.Close()
+	err = server.WriteAuditCSV(f, events)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return f.Close()
+}
+
+// formatTime formats an optional time for the CLI's tables
+func formatTime(t null.Time) string {
+	if !t.Valid {
+		return "-"
+	}
+	return t.Time.Format(time.RFC3339)
+}
+
+// apiClient is the API client for the client command's flags
+func apiClient(c *cli.Context) (*client.Client, error) {
+	cl := client.New(c.String("server"), c.String("token"))
+	if c.String("ca_file") != "" {
+		httpClient, err := (&agent.ServerConfig{CAFile: c.String("ca_file")}).Client()
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		cl.HTTPClient = httpClient
+	}
+	return cl, nil
+}
+
+func serveCommand(ctx context.Context, addr, serverHost string, allowedOrigins []string, tlsOptions *server.TLSOptions) error {
+	r := server.Routes(serverHost, allowedOrigins, tlsOptions.ClientCerts())
+	if !tlsOptions.Enabled() {
+		return http.ListenAndServe(addr, r)
+	}
+	tlsOptions.Hosts = certificateHosts(serverHost)
+	tlsConfig, err := tlsOptions.Config()
+	if err != nil {
+		return terror.New(err, "")
+	}
+	srv := &http.Server{Addr: addr, Handler: r, TLSConfig: tlsConfig}
+	log.Infow("Serving HTTPS", "addr", addr, "client_ca", tlsOptions.ClientCAFile)
+	return srv.ListenAndServeTLS("", "")
+}
+
+// certificateHosts is who a self-signed certificate is made out to, the server's own host and this machine
+func certificateHosts(serverHost string) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	u, err := url.Parse(serverHost)
+	if err != nil || u.Hostname() == "" {
+		return hosts
+	}
+	for _, h := range hosts {
+		if h == u.Hostname() {
+			return hosts
+		}
+	}
+	return append([]string{u.Hostname()}, hosts...)
 }
-func devCommand(ctx context.Context, addr, serverHost string, baudRate int, serialDevice, websocketHost, websocketPort string) error {
+func devCommand(ctx context.Context, addr, serverHost string, printer *agent.PrinterConfig, serverConfig *agent.ServerConfig) error {
 	ctx, cancel := context.WithCancel(ctx)
 	g := &run.Group{}
 	g.Add(func() error {
-		return serveCommand(ctx, addr, serverHost)
+		return serveCommand(ctx, addr, serverHost, nil, &server.TLSOptions{})
 	}, func(error) {
 		cancel()
 	})
 	g.Add(func() error {
-		return agentCommand(ctx, baudRate, serialDevice, websocketHost, websocketPort)
+		return agentCommand(ctx, printer, serverConfig)
 	}, func(error) {
 		cancel()
 	})
@@ -31,10 +31,60 @@ const StatusUnknown AgentStatus = "UNKNOWN"
 // StatusIdle is the printer waiting
 const StatusIdle AgentStatus = "IDLE"
 
+// StatusPaused is the job held part way through
+const StatusPaused AgentStatus = "PAUSED"
+
+// StatusDisconnected is the agent running with its printer unplugged or switched off
+const StatusDisconnected AgentStatus = "DISCONNECTED"
+
+// StatusError is the firmware reporting a fatal error, latched until reset
+const StatusError AgentStatus = "ERROR"
+
+// StatusHalted is the printer stopped by an emergency stop, latched until reset
+const StatusHalted AgentStatus = "HALTED"
+
 // AgentInfo used for info panel on the front end
 type AgentInfo struct {
-	Busy   bool        `json:"busy"` // No print commands allowed
-	Status AgentStatus `json:"status"`
+	Busy        bool          `json:"busy"` // No print commands allowed
+	Status      AgentStatus   `json:"status"`
+	PauseReason PauseReason   `json:"pause_reason,omitempty"`
+	Firmware    *FirmwareInfo `json:"firmware,omitempty"`
+	Prompt      *Prompt       `json:"prompt,omitempty"` // Waiting for someone to answer
+
+	Temperatures map[string]Temperature `json:"temperatures,omitempty"` // Keyed by heater, T0, B etc
+	Error        string                 `json:"error,omitempty"`        // Firmware message behind StatusError
+}
+
+// Temperature of a heater in celsius
+type Temperature struct {
+	Actual float64 `json:"actual"`
+	Target float64 `json:"target"`
+}
+
+// PauseReason says why the printer is paused
+type PauseReason string
+
+// PauseReasonUser is a pause requested through the API
+const PauseReasonUser PauseReason = "USER"
+
+// PauseReasonPrinter is a pause requested from the printer's display
+const PauseReasonPrinter PauseReason = "PRINTER"
+
+// PauseReasonFilamentRunout is the runout sensor firing
+const PauseReasonFilamentRunout PauseReason = "FILAMENT_RUNOUT"
+
+// PauseReasonFilamentChange is an M600 waiting for the spool to be changed
+const PauseReasonFilamentChange PauseReason = "FILAMENT_CHANGE"
+
+// Prompt is a question from the printer's display, built from //action:prompt_ lines
+type Prompt struct {
+	Message string   `json:"message"`
+	Choices []string `json:"choices"`
+}
+
+// PayloadPromptResponse answers the printer's prompt with the index of the chosen button
+type PayloadPromptResponse struct {
+	Choice int `json:"choice"`
 }
 
 // MessageType shows the type of message
@@ -72,3 +122,18 @@ const CommandPause RequestType = "COMMAND_PAUSE"
 
 // CommandCancel will tell the printer to cancel
 const CommandCancel RequestType = "COMMAND_CANCEL"
+
+// CommandResume will tell the printer to carry on after a pause
+const CommandResume RequestType = "COMMAND_RESUME"
+
+// CommandPromptResponse answers a prompt showing on the printer
+const CommandPromptResponse RequestType = "COMMAND_PROMPT_RESPONSE"
+
+// CommandLoadFilament will tell the printer to load filament
+const CommandLoadFilament RequestType = "COMMAND_LOAD_FILAMENT"
+
+// CommandUnloadFilament will tell the printer to unload filament
+const CommandUnloadFilament RequestType = "COMMAND_UNLOAD_FILAMENT"
+
+// CommandEmergencyStop will tell the printer to halt immediately
+const CommandEmergencyStop RequestType = "COMMAND_EMERGENCY_STOP"
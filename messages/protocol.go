@@ -0,0 +1,208 @@
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gofrs/uuid"
+)
+
+// ProtocolVersion is the version of the agent-server protocol spoken by this build.
+// Version 2 agents authenticate with the token in their hello.
+const ProtocolVersion = 2
+
+// MinProtocolVersion is the oldest agent protocol version the server still accepts.
+// Version 1 agents have no token to send.
+const MinProtocolVersion = 2
+
+// InfoHello is the first message an agent sends after connecting
+const InfoHello RequestType = "HELLO"
+
+// InfoHelloAck is the server accepting the agent's hello
+const InfoHelloAck RequestType = "HELLO_ACK"
+
+// InfoHelloReject is the server refusing the agent, the connection is closed afterwards
+const InfoHelloReject RequestType = "HELLO_REJECT"
+
+// Capability is a feature the agent advertises during the handshake
+type Capability string
+
+// CapabilityLoad means the agent can download gcode files
+const CapabilityLoad Capability = "LOAD"
+
+// CapabilityPrint means the agent can stream gcode to the printer
+const CapabilityPrint Capability = "PRINT"
+
+// CapabilityAutoHome means the agent can run the auto home script
+const CapabilityAutoHome Capability = "AUTO_HOME"
+
+// CapabilityEmergencyStop means the agent handles emergency stops out of band
+const CapabilityEmergencyStop Capability = "EMERGENCY_STOP"
+
+// FirmwareInfo describes the firmware running on the printer, as reported by M115
+type FirmwareInfo struct {
+	Name            string          `json:"name"`
+	SourceCodeURL   string          `json:"source_code_url,omitempty"`
+	ProtocolVersion string          `json:"protocol_version,omitempty"`
+	MachineType     string          `json:"machine_type,omitempty"`
+	ExtruderCount   int             `json:"extruder_count"`
+	UUID            string          `json:"uuid,omitempty"`
+	Capabilities    map[string]bool `json:"capabilities"`
+}
+
+// Supports reports whether the firmware has the capability enabled
+func (f *FirmwareInfo) Supports(c FirmwareCapability) bool {
+	if f == nil {
+		return false
+	}
+	return f.Capabilities[string(c)]
+}
+
+// FirmwareCapability is a Cap: line reported by Marlin's M115
+type FirmwareCapability string
+
+// FirmwareCapAutoreportTemp means the firmware can push temperatures with M155
+const FirmwareCapAutoreportTemp FirmwareCapability = "AUTOREPORT_TEMP"
+
+// FirmwareCapEmergencyParser means M108, M112 and M410 are acted on as soon as they're received
+const FirmwareCapEmergencyParser FirmwareCapability = "EMERGENCY_PARSER"
+
+// FirmwareCapHostActionCommands means the firmware sends //action: lines
+const FirmwareCapHostActionCommands FirmwareCapability = "HOST_ACTION_COMMANDS"
+
+// FirmwareCapPromptSupport means the firmware sends //action:prompt_ lines
+const FirmwareCapPromptSupport FirmwareCapability = "PROMPT_SUPPORT"
+
+// FirmwareCapEEPROM means settings can be saved with M500
+const FirmwareCapEEPROM FirmwareCapability = "EEPROM"
+
+// FirmwareCapAutolevel means the printer has bed levelling with G29
+const FirmwareCapAutolevel FirmwareCapability = "AUTOLEVEL"
+
+// PayloadHello is sent by the agent to introduce itself
+type PayloadHello struct {
+	ProtocolVersion int          `json:"protocol_version"`
+	AgentVersion    string       `json:"agent_version"`
+	Name            string       `json:"name,omitempty"`  // Printer name from the agent's config, several can share one agent
+	Token           string       `json:"token,omitempty"` // Issued by the server for the printer, identifies the agent
+	Firmware        FirmwareInfo `json:"firmware"`
+	Capabilities    []Capability `json:"capabilities"`
+}
+
+// Supports reports whether the agent advertised the capability
+func (h *PayloadHello) Supports(c Capability) bool {
+	for _, v := range h.Capabilities {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+// PayloadHelloAck is the server accepting an agent
+type PayloadHelloAck struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	SessionID       string `json:"session_id"`
+}
+
+// PayloadHelloReject is the server refusing an agent
+type PayloadHelloReject struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	Reason          string `json:"reason"`
+}
+
+// ErrUnknownRequestType is returned when no payload is registered for a request type
+var ErrUnknownRequestType = errors.New("unknown request type")
+
+// ErrIncompatibleProtocol is returned when the agent speaks a protocol the server can't handle
+var ErrIncompatibleProtocol = errors.New("incompatible protocol version")
+
+// registry maps a request type to a constructor for its payload, nil means no payload
+var registry = map[RequestType]func() interface{}{}
+
+// Register associates a payload type with a request type
+func Register(t RequestType, factory func() interface{}) {
+	registry[t] = factory
+}
+
+func init() {
+	Register(InfoHello, func() interface{} { return &PayloadHello{} })
+	Register(InfoHelloAck, func() interface{} { return &PayloadHelloAck{} })
+	Register(InfoHelloReject, func() interface{} { return &PayloadHelloReject{} })
+	Register(InfoAgentStatus, func() interface{} { return &AgentInfo{} })
+
+	Register(CommandLoad, func() interface{} { return &PayloadLoadFile{} })
+	Register(CommandStart, nil)
+	Register(CommandPause, nil)
+	Register(CommandCancel, nil)
+	Register(CommandAutoHome, nil)
+	Register(CommandLevelBedTest, nil)
+	Register(CommandUnlockPrinter, nil)
+	Register(CommandEmergencyStop, nil)
+	Register(CommandResume, nil)
+	Register(CommandPromptResponse, func() interface{} { return &PayloadPromptResponse{} })
+	Register(CommandLoadFilament, nil)
+	Register(CommandUnloadFilament, nil)
+}
+
+// Encode builds a message for the request type, checking the payload matches the registry
+func Encode(messageType MessageType, requestType RequestType, payload interface{}) (*AsyncCommand, error) {
+	factory, ok := registry[requestType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRequestType, requestType)
+	}
+	msg := &AsyncCommand{
+		RequestID:   uuid.Must(uuid.NewV4()).String(),
+		MessageType: messageType,
+		RequestType: requestType,
+	}
+	if factory == nil {
+		if payload != nil {
+			return nil, fmt.Errorf("%s does not take a payload", requestType)
+		}
+		return msg, nil
+	}
+	if reflect.TypeOf(payload) != reflect.TypeOf(factory()) {
+		return nil, fmt.Errorf("%s expects payload %T, got %T", requestType, factory(), payload)
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	msg.Payload = b
+	return msg, nil
+}
+
+// Decode unmarshals the payload into the type registered for its request type.
+// Request types without a payload decode to nil.
+func Decode(msg *AsyncCommand) (interface{}, error) {
+	factory, ok := registry[msg.RequestType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRequestType, msg.RequestType)
+	}
+	if factory == nil {
+		return nil, nil
+	}
+	payload := factory()
+	if len(msg.Payload) == 0 {
+		return nil, fmt.Errorf("%s is missing its payload", msg.RequestType)
+	}
+	err := json.Unmarshal(msg.Payload, payload)
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// CheckCompatible returns an error if the server can't talk to the agent
+func CheckCompatible(hello *PayloadHello) error {
+	if hello.ProtocolVersion < MinProtocolVersion {
+		return fmt.Errorf("%w: agent speaks v%d, server accepts v%d to v%d, upgrade the agent", ErrIncompatibleProtocol, hello.ProtocolVersion, MinProtocolVersion, ProtocolVersion)
+	}
+	if hello.ProtocolVersion > ProtocolVersion {
+		return fmt.Errorf("%w: agent speaks v%d, server accepts v%d to v%d, upgrade the server", ErrIncompatibleProtocol, hello.ProtocolVersion, MinProtocolVersion, ProtocolVersion)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package messages
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckCompatible(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		wantErr string
+	}{
+		{name: "current", version: ProtocolVersion},
+		{name: "before tokens", version: 1, wantErr: "upgrade the agent"},
+		{name: "newer than the server", version: ProtocolVersion + 1, wantErr: "upgrade the server"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCompatible(&PayloadHello{ProtocolVersion: tt.version})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrIncompatibleProtocol) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+CREATE TABLE printers (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    name TEXT NOT NULL UNIQUE,
+    deleted_at timestamptz,
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE agent_tokens (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    printer_id UUID NOT NULL REFERENCES printers(id),
+    token_hash TEXT NOT NULL UNIQUE,
+    last_used_at timestamptz,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
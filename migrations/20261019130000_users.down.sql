@@ -0,0 +1,3 @@
+DROP TABLE api_tokens;
+DROP TABLE user_sessions;
+DROP TABLE users;
@@ -0,0 +1,27 @@
+CREATE TABLE users (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    email TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'viewer' CHECK (role IN ('viewer', 'operator', 'admin')),
+    deleted_at timestamptz,
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE user_sessions (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users(id),
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE api_tokens (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users(id),
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    last_used_at timestamptz,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,12 @@
+CREATE TABLE audit_events (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    user_id UUID REFERENCES users(id),
+    actor TEXT NOT NULL,
+    printer_id UUID REFERENCES printers(id),
+    action TEXT NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
+CREATE INDEX audit_events_printer_id_idx ON audit_events (printer_id, created_at);
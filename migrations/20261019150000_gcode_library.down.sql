@@ -0,0 +1,3 @@
+ALTER TABLE gcodes DROP COLUMN tags;
+ALTER TABLE gcodes DROP COLUMN folder_id;
+DROP TABLE folders;
@@ -0,0 +1,17 @@
+CREATE TABLE folders (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    name TEXT NOT NULL,
+    parent_id UUID REFERENCES folders(id),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- Names are unique within a folder, the root included
+CREATE UNIQUE INDEX folders_parent_id_name_idx ON folders (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), name);
+
+ALTER TABLE gcodes ADD COLUMN folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+ALTER TABLE gcodes ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX gcodes_folder_id_idx ON gcodes (folder_id);
+CREATE INDEX gcodes_tags_idx ON gcodes USING GIN (tags);
+CREATE INDEX gcodes_deleted_at_idx ON gcodes (deleted_at);
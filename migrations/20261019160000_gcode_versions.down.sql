@@ -0,0 +1,3 @@
+DROP TABLE gcode_versions;
+ALTER TABLE gcodes DROP COLUMN version;
+ALTER TABLE blobs DROP COLUMN sha256;
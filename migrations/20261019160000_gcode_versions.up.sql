@@ -0,0 +1,30 @@
+ALTER TABLE blobs ADD COLUMN sha256 TEXT;
+UPDATE blobs SET sha256 = encode(sha256(data), 'hex');
+ALTER TABLE blobs ALTER COLUMN sha256 SET NOT NULL;
+
+-- Files uploaded more than once each got a blob of their own, keep the oldest of each and point the files at it
+CREATE TEMPORARY TABLE blob_duplicates AS
+SELECT id, first_value(id) OVER (PARTITION BY sha256 ORDER BY created_at, id) AS keep_id FROM blobs;
+UPDATE gcodes SET blob_id = d.keep_id FROM blob_duplicates d WHERE gcodes.blob_id = d.id AND d.id <> d.keep_id;
+DELETE FROM blobs USING blob_duplicates d WHERE blobs.id = d.id AND d.id <> d.keep_id;
+DROP TABLE blob_duplicates;
+
+CREATE UNIQUE INDEX blobs_sha256_idx ON blobs (sha256);
+
+ALTER TABLE gcodes ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE gcode_versions (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    gcode_id UUID NOT NULL REFERENCES gcodes(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    blob_id UUID NOT NULL REFERENCES blobs(id),
+    uploaded_by TEXT NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    UNIQUE (gcode_id, version)
+);
+
+CREATE INDEX gcode_versions_blob_id_idx ON gcode_versions (blob_id);
+
+-- Every file so far is its own first version
+INSERT INTO gcode_versions (gcode_id, version, blob_id, created_at)
+SELECT id, 1, blob_id, created_at FROM gcodes;
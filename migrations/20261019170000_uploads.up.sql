@@ -0,0 +1,25 @@
+CREATE TABLE uploads (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    name TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    received_bytes BIGINT NOT NULL DEFAULT 0,
+    sha256 TEXT,
+    folder_id UUID REFERENCES folders(id) ON DELETE SET NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    new_version BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by TEXT NOT NULL,
+    expires_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX uploads_expires_at_idx ON uploads (expires_at);
+
+CREATE TABLE upload_chunks (
+    id uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid (),
+    upload_id UUID NOT NULL REFERENCES uploads(id) ON DELETE CASCADE,
+    start_offset BIGINT NOT NULL,
+    data bytea NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    UNIQUE (upload_id, start_offset)
+);
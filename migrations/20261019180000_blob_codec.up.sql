@@ -0,0 +1,2 @@
+-- How blobs.data is compressed. Blobs stored before this stay as they are.
+ALTER TABLE blobs ADD COLUMN codec TEXT NOT NULL DEFAULT 'identity';
@@ -0,0 +1,3 @@
+ALTER TABLE gcodes DROP COLUMN thumbnail_blob_id;
+ALTER TABLE gcodes DROP COLUMN metadata;
+ALTER TABLE gcodes DROP COLUMN format;
@@ -0,0 +1,4 @@
+-- What the file was uploaded as and what its slicer said about it. The gcode itself is always stored as text.
+ALTER TABLE gcodes ADD COLUMN format TEXT NOT NULL DEFAULT 'gcode';
+ALTER TABLE gcodes ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE gcodes ADD COLUMN thumbnail_blob_id UUID REFERENCES blobs(id) ON DELETE SET NULL;
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go-3dprint/db"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// AuditAction is what was done, as recorded in the audit log
+type AuditAction string
+
+// AuditLoad is a file being loaded onto a printer
+const AuditLoad AuditAction = "load"
+
+// AuditStart is a print being started
+const AuditStart AuditAction = "start"
+
+// AuditPause is a print being paused
+const AuditPause AuditAction = "pause"
+
+// AuditResume is a paused print being resumed
+const AuditResume AuditAction = "resume"
+
+// AuditCancel is a print being cancelled
+const AuditCancel AuditAction = "cancel"
+
+// AuditMacro is a canned sequence being run, such as homing or loading filament
+const AuditMacro AuditAction = "macro"
+
+// AuditPrompt is a prompt on the printer being answered
+const AuditPrompt AuditAction = "prompt"
+
+// AuditUnlock is a halted printer being reset
+const AuditUnlock AuditAction = "unlock"
+
+// AuditEmergencyStop is a printer being halted
+const AuditEmergencyStop AuditAction = "emergency_stop"
+
+// AuditFileUpload is a gcode file being uploaded
+const AuditFileUpload AuditAction = "file_upload"
+
+// AuditFileUpdate is a gcode file being renamed, moved to another folder or retagged
+const AuditFileUpdate AuditAction = "file_update"
+
+// AuditFileDelete is a gcode file being deleted, it can still be restored
+const AuditFileDelete AuditAction = "file_delete"
+
+// AuditFileRestore is a deleted gcode file being restored
+const AuditFileRestore AuditAction = "file_restore"
+
+// AuditFilePurge is deleted gcode files being removed for good
+const AuditFilePurge AuditAction = "file_purge"
+
+// DefaultAuditLimit is how many events the API returns when no limit is asked for
+const DefaultAuditLimit = 1000
+
+// Actor names whoever made the request in the audit log
+func (p *Principal) Actor() string {
+	switch {
+	case p == nil:
+		return "anonymous"
+	case p.User != nil:
+		return p.User.Email
+	case p.Printer != nil:
+		return "printer:" + p.Printer.Name
+	}
+	return "unknown"
+}
+
+// RecordAudit adds an event to the audit log. printerID is empty when no printer was involved.
+func RecordAudit(principal *Principal, printerID string, action AuditAction, params map[string]interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	event := &db.AuditEvent{
+		Actor:  principal.Actor(),
+		Action: string(action),
+		Params: types.JSON(b),
+	}
+	if principal != nil && principal.User != nil {
+		event.UserID = null.StringFrom(principal.User.ID)
+	}
+	if printerID != "" {
+		event.PrinterID = null.StringFrom(printerID)
+	}
+	err = event.InsertG(boil.Infer())
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+// audit records what the request did. The command has already gone out by the time this is called,
+// so failing to record it is logged rather than failing the request.
+func (c *Controller) audit(r *http.Request, printerID string, action AuditAction, params map[string]interface{}) {
+	principal := PrincipalFromContext(r.Context())
+	err := RecordAudit(principal, printerID, action, params)
+	if err != nil {
+		log.Errorw("Could not record audit event", "actor", principal.Actor(), "printer_id", printerID, "action", action, "err", err)
+	}
+}
+
+// AuditFilter narrows down the audit log, zero values match everything
+type AuditFilter struct {
+	PrinterID string
+	UserID    string
+	Action    AuditAction
+	Since     time.Time
+	Until     time.Time
+	Limit     int // Newest events first, 0 for all of them
+}
+
+// ParseAuditFilter reads a filter from query parameters, times are RFC 3339
+func ParseAuditFilter(q url.Values) (*AuditFilter, error) {
+	f := &AuditFilter{
+		PrinterID: q.Get("printer_id"),
+		UserID:    q.Get("user_id"),
+		Action:    AuditAction(q.Get("action")),
+		Limit:     DefaultAuditLimit,
+	}
+	var err error
+	if s := q.Get("since"); s != "" {
+		f.Since, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, terror.New(fmt.Errorf("since: %w", err), "")
+		}
+	}
+	if s := q.Get("until"); s != "" {
+		f.Until, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, terror.New(fmt.Errorf("until: %w", err), "")
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		f.Limit, err = strconv.Atoi(s)
+		if err != nil || f.Limit < 1 {
+			return nil, terror.New(fmt.Errorf("limit must be a positive number, got %s", s), "")
+		}
+	}
+	return f, nil
+}
+
+// ListAuditEvents returns the events matching the filter, newest first
+func ListAuditEvents(f *AuditFilter) ([]*db.AuditEvent, error) {
+	mods := []qm.QueryMod{qm.OrderBy(db.AuditEventColumns.CreatedAt + " DESC")}
+	if f.PrinterID != "" {
+		mods = append(mods, db.AuditEventWhere.PrinterID.EQ(null.StringFrom(f.PrinterID)))
+	}
+	if f.UserID != "" {
+		mods = append(mods, db.AuditEventWhere.UserID.EQ(null.StringFrom(f.UserID)))
+	}
+	if f.Action != "" {
+		mods = append(mods, db.AuditEventWhere.Action.EQ(string(f.Action)))
+	}
+	if !f.Since.IsZero() {
+		mods = append(mods, db.AuditEventWhere.CreatedAt.GTE(f.Since))
+	}
+	if !f.Until.IsZero() {
+		mods = append(mods, db.AuditEventWhere.CreatedAt.LT(f.Until))
+	}
+	if f.Limit > 0 {
+		mods = append(mods, qm.Limit(f.Limit))
+	}
+	events, err := db.AuditEvents(mods...).AllG()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return events, nil
+}
+
+// WriteAuditCSV writes the events as CSV with a header row
+func WriteAuditCSV(w io.Writer, events []*db.AuditEvent) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"time", "actor", "user_id", "printer_id", "action", "params"})
+	if err != nil {
+		return terror.New(err, "")
+	}
+	for _, e := range events {
+		err = cw.Write([]string{
+			e.CreatedAt.Format(time.RFC3339),
+			csvCell(e.Actor),
+			csvCell(e.UserID.String),
+			csvCell(e.PrinterID.String),
+			csvCell(e.Action),
+			csvCell(string(e.Params)),
+		})
+		if err != nil {
+			return terror.New(err, "")
+		}
+	}
+	cw.Flush()
+	err = cw.Error()
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+// csvCell stops spreadsheets running a cell as a formula. Usernames, printer names and file names
+// are chosen by users, so a cell starting with a formula character, tab or carriage return is
+// quoted to be read as text.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsAny(s[:1], "=+-@\t\r") {
+		return "'" + s
+	}
+	return s
+}
+
+func (c *Controller) auditList(w http.ResponseWriter, r *http.Request) (int, error) {
+	filter, err := ParseAuditFilter(r.URL.Query())
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	events, err := ListAuditEvents(filter)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+		err = WriteAuditCSV(w, events)
+		if err != nil {
+			return http.StatusInternalServerError, terror.New(err, "")
+		}
+		return http.StatusOK, nil
+	}
+	return writePayload(w, events)
+}
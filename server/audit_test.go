@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bytes"
+	"go-3dprint/db"
+	"testing"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+func TestWriteAuditCSV(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		event *db.AuditEvent
+		want  string
+	}{
+		{
+			name:  "plain",
+			event: &db.AuditEvent{Actor: "alice", UserID: null.StringFrom("u1"), Action: "LOGIN", Params: types.JSON(`{}`), CreatedAt: at},
+			want:  "2026-10-19T12:00:00Z,alice,u1,,LOGIN,{}\n",
+		},
+		{
+			name:  "formula",
+			event: &db.AuditEvent{Actor: "=HYPERLINK(\"x\")", Params: types.JSON(`{}`), CreatedAt: at},
+			want:  "2026-10-19T12:00:00Z,\"'=HYPERLINK(\"\"x\"\")\",,,,{}\n",
+		},
+		{
+			name:  "plus, minus and at",
+			event: &db.AuditEvent{Actor: "+1", UserID: null.StringFrom("-1"), PrinterID: null.StringFrom("@SUM(A1)"), CreatedAt: at},
+			want:  "2026-10-19T12:00:00Z,'+1,'-1,'@SUM(A1),,\n",
+		},
+		{
+			name:  "tab and carriage return",
+			event: &db.AuditEvent{Actor: "\tcmd", PrinterID: null.StringFrom("\r=1+1"), CreatedAt: at},
+			want:  "2026-10-19T12:00:00Z,'\tcmd,,\"'\r=1+1\",,\n",
+		},
+		{
+			name:  "formula character later on",
+			event: &db.AuditEvent{Actor: "a=b", CreatedAt: at},
+			want:  "2026-10-19T12:00:00Z,a=b,,,,\n",
+		},
+	}
+	header := "time,actor,user_id,printer_id,action,params\n"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := WriteAuditCSV(&b, []*db.AuditEvent{tt.event})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != header+tt.want {
+				t.Errorf("got %q, want %q", b.String(), header+tt.want)
+			}
+		})
+	}
+}
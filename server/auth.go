@@ -0,0 +1,427 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"go-3dprint/db"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Role decides what a user is allowed to do, each role can do everything the ones below it can
+type Role string
+
+// RoleViewer can read printer status and the file list
+const RoleViewer Role = "viewer"
+
+// RoleOperator can also run printers, start, pause, cancel and so on
+const RoleOperator Role = "operator"
+
+// RoleAdmin can also manage printers, files and users
+const RoleAdmin Role = "admin"
+
+// RoleAgent is an agent fetching the job sent to its printer. It's below every user role,
+// so it's turned away from every route except the content of that job.
+const RoleAgent Role = "agent"
+
+// roleLevels orders the roles
+var roleLevels = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// ParseRole checks a role given by a user
+func ParseRole(s string) (Role, error) {
+	if _, ok := roleLevels[Role(s)]; !ok {
+		return "", terror.New(errors.New("unknown role "+s+", use viewer, operator or admin"), "")
+	}
+	return Role(s), nil
+}
+
+// Allows reports whether the role includes the other
+func (r Role) Allows(other Role) bool {
+	return roleLevels[r] >= roleLevels[other]
+}
+
+// SessionCookie holds the session token of a logged in user
+const SessionCookie = "goprint_session"
+
+// SessionDuration is how long a login lasts
+const SessionDuration = 30 * 24 * time.Hour
+
+// ErrBadLogin is returned for a wrong email or password, without saying which
+var ErrBadLogin = errors.New("wrong email or password")
+
+// ErrUnauthenticated is returned when a request has no valid session or token
+var ErrUnauthenticated = errors.New("not logged in")
+
+// ErrForbidden is returned when the user's role doesn't allow the request
+var ErrForbidden = errors.New("not allowed")
+
+// Principal is whoever made the request, a user or an agent fetching files with its token
+type Principal struct {
+	User    *db.User
+	Printer *db.Printer
+	Role    Role
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns who made the request, nil if nobody is logged in
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// CreateUser adds a user with a bcrypt hashed password
+func CreateUser(email, password string, role Role) (*db.User, error) {
+	if email == "" || password == "" {
+		return nil, terror.New(errors.New("email and password are required"), "")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	user := &db.User{Email: strings.ToLower(email), PasswordHash: string(hash), Role: string(role)}
+	err = user.InsertG(boil.Infer())
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return user, nil
+}
+
+// FindUserByEmail looks up a user that hasn't been deleted
+func FindUserByEmail(email string) (*db.User, error) {
+	user, err := db.Users(db.UserWhere.Email.EQ(strings.ToLower(email)), db.UserWhere.DeletedAt.IsNull()).OneG()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return user, nil
+}
+
+// SetPassword replaces the user's password
+func SetPassword(user *db.User, password string) error {
+	if password == "" {
+		return terror.New(errors.New("password is required"), "")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	user.PasswordHash = string(hash)
+	_, err = user.UpdateG(boil.Whitelist(db.UserColumns.PasswordHash, db.UserColumns.UpdatedAt))
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+// Login checks the user's password and starts a session, returning its token
+func Login(email, password string) (string, *db.UserSession, error) {
+	user, err := FindUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrBadLogin
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if err != nil {
+		return "", nil, ErrBadLogin
+	}
+	token, err := newToken()
+	if err != nil {
+		return "", nil, err
+	}
+	session := &db.UserSession{UserID: user.ID, TokenHash: hashToken(token), ExpiresAt: time.Now().Add(SessionDuration)}
+	err = session.InsertG(boil.Infer())
+	if err != nil {
+		return "", nil, terror.New(err, "")
+	}
+	return token, session, nil
+}
+
+// IssueAPIToken creates a token for scripts to act as the user. It's returned once and only its hash kept.
+func IssueAPIToken(userID, name string) (string, *db.APIToken, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", nil, err
+	}
+	apiToken := &db.APIToken{UserID: userID, Name: name, TokenHash: hashToken(token)}
+	err = apiToken.InsertG(boil.Infer())
+	if err != nil {
+		return "", nil, terror.New(err, "")
+	}
+	return token, apiToken, nil
+}
+
+// authenticate works out who made the request from the session cookie or the bearer token.
+// Bearer tokens can be a user's API token or an agent's token.
+func authenticate(r *http.Request) (*Principal, error) {
+	if cookie, err := r.Cookie(SessionCookie); err == nil {
+		session, err := db.UserSessions(
+			db.UserSessionWhere.TokenHash.EQ(hashToken(cookie.Value)),
+			db.UserSessionWhere.ExpiresAt.GT(time.Now()),
+			qm.Load(db.UserSessionRels.User),
+		).OneG()
+		if err == nil {
+			return userPrincipal(session.R.User)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, terror.New(err, "")
+		}
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		return nil, ErrUnauthenticated
+	}
+	apiToken, err := db.APITokens(
+		db.APITokenWhere.TokenHash.EQ(hashToken(token)),
+		db.APITokenWhere.RevokedAt.IsNull(),
+		qm.Load(db.APITokenRels.User),
+	).OneG()
+	if err == nil {
+		apiToken.LastUsedAt = null.TimeFrom(time.Now())
+		_, err = apiToken.UpdateG(boil.Whitelist(db.APITokenColumns.LastUsedAt))
+		if err != nil {
+			log.Warnw("Could not record token use", "err", err)
+		}
+		return userPrincipal(apiToken.R.User)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, terror.New(err, "")
+	}
+	agentToken, err := AuthenticateAgent(token)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	return &Principal{Printer: agentToken.R.Printer, Role: RoleAgent}, nil
+}
+
+func userPrincipal(user *db.User) (*Principal, error) {
+	if user == nil || user.DeletedAt.Valid {
+		return nil, ErrUnauthenticated
+	}
+	return &Principal{User: user, Role: Role(user.Role)}, nil
+}
+
+// RequireRole only lets through requests from someone with at least the role
+func RequireRole(role Role) func(http.Handler) http.Handler {
+	return requirePrincipal(func(r *http.Request, principal *Principal) bool {
+		return principal.Role.Allows(role)
+	})
+}
+
+// requirePrincipal only lets through requests from someone allow accepts
+func requirePrincipal(allow func(r *http.Request, principal *Principal) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticate(r)
+			if errors.Is(err, ErrUnauthenticated) {
+				writeError(w, NewAPIError(http.StatusUnauthorized, CodeUnauthenticated, err.Error(), nil))
+				return
+			}
+			if err != nil {
+				terror.Echo(err)
+				writeError(w, errInternal(err))
+				return
+			}
+			if !allow(r, principal) {
+				writeError(w, NewAPIError(http.StatusForbidden, CodeForbidden, ErrForbidden.Error(), nil))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+		})
+	}
+}
+
+// requireViewerOrJobAgent lets through viewers, and agents downloading the version of the file
+// loaded onto their own printer
+func (c *Controller) requireViewerOrJobAgent() func(http.Handler) http.Handler {
+	return requirePrincipal(func(r *http.Request, principal *Principal) bool {
+		if principal.Role != RoleAgent {
+			return principal.Role.Allows(RoleViewer)
+		}
+		if principal.Printer == nil {
+			return false
+		}
+		job := c.job(principal.Printer.ID)
+		return job != nil &&
+			job.FileID == chi.URLParam(r, "id") &&
+			strconv.Itoa(job.Version) == chi.URLParam(r, "version")
+	})
+}
+
+// LoginRequest logs a user in
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// UserInfo is a user as shown by the API, without the password hash
+type UserInfo struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func userInfo(u *db.User) *UserInfo {
+	return &UserInfo{ID: u.ID, Email: u.Email, Role: Role(u.Role), CreatedAt: u.CreatedAt}
+}
+
+func (c *Controller) authLogin(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &LoginRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	token, session, err := Login(req.Email, req.Password)
+	if errors.Is(err, ErrBadLogin) {
+		return http.StatusUnauthorized, terror.New(err, "")
+	}
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	user, err := db.FindUserG(session.UserID)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return writePayload(w, userInfo(user))
+}
+
+func (c *Controller) authLogout(w http.ResponseWriter, r *http.Request) (int, error) {
+	cookie, err := r.Cookie(SessionCookie)
+	if err == nil {
+		_, err = db.UserSessions(db.UserSessionWhere.TokenHash.EQ(hashToken(cookie.Value))).DeleteAllG()
+		if err != nil {
+			return http.StatusInternalServerError, terror.New(err, "")
+		}
+	}
+	http.SetCookie(w, &http.Cookie{Name: SessionCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+	return http.StatusOK, nil
+}
+
+func (c *Controller) authMe(w http.ResponseWriter, r *http.Request) (int, error) {
+	principal := PrincipalFromContext(r.Context())
+	if principal.User == nil {
+		return http.StatusForbidden, terror.New(ErrForbidden, "")
+	}
+	return writePayload(w, userInfo(principal.User))
+}
+
+// UserRequest creates a user
+type UserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+func (c *Controller) usersList(w http.ResponseWriter, r *http.Request) (int, error) {
+	users, err := db.Users(db.UserWhere.DeletedAt.IsNull(), qm.OrderBy(db.UserColumns.Email)).AllG()
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	result := []*UserInfo{}
+	for _, u := range users {
+		result = append(result, userInfo(u))
+	}
+	return writePayload(w, result)
+}
+
+func (c *Controller) usersCreate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &UserRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	role, err := ParseRole(req.Role)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	user, err := CreateUser(req.Email, req.Password, role)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	return writePayload(w, userInfo(user))
+}
+
+// APITokenRequest issues an API token for the logged in user
+type APITokenRequest struct {
+	Name string `json:"name"`
+}
+
+func (c *Controller) apiTokensIssue(w http.ResponseWriter, r *http.Request) (int, error) {
+	principal := PrincipalFromContext(r.Context())
+	if principal.User == nil {
+		return http.StatusForbidden, terror.New(ErrForbidden, "")
+	}
+	req := &APITokenRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	token, apiToken, err := IssueAPIToken(principal.User.ID, req.Name)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return writePayload(w, &IssuedToken{ID: apiToken.ID, Token: token})
+}
+
+// RevokeAPIToken stops an API token being accepted
+func RevokeAPIToken(tokenID string) error {
+	apiToken, err := db.FindAPITokenG(tokenID)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	apiToken.RevokedAt = null.TimeFrom(time.Now())
+	_, err = apiToken.UpdateG(boil.Whitelist(db.APITokenColumns.RevokedAt))
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+func (c *Controller) apiTokensRevoke(w http.ResponseWriter, r *http.Request) (int, error) {
+	principal := PrincipalFromContext(r.Context())
+	req := &TokenRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	apiToken, err := db.FindAPITokenG(req.TokenID)
+	if err != nil {
+		return http.StatusNotFound, terror.New(err, "")
+	}
+	// Users revoke their own tokens, admins anyone's
+	if principal.User == nil || (apiToken.UserID != principal.User.ID && !principal.Role.Allows(RoleAdmin)) {
+		return http.StatusForbidden, terror.New(ErrForbidden, "")
+	}
+	err = RevokeAPIToken(req.TokenID)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return http.StatusOK, nil
+}
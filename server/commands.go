@@ -0,0 +1,233 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"go-3dprint/messages"
+	"net/http"
+	"time"
+
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+)
+
+// CommandName is a command sent to a printer through /printers/{id}/commands
+type CommandName string
+
+// CommandStart starts printing the loaded file
+const CommandStart CommandName = "start"
+
+// CommandPause pauses the print
+const CommandPause CommandName = "pause"
+
+// CommandResume resumes a paused print
+const CommandResume CommandName = "resume"
+
+// CommandCancel cancels the print
+const CommandCancel CommandName = "cancel"
+
+// CommandAutoHome homes all axes
+const CommandAutoHome CommandName = "autohome"
+
+// CommandFilamentLoad runs the printer's filament load macro
+const CommandFilamentLoad CommandName = "filament_load"
+
+// CommandFilamentUnload runs the printer's filament unload macro
+const CommandFilamentUnload CommandName = "filament_unload"
+
+// CommandPrompt answers the prompt showing on the printer with the request's choice
+const CommandPrompt CommandName = "prompt"
+
+// CommandUnlock resets a halted or errored printer
+const CommandUnlock CommandName = "unlock"
+
+// CommandEmergencyStop halts the printer, the agent handles it ahead of anything else
+const CommandEmergencyStop CommandName = "emergency_stop"
+
+// printerCommand is how a command is sent to the agent and recorded
+type printerCommand struct {
+	requestType messages.RequestType
+	action      AuditAction
+	idle        bool // Refused while the printer is printing
+}
+
+// printerCommands are the commands runCommand knows
+var printerCommands = map[CommandName]*printerCommand{
+	CommandStart:          {requestType: messages.CommandStart, action: AuditStart, idle: true},
+	CommandPause:          {requestType: messages.CommandPause, action: AuditPause},
+	CommandResume:         {requestType: messages.CommandResume, action: AuditResume},
+	CommandCancel:         {requestType: messages.CommandCancel, action: AuditCancel},
+	CommandAutoHome:       {requestType: messages.CommandAutoHome, action: AuditMacro, idle: true},
+	CommandFilamentLoad:   {requestType: messages.CommandLoadFilament, action: AuditMacro},
+	CommandFilamentUnload: {requestType: messages.CommandUnloadFilament, action: AuditMacro},
+	CommandPrompt:         {requestType: messages.CommandPromptResponse, action: AuditPrompt},
+	CommandUnlock:         {requestType: messages.CommandUnlockPrinter, action: AuditUnlock},
+	CommandEmergencyStop:  {requestType: messages.CommandEmergencyStop, action: AuditEmergencyStop},
+}
+
+// commandNames lists the commands for the OpenAPI document
+func commandNames() []string {
+	return []string{
+		string(CommandStart), string(CommandPause), string(CommandResume), string(CommandCancel),
+		string(CommandAutoHome), string(CommandFilamentLoad), string(CommandFilamentUnload),
+		string(CommandPrompt), string(CommandUnlock), string(CommandEmergencyStop),
+	}
+}
+
+// CommandRequest sends a command to a printer
+type CommandRequest struct {
+	Command CommandName `json:"command"`
+	Choice  *int        `json:"choice,omitempty"` // Only for prompt
+}
+
+// runCommand sends the command to the printer's agent and records it in the audit log
+func (c *Controller) runCommand(r *http.Request, printerID string, req *CommandRequest) error {
+	cmd, ok := printerCommands[req.Command]
+	if !ok {
+		return errBadRequest(fmt.Errorf("unknown command %q", req.Command))
+	}
+	find := c.commandSession
+	if cmd.idle {
+		find = c.idleSession
+	}
+	chs, err := find(printerID)
+	if err != nil {
+		return err
+	}
+
+	var payload interface{}
+	var params map[string]interface{}
+	if cmd.action == AuditMacro {
+		params = map[string]interface{}{"macro": cmd.requestType}
+	}
+	switch req.Command {
+	case CommandPrompt:
+		if req.Choice == nil {
+			return errBadRequest(errors.New("choice is required to answer a prompt"))
+		}
+		if chs.Info.Prompt == nil {
+			return NewAPIError(http.StatusConflict, CodeConflict, "printer is not showing a prompt", nil)
+		}
+		payload = &messages.PayloadPromptResponse{Choice: *req.Choice}
+		params = map[string]interface{}{"choice": *req.Choice}
+	case CommandEmergencyStop:
+		if !chs.Hello.Supports(messages.CapabilityEmergencyStop) {
+			return NewAPIError(http.StatusBadRequest, CodeUnsupported, "agent does not support emergency stop", nil)
+		}
+		log.Warnw("Emergency stop requested", "session_id", printerID, "actor", PrincipalFromContext(r.Context()).Actor())
+	}
+
+	msg, err := messages.Encode(messages.TypeCommand, cmd.requestType, payload)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	err = chs.send(r.Context(), msg)
+	if err != nil {
+		return err
+	}
+	if req.Command == CommandStart {
+		c.Lock()
+		if job, ok := c.Jobs[printerID]; ok {
+			job.StartedAt = null.TimeFrom(time.Now())
+		}
+		c.Unlock()
+	}
+	c.audit(r, chs.Printer.ID, cmd.action, params)
+	return nil
+}
+
+// Job is the file loaded onto a printer. The server only remembers the latest one for each printer.
+type Job struct {
+	PrinterID string               `json:"printer_id"`
+	FileID    string               `json:"file_id"`
+	FileName  string               `json:"file_name"`
+	Version   int                  `json:"version"`
+	LoadedBy  string               `json:"loaded_by"`
+	LoadedAt  time.Time            `json:"loaded_at"`
+	StartedAt null.Time            `json:"started_at"`
+	Status    messages.AgentStatus `json:"status"` // What the printer last reported, UNKNOWN while its agent is away
+}
+
+// JobRequest loads a file onto a printer
+type JobRequest struct {
+	FileID  string `json:"file_id"`
+	Version int    `json:"version,omitempty"` // An earlier version to print, 0 for the current one
+}
+
+// loadJob tells the printer's agent to download a version of the file, ready to be started.
+// Version 0 is the current one.
+func (c *Controller) loadJob(r *http.Request, printerID, fileID string, version int) (*Job, error) {
+	if fileID == "" {
+		return nil, errBadRequest(errors.New("file id not provided"))
+	}
+	chs, err := c.idleSession(printerID)
+	if err != nil {
+		return nil, err
+	}
+	gc, err := findGcode(fileID)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		version = gc.Version
+	}
+	_, err = findVersion(gc.ID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &messages.PayloadLoadFile{
+		ID:  gc.ID,
+		URL: fmt.Sprintf("%s/api/v2/gcodes/%s/versions/%d/content", c.Host, gc.ID, version),
+	}
+	msg, err := messages.Encode(messages.TypeCommand, messages.CommandLoad, payload)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+
+	// Recorded before the agent hears about it, the job is what lets the agent download the file
+	job := &Job{
+		PrinterID: printerID,
+		FileID:    gc.ID,
+		FileName:  gc.Name,
+		Version:   version,
+		LoadedBy:  PrincipalFromContext(r.Context()).Actor(),
+		LoadedAt:  time.Now(),
+	}
+	c.Lock()
+	previous := c.Jobs[printerID]
+	c.Jobs[printerID] = job
+	c.Unlock()
+	err = chs.send(r.Context(), msg)
+	if err != nil {
+		// The agent never heard about it, the printer still has whatever it had before
+		c.Lock()
+		if c.Jobs[printerID] == job {
+			if previous != nil {
+				c.Jobs[printerID] = previous
+			} else {
+				delete(c.Jobs, printerID)
+			}
+		}
+		c.Unlock()
+		return nil, err
+	}
+	c.audit(r, chs.Printer.ID, AuditLoad, map[string]interface{}{"file_id": gc.ID, "version": version})
+	return c.job(printerID), nil
+}
+
+// job is a copy of the printer's job with the status filled in, nil when nothing has been loaded
+func (c *Controller) job(printerID string) *Job {
+	c.Lock()
+	defer c.Unlock()
+	job, ok := c.Jobs[printerID]
+	if !ok {
+		return nil
+	}
+	result := *job
+	result.Status = messages.StatusUnknown
+	if chs, ok := c.Sessions[printerID]; ok {
+		result.Status = chs.Info.Status
+	}
+	return &result
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lib/pq"
+)
+
+// ErrorCode tells clients what went wrong without them having to read the message
+type ErrorCode string
+
+// CodeBadRequest is a request that couldn't be understood or was missing something
+const CodeBadRequest ErrorCode = "bad_request"
+
+// CodeUnauthenticated is a request without a valid session or token
+const CodeUnauthenticated ErrorCode = "unauthenticated"
+
+// CodeForbidden is a request the user's role doesn't allow
+const CodeForbidden ErrorCode = "forbidden"
+
+// CodeNotFound is a request for something that doesn't exist
+const CodeNotFound ErrorCode = "not_found"
+
+// CodeSessionNotFound is a command for a printer that isn't known
+const CodeSessionNotFound ErrorCode = "session_not_found"
+
+// CodeFileNotFound is a request for a gcode file that doesn't exist
+const CodeFileNotFound ErrorCode = "file_not_found"
+
+// CodePrinterBusy is a command that can't run while the printer is printing
+const CodePrinterBusy ErrorCode = "printer_busy"
+
+// CodeConflict is a request that clashes with something that already exists
+const CodeConflict ErrorCode = "conflict"
+
+// CodeAgentDisconnected is a command for a printer whose agent isn't connected to the server
+const CodeAgentDisconnected ErrorCode = "agent_disconnected"
+
+// CodeAgentUnresponsive is a command the printer's agent connection didn't take in time
+const CodeAgentUnresponsive ErrorCode = "agent_unresponsive"
+
+// CodePrinterDisconnected is a command for a printer that isn't connected to its agent
+const CodePrinterDisconnected ErrorCode = "printer_disconnected"
+
+// CodeUnsupported is a command the printer's agent can't do
+const CodeUnsupported ErrorCode = "unsupported"
+
+// CodeInternal is a failure on the server's side, the details are only logged
+const CodeInternal ErrorCode = "internal"
+
+// APIError is an error with the status and code to send the client
+type APIError struct {
+	Status  int
+	Code    ErrorCode
+	Message string
+	Err     error // What caused it, logged but not sent
+}
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// NewAPIError creates an error to send the client
+func NewAPIError(status int, code ErrorCode, message string, err error) *APIError {
+	return &APIError{Status: status, Code: code, Message: message, Err: err}
+}
+
+// errInternal hides a server side failure from the client
+func errInternal(err error) *APIError {
+	return NewAPIError(http.StatusInternalServerError, CodeInternal, "internal server error", err)
+}
+
+// errBadRequest passes on what was wrong with the request
+func errBadRequest(err error) *APIError {
+	return NewAPIError(http.StatusBadRequest, CodeBadRequest, err.Error(), err)
+}
+
+// ErrorBody describes an error to the client
+type ErrorBody struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// ErrorResponse is the envelope for errors, as APIResponse is for results
+type ErrorResponse struct {
+	Error *ErrorBody `json:"error"`
+}
+
+// codes is the code used for a status when the handler didn't give one
+var codes = map[int]ErrorCode{
+	http.StatusBadRequest:          CodeBadRequest,
+	http.StatusUnauthorized:        CodeUnauthenticated,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusConflict:            CodeConflict,
+	http.StatusServiceUnavailable:  CodeAgentDisconnected,
+	http.StatusInternalServerError: CodeInternal,
+}
+
+// toAPIError works out what to tell the client about an error returned with a status.
+// Rows that weren't found are 404s whatever the handler said, and 500s never give away the details.
+func toAPIError(status int, err error) *APIError {
+	apiErr := &APIError{}
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewAPIError(http.StatusNotFound, CodeNotFound, "not found", err)
+	}
+	pqErr := &pq.Error{}
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			return NewAPIError(http.StatusConflict, CodeConflict, "already exists", err)
+		case "foreign_key_violation":
+			return NewAPIError(http.StatusNotFound, CodeNotFound, "refers to something that doesn't exist", err)
+		case "invalid_text_representation":
+			return NewAPIError(http.StatusBadRequest, CodeBadRequest, "malformed id", err)
+		}
+		return errInternal(err)
+	}
+	if status < http.StatusBadRequest {
+		status = http.StatusInternalServerError
+	}
+	if status >= http.StatusInternalServerError {
+		return errInternal(err)
+	}
+	code, ok := codes[status]
+	if !ok {
+		code = CodeBadRequest
+	}
+	return NewAPIError(status, code, err.Error(), err)
+}
+
+// writeError sends the error in the JSON envelope
+func writeError(w http.ResponseWriter, apiErr *APIError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(apiErr.Status)
+	json.NewEncoder(w).Encode(&ErrorResponse{Error: &ErrorBody{Code: apiErr.Code, Message: apiErr.Message}})
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"go-3dprint/db"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/gofrs/uuid"
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// FolderRequest creates a folder, or renames or moves one. Fields left out of an update are kept.
+type FolderRequest struct {
+	Name     *string `json:"name,omitempty"`
+	ParentID *string `json:"parent_id,omitempty"` // Empty for the root
+}
+
+// findFolder looks up a folder, a 404 when there isn't one
+func findFolder(folderID string) (*db.Folder, error) {
+	notFound := NewAPIError(http.StatusNotFound, CodeNotFound, "folder not found", nil)
+	if _, err := uuid.FromString(folderID); err != nil {
+		return nil, notFound
+	}
+	folder, err := db.FindFolderG(folderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound
+	}
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return folder, nil
+}
+
+// setParent moves the folder under parentID, refusing to put a folder inside itself
+func setParent(folder *db.Folder, parentID string) error {
+	folder.ParentID = null.NewString(parentID, parentID != "")
+	for id := parentID; id != ""; {
+		if id == folder.ID {
+			return errBadRequest(errors.New("a folder can't be moved inside itself"))
+		}
+		parent, err := findFolder(id)
+		if err != nil {
+			return err
+		}
+		id = parent.ParentID.String
+	}
+	return nil
+}
+
+// CreateFolder makes a folder inside parentID, the root when it's empty
+func CreateFolder(name, parentID string) (*db.Folder, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errBadRequest(errors.New("name must not be empty"))
+	}
+	folder := &db.Folder{Name: name}
+	err := setParent(folder, parentID)
+	if err != nil {
+		return nil, err
+	}
+	err = folder.InsertG(boil.Infer())
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return folder, nil
+}
+
+// UpdateFolder renames or moves a folder
+func UpdateFolder(folder *db.Folder, req *FolderRequest) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return errBadRequest(errors.New("name must not be empty"))
+		}
+		folder.Name = name
+	}
+	if req.ParentID != nil {
+		err := setParent(folder, *req.ParentID)
+		if err != nil {
+			return err
+		}
+	}
+	folder.UpdatedAt = time.Now()
+	_, err := folder.UpdateG(boil.Whitelist(db.FolderColumns.Name, db.FolderColumns.ParentID, db.FolderColumns.UpdatedAt))
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+// DeleteFolder removes an empty folder. Deleted files still in it are moved to the root.
+func DeleteFolder(folder *db.Folder) error {
+	children, err := db.Folders(db.FolderWhere.ParentID.EQ(null.StringFrom(folder.ID))).CountG()
+	if err != nil {
+		return terror.New(err, "")
+	}
+	files, err := db.Gcodes(db.GcodeWhere.FolderID.EQ(null.StringFrom(folder.ID)), db.GcodeWhere.DeletedAt.IsNull()).CountG()
+	if err != nil {
+		return terror.New(err, "")
+	}
+	if children > 0 || files > 0 {
+		return NewAPIError(http.StatusConflict, CodeConflict, "folder is not empty", nil)
+	}
+	_, err = folder.DeleteG()
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+func (c *Controller) v2FoldersList(w http.ResponseWriter, r *http.Request) (int, error) {
+	folders, err := db.Folders(qm.OrderBy(db.FolderColumns.Name)).AllG()
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return writePayload(w, folders)
+}
+
+func (c *Controller) v2FoldersCreate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &FolderRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	if req.Name == nil {
+		return http.StatusBadRequest, terror.New(errors.New("name is required"), "")
+	}
+	parentID := ""
+	if req.ParentID != nil {
+		parentID = *req.ParentID
+	}
+	folder, err := CreateFolder(*req.Name, parentID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return writePayload(w, folder)
+}
+
+func (c *Controller) v2FolderUpdate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &FolderRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	folder, err := findFolder(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	err = UpdateFolder(folder, req)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return writePayload(w, folder)
+}
+
+func (c *Controller) v2FolderDelete(w http.ResponseWriter, r *http.Request) (int, error) {
+	folder, err := findFolder(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	err = DeleteFolder(folder)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-3dprint/db"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// FolderRoot is the folder_id filter for files that aren't in a folder
+const FolderRoot = "root"
+
+// TotalCountHeader tells clients how many results there are before limit and offset
+const TotalCountHeader = "X-Total-Count"
+
+// gcodeSorts are the orders the library can be listed in, prefix with - to reverse
+var gcodeSorts = map[string]string{
+	"name":       db.GcodeColumns.Name,
+	"created_at": db.GcodeColumns.CreatedAt,
+	"updated_at": db.GcodeColumns.UpdatedAt,
+}
+
+// gcodeSortNames lists the orders for the OpenAPI document
+func gcodeSortNames() []string {
+	result := []string{}
+	for name := range gcodeSorts {
+		result = append(result, name, "-"+name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GcodeFilter narrows down the library, zero values match everything that hasn't been deleted
+type GcodeFilter struct {
+	Search   string   // Part of the name, any case
+	FolderID string   // FolderRoot for files outside any folder
+	Tags     []string // Files with all of them
+	Deleted  bool     // Only deleted files instead of only live ones
+	Sort     string   // Key of gcodeSorts, - prefix for descending
+	Limit    int      // 0 for all of them
+	Offset   int
+}
+
+// ParseGcodeFilter reads a filter from query parameters
+func ParseGcodeFilter(q url.Values) (*GcodeFilter, error) {
+	f := &GcodeFilter{
+		Search:   q.Get("q"),
+		FolderID: q.Get("folder_id"),
+		Tags:     normaliseTags(strings.Split(q.Get("tags"), ",")),
+		Sort:     q.Get("sort"),
+	}
+	if f.Sort == "" {
+		f.Sort = "-created_at"
+	}
+	if _, ok := gcodeSorts[strings.TrimPrefix(f.Sort, "-")]; !ok {
+		return nil, terror.New(fmt.Errorf("can't sort by %s", f.Sort), "")
+	}
+	var err error
+	if s := q.Get("deleted"); s != "" {
+		f.Deleted, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, terror.New(fmt.Errorf("deleted: %w", err), "")
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		f.Limit, err = strconv.Atoi(s)
+		if err != nil || f.Limit < 1 {
+			return nil, terror.New(fmt.Errorf("limit must be a positive number, got %s", s), "")
+		}
+	}
+	if s := q.Get("offset"); s != "" {
+		f.Offset, err = strconv.Atoi(s)
+		if err != nil || f.Offset < 0 {
+			return nil, terror.New(fmt.Errorf("offset must not be negative, got %s", s), "")
+		}
+	}
+	return f, nil
+}
+
+// likeEscaper stops a search matching more than it says
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ListGcodes returns a page of the files matching the filter, and how many match altogether
+func ListGcodes(f *GcodeFilter) ([]*db.Gcode, int64, error) {
+	mods := []qm.QueryMod{db.GcodeWhere.DeletedAt.IsNull()}
+	if f.Deleted {
+		mods = []qm.QueryMod{db.GcodeWhere.DeletedAt.IsNotNull()}
+	}
+	if f.Search != "" {
+		mods = append(mods, qm.Where(db.GcodeColumns.Name+" ILIKE ?", "%"+likeEscaper.Replace(f.Search)+"%"))
+	}
+	switch f.FolderID {
+	case "":
+	case FolderRoot:
+		mods = append(mods, db.GcodeWhere.FolderID.IsNull())
+	default:
+		mods = append(mods, db.GcodeWhere.FolderID.EQ(null.StringFrom(f.FolderID)))
+	}
+	if len(f.Tags) > 0 {
+		mods = append(mods, qm.Where(db.GcodeColumns.Tags+" @> ?", types.StringArray(f.Tags)))
+	}
+	total, err := db.Gcodes(mods...).CountG()
+	if err != nil {
+		return nil, 0, terror.New(err, "")
+	}
+
+	order := gcodeSorts[strings.TrimPrefix(f.Sort, "-")]
+	if strings.HasPrefix(f.Sort, "-") {
+		order += " DESC"
+	}
+	// Break ties so pages don't overlap
+	mods = append(mods, qm.OrderBy(order+", "+db.GcodeColumns.ID))
+	if f.Limit > 0 {
+		mods = append(mods, qm.Limit(f.Limit))
+	}
+	if f.Offset > 0 {
+		mods = append(mods, qm.Offset(f.Offset))
+	}
+	gcodes, err := db.Gcodes(mods...).AllG()
+	if err != nil {
+		return nil, 0, terror.New(err, "")
+	}
+	return gcodes, total, nil
+}
+
+// normaliseTags lower cases and sorts tags, dropping blanks and repeats
+func normaliseTags(tags []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GcodeUpdate changes a file's details, fields left out are kept
+type GcodeUpdate struct {
+	Name     *string   `json:"name,omitempty"`
+	FolderID *string   `json:"folder_id,omitempty"` // Empty to move it out of its folder
+	Tags     *[]string `json:"tags,omitempty"`      // Replaces the file's tags
+}
+
+// UpdateGcode renames, moves or retags a file
+func UpdateGcode(gc *db.Gcode, update *GcodeUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return errBadRequest(errors.New("name must not be empty"))
+		}
+		gc.Name = name
+	}
+	if update.FolderID != nil {
+		gc.FolderID = null.NewString(*update.FolderID, *update.FolderID != "")
+		if gc.FolderID.Valid {
+			_, err := findFolder(gc.FolderID.String)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if update.Tags != nil {
+		gc.Tags = normaliseTags(*update.Tags)
+	}
+	gc.UpdatedAt = time.Now()
+	_, err := gc.UpdateG(boil.Whitelist(db.GcodeColumns.Name, db.GcodeColumns.FolderID, db.GcodeColumns.Tags, db.GcodeColumns.UpdatedAt))
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+// PurgeResult says how many deleted files were removed for good
+type PurgeResult struct {
+	Purged int `json:"purged"`
+}
+
+// PurgeGcodes removes files deleted before the time with all their versions, along with blobs no other file uses.
+// A zero time purges everything that has been deleted.
+func PurgeGcodes(ctx context.Context, before time.Time) (int, error) {
+	mods := []qm.QueryMod{db.GcodeWhere.DeletedAt.IsNotNull()}
+	if !before.IsZero() {
+		mods = append(mods, db.GcodeWhere.DeletedAt.LT(null.TimeFrom(before)))
+	}
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, terror.New(err, "")
+	}
+	defer tx.Rollback()
+
+	gcodes, err := db.Gcodes(mods...).All(tx)
+	if err != nil {
+		return 0, terror.New(err, "")
+	}
+	if len(gcodes) == 0 {
+		return 0, nil
+	}
+	ids := []interface{}{}
+	for _, gc := range gcodes {
+		ids = append(ids, gc.ID)
+	}
+	versions, err := db.GcodeVersions(
+		qm.Select(db.GcodeVersionColumns.BlobID),
+		qm.WhereIn(db.GcodeVersionColumns.GcodeID+" IN ?", ids...),
+	).All(tx)
+	if err != nil {
+		return 0, terror.New(err, "")
+	}
+	blobIDs := []interface{}{}
+	for _, v := range versions {
+		blobIDs = append(blobIDs, v.BlobID)
+	}
+	for _, gc := range gcodes {
+		if gc.ThumbnailBlobID.Valid {
+			blobIDs = append(blobIDs, gc.ThumbnailBlobID.String)
+		}
+	}
+	// Their versions go with them
+	_, err = db.Gcodes(qm.WhereIn(db.GcodeColumns.ID+" IN ?", ids...)).DeleteAll(tx)
+	if err != nil {
+		return 0, terror.New(err, "")
+	}
+	err = deleteUnusedBlobs(tx, blobIDs)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, terror.New(err, "")
+	}
+	return len(gcodes), nil
+}
+
+// deleteUnusedBlobs deletes the blobs no file, version or thumbnail uses any more
+func deleteUnusedBlobs(exec boil.Executor, blobIDs []interface{}) error {
+	if len(blobIDs) == 0 {
+		return nil
+	}
+	_, err := db.Blobs(
+		qm.WhereIn(db.BlobColumns.ID+" IN ?", blobIDs...),
+		qm.Where("NOT EXISTS (SELECT 1 FROM gcodes WHERE gcodes.blob_id = blobs.id OR gcodes.thumbnail_blob_id = blobs.id)"),
+		qm.Where("NOT EXISTS (SELECT 1 FROM gcode_versions WHERE gcode_versions.blob_id = blobs.id)"),
+	).DeleteAll(exec)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+func (c *Controller) gcodesList(w http.ResponseWriter, r *http.Request) (int, error) {
+	filter, err := ParseGcodeFilter(r.URL.Query())
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	result, total, err := ListGcodes(filter)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	w.Header().Set(TotalCountHeader, strconv.FormatInt(total, 10))
+	return writePayload(w, result)
+}
+
+func (c *Controller) v2GcodeUpdate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &GcodeUpdate{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	err = UpdateGcode(gc, req)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	c.audit(r, "", AuditFileUpdate, map[string]interface{}{"file_id": gc.ID, "name": gc.Name, "folder_id": gc.FolderID, "tags": gc.Tags})
+	return writePayload(w, gc)
+}
+
+func (c *Controller) v2GcodeDelete(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	gc.DeletedAt = null.TimeFrom(time.Now())
+	_, err = gc.UpdateG(boil.Whitelist(db.GcodeColumns.DeletedAt))
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	c.audit(r, "", AuditFileDelete, map[string]interface{}{"file_id": gc.ID, "name": gc.Name})
+	return http.StatusOK, nil
+}
+
+func (c *Controller) v2GcodeRestore(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, err := db.Gcodes(db.GcodeWhere.ID.EQ(chi.URLParam(r, "id")), db.GcodeWhere.DeletedAt.IsNotNull()).OneG()
+	if err != nil {
+		return http.StatusNotFound, terror.New(err, "")
+	}
+	gc.DeletedAt = null.Time{}
+	_, err = gc.UpdateG(boil.Whitelist(db.GcodeColumns.DeletedAt))
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	c.audit(r, "", AuditFileRestore, map[string]interface{}{"file_id": gc.ID, "name": gc.Name})
+	return writePayload(w, gc)
+}
+
+func (c *Controller) v2GcodesPurge(w http.ResponseWriter, r *http.Request) (int, error) {
+	var before time.Time
+	if s := r.URL.Query().Get("before"); s != "" {
+		var err error
+		before, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return http.StatusBadRequest, terror.New(fmt.Errorf("before: %w", err), "")
+		}
+	}
+	n, err := PurgeGcodes(r.Context(), before)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	params := map[string]interface{}{"purged": n}
+	if !before.IsZero() {
+		params["before"] = before
+	}
+	c.audit(r, "", AuditFilePurge, params)
+	return writePayload(w, &PurgeResult{Purged: n})
+}
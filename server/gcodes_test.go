@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGcodeFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *GcodeFilter
+		wantErr string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  &GcodeFilter{Tags: []string{}, Sort: "-created_at"},
+		},
+		{
+			name:  "everything",
+			query: "q=benchy&folder_id=root&tags=PLA,%20draft,,pla&deleted=true&sort=name&limit=20&offset=40",
+			want:  &GcodeFilter{Search: "benchy", FolderID: FolderRoot, Tags: []string{"draft", "pla"}, Deleted: true, Sort: "name", Limit: 20, Offset: 40},
+		},
+		{
+			name:  "descending",
+			query: "sort=-updated_at",
+			want:  &GcodeFilter{Tags: []string{}, Sort: "-updated_at"},
+		},
+		{name: "unknown sort", query: "sort=size", wantErr: "can't sort by size"},
+		{name: "bad deleted", query: "deleted=maybe", wantErr: "deleted"},
+		{name: "zero limit", query: "limit=0", wantErr: "limit must be a positive number"},
+		{name: "bad limit", query: "limit=ten", wantErr: "limit must be a positive number"},
+		{name: "negative offset", query: "offset=-1", wantErr: "offset must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseGcodeFilter(q)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,21 +1,43 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/ninja-software/terror"
 )
 
-// WithError handles http errors in one spot
+// WithError handles http errors in one spot. Handlers write their own response when they succeed,
+// errors are sent in the JSON envelope with the status and code they carry.
 func WithError(next func(w http.ResponseWriter, r *http.Request) (int, error)) func(w http.ResponseWriter, r *http.Request) {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		code, err := next(w, r)
-		if err != nil {
-			terror.Echo(err)
-			http.Error(w, err.Error(), code)
+		if err == nil {
+			return
 		}
-		w.WriteHeader(code)
+		terror.Echo(err)
+		writeError(w, toAPIError(code, err))
 	}
 	return fn
 
 }
+
+// Recover turns a panicking handler into a 500 rather than a dropped connection
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// Deliberately aborted, net/http deals with it quietly
+				panic(rec)
+			}
+			log.Errorw("Handler panicked", "method", r.Method, "path", r.URL.Path, "panic", rec, "stack", string(debug.Stack()))
+			writeError(w, errInternal(fmt.Errorf("panic: %v", rec)))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
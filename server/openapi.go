@@ -0,0 +1,484 @@
+package server
+
+import (
+	"encoding/json"
+	"go-3dprint/db"
+	"go-3dprint/gcodefile"
+	"go-3dprint/messages"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/volatiletech/sqlboiler/v4/types"
+)
+
+// APIVersion is the version of the REST API described by the OpenAPI document
+const APIVersion = "1.1.0"
+
+// OpenAPIDoc is an OpenAPI 3 document, as much of it as the API needs
+type OpenAPIDoc struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       *OpenAPIInfo                     `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"` // Keyed by path then lower case method
+	Components *Components                      `json:"components"`
+}
+
+// OpenAPIInfo describes the API
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the schemas operations refer to
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme is a way of authenticating
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Operation is one method on one path
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Role        Role                  `json:"x-role,omitempty"` // Least role allowed to call it, empty for anyone
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is what an operation accepts
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is what an operation answers with for a status
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON schema, as much of it as the API needs
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// schemaRefPrefix is where refs to component schemas point
+const schemaRefPrefix = "#/components/schemas/"
+
+// RefName is the component a ref points to, empty when the schema isn't a ref
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, schemaRefPrefix)
+}
+
+// endpoint describes a route for the OpenAPI document
+type endpoint struct {
+	Method   string
+	Path     string
+	ID       string
+	Summary  string
+	Tag      string
+	Role     Role
+	Params   []*Parameter
+	Body     interface{} // Value of the JSON request body's type, nil for none
+	Upload   bool        // Multipart form with the gcode in its file field
+	Chunk    bool        // Raw bytes in the body
+	Result   interface{} // Value of the type in the APIResponse payload, nil for an empty response
+	Download bool        // Responds with the file itself
+	Image    bool        // Responds with an image
+	Replaced string      // Operation ID of the /v2 route that replaces it
+}
+
+// requiredFields are the fields request bodies can't do without, keyed by type name
+var requiredFields = map[string][]string{
+	"LoginRequest":    {"email", "password"},
+	"UserRequest":     {"email", "password", "role"},
+	"APITokenRequest": {"name"},
+	"PrinterRequest":  {"name"},
+	"SessionRequest":  {"sessionId"},
+	"PromptRequest":   {"sessionId", "choice"},
+	"LoadCommand":     {"session_id", "file_id"},
+	"JobRequest":      {"file_id"},
+	"CommandRequest":  {"command"},
+	"UploadRequest":   {"name", "size_bytes"},
+}
+
+// fieldEnums are the values string fields accept, keyed by type name then field
+var fieldEnums = map[string]map[string][]string{
+	"CommandRequest": {"command": commandNames()},
+	"Gcode":          {"format": {gcodefile.FormatGcode, gcodefile.FormatBgcode, gcodefile.Format3MF}},
+}
+
+func query(name, typ, description string, required bool) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Required: required, Schema: &Schema{Type: typ}}
+}
+
+// endpoints lists every route in Routes
+func endpoints() []*endpoint {
+	sessionID := query("session_id", "string", "ID of the printer's session, the same as the printer's ID", true)
+	fileID := query("file_id", "string", "ID of the gcode file", true)
+	gcodeFilter := []*Parameter{
+		query("q", "string", "Only files with this in their name, any case", false),
+		query("folder_id", "string", "Only files in this folder, root for files outside any folder", false),
+		query("tags", "string", "Only files with all these comma separated tags", false),
+		query("deleted", "boolean", "Only deleted files instead of only live ones", false),
+		{Name: "sort", In: "query", Description: "Order, - prefix for descending", Schema: &Schema{Type: "string", Enum: gcodeSortNames()}},
+		query("limit", "integer", "At most this many files, the total is in the X-Total-Count header", false),
+		query("offset", "integer", "Skip this many files", false),
+	}
+	return []*endpoint{
+		{Method: http.MethodGet, Path: "/api/openapi.json", ID: "openAPI", Summary: "This document", Tag: "meta", Result: nil},
+		{Method: http.MethodGet, Path: "/api/websocket", ID: "agentWebsocket", Summary: "Websocket agents connect to, authenticated by the token in their hello", Tag: "agent"},
+		{Method: http.MethodPost, Path: "/api/auth/login", ID: "login", Summary: "Log in, setting the session cookie", Tag: "auth", Body: LoginRequest{}, Result: UserInfo{}},
+		{Method: http.MethodPost, Path: "/api/auth/logout", ID: "logout", Summary: "Log out, ending the session", Tag: "auth"},
+
+		{Method: http.MethodGet, Path: "/api/auth/me", ID: "me", Summary: "The logged in user", Tag: "auth", Role: RoleViewer, Result: UserInfo{}},
+		{Method: http.MethodPost, Path: "/api/auth/tokens/issue", ID: "issueAPIToken", Summary: "Issue an API token for the logged in user, shown only once", Tag: "auth", Role: RoleViewer, Body: APITokenRequest{}, Result: IssuedToken{}},
+		{Method: http.MethodPost, Path: "/api/auth/tokens/revoke", ID: "revokeAPIToken", Summary: "Revoke an API token, admins can revoke anyone's", Tag: "auth", Role: RoleViewer, Body: TokenRequest{}},
+		{Method: http.MethodGet, Path: "/api/printer/sessions", ID: "printerSessions", Summary: "IDs of the printers with a connected agent", Tag: "printer", Role: RoleViewer, Result: []string{}, Replaced: "listPrinters"},
+		{Method: http.MethodGet, Path: "/api/printer/info", ID: "printerInfo", Summary: "Status of a printer", Tag: "printer", Role: RoleViewer, Params: []*Parameter{sessionID}, Result: messages.AgentInfo{}, Replaced: "getPrinter"},
+		{Method: http.MethodGet, Path: "/api/gcodes", ID: "gcodesList", Summary: "Uploaded gcode files", Tag: "gcodes", Role: RoleViewer, Params: gcodeFilter, Result: []*db.Gcode{}, Replaced: "listGcodes"},
+		{Method: http.MethodGet, Path: "/api/gcodes/download", ID: "gcodesDownload", Summary: "Download a gcode file", Tag: "gcodes", Role: RoleViewer, Params: []*Parameter{fileID}, Download: true, Replaced: "getGcodeContent"},
+
+		{Method: http.MethodPost, Path: "/api/command/levelbedtest", ID: "commandLevelBedTest", Summary: "Level the bed, not implemented yet", Tag: "command", Role: RoleOperator, Body: SessionRequest{}},
+		{Method: http.MethodPost, Path: "/api/command/autohome", ID: "commandAutoHome", Summary: "Home all axes", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/unlock", ID: "commandUnlock", Summary: "Reset a halted or errored printer", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/emergency-stop", ID: "commandEmergencyStop", Summary: "Halt the printer immediately", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/load", ID: "commandLoad", Summary: "Load a gcode file onto the printer", Tag: "command", Role: RoleOperator, Body: LoadCommand{}, Replaced: "loadPrinterJob"},
+		{Method: http.MethodPost, Path: "/api/command/start", ID: "commandStart", Summary: "Start printing the loaded file", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/pause", ID: "commandPause", Summary: "Pause the print", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/cancel", ID: "commandCancel", Summary: "Cancel the print", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/resume", ID: "commandResume", Summary: "Resume a paused print", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/prompt", ID: "commandPrompt", Summary: "Answer the prompt showing on the printer", Tag: "command", Role: RoleOperator, Body: PromptRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/filament/load", ID: "commandLoadFilament", Summary: "Load filament", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+		{Method: http.MethodPost, Path: "/api/command/filament/unload", ID: "commandUnloadFilament", Summary: "Unload filament", Tag: "command", Role: RoleOperator, Body: SessionRequest{}, Replaced: "sendPrinterCommand"},
+
+		{Method: http.MethodGet, Path: "/api/printers", ID: "printersList", Summary: "Registered printers", Tag: "printers", Role: RoleAdmin, Result: []*db.Printer{}, Replaced: "listPrinters"},
+		{Method: http.MethodPost, Path: "/api/printers", ID: "printersCreate", Summary: "Register a printer", Tag: "printers", Role: RoleAdmin, Body: PrinterRequest{}, Result: db.Printer{}, Replaced: "createPrinter"},
+		{Method: http.MethodGet, Path: "/api/printers/tokens", ID: "tokensList", Summary: "Agent tokens issued for a printer", Tag: "printers", Role: RoleAdmin, Params: []*Parameter{query("printer_id", "string", "ID of the printer", true)}, Result: []*TokenInfo{}},
+		{Method: http.MethodPost, Path: "/api/printers/tokens/issue", ID: "tokensIssue", Summary: "Issue an agent token for a printer, shown only once", Tag: "printers", Role: RoleAdmin, Body: TokenRequest{}, Result: IssuedToken{}},
+		{Method: http.MethodPost, Path: "/api/printers/tokens/revoke", ID: "tokensRevoke", Summary: "Revoke an agent token, dropping agents using it", Tag: "printers", Role: RoleAdmin, Body: TokenRequest{}},
+		{Method: http.MethodGet, Path: "/api/audit", ID: "auditList", Summary: "Audit log, newest first. format=csv returns CSV instead.", Tag: "audit", Role: RoleAdmin, Params: []*Parameter{
+			query("printer_id", "string", "Only events for this printer", false),
+			query("user_id", "string", "Only events by this user", false),
+			query("action", "string", "Only events with this action", false),
+			{Name: "since", In: "query", Description: "Only events at or after this time", Schema: &Schema{Type: "string", Format: "date-time"}},
+			{Name: "until", In: "query", Description: "Only events before this time", Schema: &Schema{Type: "string", Format: "date-time"}},
+			query("limit", "integer", "At most this many events", false),
+			{Name: "format", In: "query", Description: "csv for CSV", Schema: &Schema{Type: "string", Enum: []string{"json", "csv"}}},
+		}, Result: []*db.AuditEvent{}},
+		{Method: http.MethodGet, Path: "/api/users", ID: "usersList", Summary: "Users", Tag: "users", Role: RoleAdmin, Result: []*UserInfo{}},
+		{Method: http.MethodPost, Path: "/api/users", ID: "usersCreate", Summary: "Add a user", Tag: "users", Role: RoleAdmin, Body: UserRequest{}, Result: UserInfo{}},
+		{Method: http.MethodPost, Path: "/api/gcodes/upload", ID: "gcodesUpload", Summary: "Upload a gcode file", Tag: "gcodes", Role: RoleAdmin, Upload: true, Result: db.Gcode{}, Replaced: "uploadGcode"},
+
+		{Method: http.MethodGet, Path: "/api/v2/printers", ID: "listPrinters", Summary: "Registered printers with their status", Tag: "v2", Role: RoleViewer, Result: []*PrinterResource{}},
+		{Method: http.MethodPost, Path: "/api/v2/printers", ID: "createPrinter", Summary: "Register a printer", Tag: "v2", Role: RoleAdmin, Body: PrinterRequest{}, Result: db.Printer{}},
+		{Method: http.MethodGet, Path: "/api/v2/printers/{id}", ID: "getPrinter", Summary: "A printer with its status", Tag: "v2", Role: RoleViewer, Result: PrinterResource{}},
+		{Method: http.MethodGet, Path: "/api/v2/printers/{id}/jobs", ID: "listPrinterJobs", Summary: "The file loaded onto the printer, if any", Tag: "v2", Role: RoleViewer, Result: []*Job{}},
+		{Method: http.MethodPost, Path: "/api/v2/printers/{id}/jobs", ID: "loadPrinterJob", Summary: "Load a gcode file onto the printer", Tag: "v2", Role: RoleOperator, Body: JobRequest{}, Result: Job{}},
+		{Method: http.MethodPost, Path: "/api/v2/printers/{id}/commands", ID: "sendPrinterCommand", Summary: "Send the printer a command", Tag: "v2", Role: RoleOperator, Body: CommandRequest{}},
+		{Method: http.MethodGet, Path: "/api/v2/gcodes", ID: "listGcodes", Summary: "Uploaded gcode files", Tag: "v2", Role: RoleViewer, Params: gcodeFilter, Result: []*db.Gcode{}},
+		{Method: http.MethodPost, Path: "/api/v2/gcodes", ID: "uploadGcode", Summary: "Upload a gcode file", Tag: "v2", Role: RoleAdmin, Upload: true, Result: db.Gcode{}},
+		{Method: http.MethodGet, Path: "/api/v2/gcodes/{id}", ID: "getGcode", Summary: "A gcode file's details", Tag: "v2", Role: RoleViewer, Result: db.Gcode{}},
+		{Method: http.MethodGet, Path: "/api/v2/gcodes/{id}/content", ID: "getGcodeContent", Summary: "Download a gcode file", Tag: "v2", Role: RoleViewer, Download: true},
+		{Method: http.MethodGet, Path: "/api/v2/gcodes/{id}/thumbnail", ID: "getGcodeThumbnail", Summary: "The largest thumbnail the slicer put in the file, 404 when it had none", Tag: "v2", Role: RoleViewer, Image: true},
+		{Method: http.MethodGet, Path: "/api/v2/gcodes/{id}/versions", ID: "listGcodeVersions", Summary: "A gcode file's versions, newest first", Tag: "v2", Role: RoleViewer, Result: []*VersionInfo{}},
+		{Method: http.MethodGet, Path: "/api/v2/gcodes/{id}/versions/{version}/content", ID: "getGcodeVersionContent", Summary: "Download a version of a gcode file, agents can download the version loaded onto their printer", Tag: "v2", Role: RoleViewer, Download: true},
+		{Method: http.MethodGet, Path: "/api/v2/gcodes/{id}/diff", ID: "diffGcodeVersions", Summary: "How the slicer settings changed between two versions", Tag: "v2", Role: RoleViewer, Params: []*Parameter{
+			query("from", "integer", "Older version, defaults to the one before to", false),
+			query("to", "integer", "Newer version, defaults to the current one", false),
+		}, Result: SettingsDiff{}},
+		{Method: http.MethodPatch, Path: "/api/v2/gcodes/{id}", ID: "updateGcode", Summary: "Rename a gcode file, move it to another folder or change its tags", Tag: "v2", Role: RoleAdmin, Body: GcodeUpdate{}, Result: db.Gcode{}},
+		{Method: http.MethodDelete, Path: "/api/v2/gcodes/{id}", ID: "deleteGcode", Summary: "Delete a gcode file, it can be restored until it's purged", Tag: "v2", Role: RoleAdmin},
+		{Method: http.MethodPost, Path: "/api/v2/gcodes/{id}/restore", ID: "restoreGcode", Summary: "Restore a deleted gcode file", Tag: "v2", Role: RoleAdmin, Result: db.Gcode{}},
+		{Method: http.MethodPost, Path: "/api/v2/gcodes/purge", ID: "purgeGcodes", Summary: "Remove deleted gcode files for good", Tag: "v2", Role: RoleAdmin, Params: []*Parameter{
+			{Name: "before", In: "query", Description: "Only files deleted before this time", Schema: &Schema{Type: "string", Format: "date-time"}},
+		}, Result: PurgeResult{}},
+		{Method: http.MethodPost, Path: "/api/v2/uploads", ID: "createUpload", Summary: "Start a resumable upload, send the file in chunks then complete it", Tag: "v2", Role: RoleAdmin, Body: UploadRequest{}, Result: db.Upload{}},
+		{Method: http.MethodGet, Path: "/api/v2/uploads/{id}", ID: "getUpload", Summary: "Where a resumable upload is up to, received_bytes is where the next chunk starts", Tag: "v2", Role: RoleAdmin, Result: db.Upload{}},
+		{Method: http.MethodPatch, Path: "/api/v2/uploads/{id}", ID: "uploadChunk", Summary: "Send the next chunk of a resumable upload", Tag: "v2", Role: RoleAdmin, Params: []*Parameter{
+			{Name: UploadOffsetHeader, In: "header", Description: "Where the chunk starts, it must be where the upload is up to", Required: true, Schema: &Schema{Type: "integer"}},
+		}, Chunk: true, Result: db.Upload{}},
+		{Method: http.MethodPost, Path: "/api/v2/uploads/{id}/complete", ID: "completeUpload", Summary: "Check a resumable upload against its size and hash and add it to the library", Tag: "v2", Role: RoleAdmin, Result: db.Gcode{}},
+		{Method: http.MethodDelete, Path: "/api/v2/uploads/{id}", ID: "deleteUpload", Summary: "Abandon a resumable upload", Tag: "v2", Role: RoleAdmin},
+		{Method: http.MethodGet, Path: "/api/v2/folders", ID: "listFolders", Summary: "Every folder, build the tree from their parent_id", Tag: "v2", Role: RoleViewer, Result: []*db.Folder{}},
+		{Method: http.MethodPost, Path: "/api/v2/folders", ID: "createFolder", Summary: "Create a folder", Tag: "v2", Role: RoleAdmin, Body: FolderRequest{}, Result: db.Folder{}},
+		{Method: http.MethodPatch, Path: "/api/v2/folders/{id}", ID: "updateFolder", Summary: "Rename a folder or move it into another", Tag: "v2", Role: RoleAdmin, Body: FolderRequest{}, Result: db.Folder{}},
+		{Method: http.MethodDelete, Path: "/api/v2/folders/{id}", ID: "deleteFolder", Summary: "Delete an empty folder", Tag: "v2", Role: RoleAdmin},
+	}
+}
+
+// NewOpenAPI builds the OpenAPI document for the API
+func NewOpenAPI() *OpenAPIDoc {
+	doc := &OpenAPIDoc{
+		OpenAPI: "3.0.3",
+		Info:    &OpenAPIInfo{Title: "go-3dprint", Version: APIVersion},
+		Paths:   map[string]map[string]*Operation{},
+		Components: &Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"cookie": {Type: "apiKey", In: "cookie", Name: SessionCookie},
+				"bearer": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+	errorResponse := &Response{
+		Description: "Error",
+		Content:     map[string]*MediaType{"application/json": {Schema: doc.schema(reflect.TypeOf(ErrorResponse{}))}},
+	}
+
+	for _, e := range endpoints() {
+		op := &Operation{
+			OperationID: e.ID,
+			Summary:     e.Summary,
+			Tags:        []string{e.Tag},
+			Parameters:  e.Params,
+			Responses:   map[string]*Response{"default": errorResponse},
+			Security:    []map[string][]string{},
+			Role:        e.Role,
+		}
+		if e.Replaced != "" {
+			op.Deprecated = true
+			op.Summary += ", replaced by " + e.Replaced
+		}
+		if e.Role != "" {
+			op.Security = []map[string][]string{{"cookie": {}}, {"bearer": {}}}
+		}
+		for _, name := range pathParams(e.Path) {
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+		if e.Body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: doc.schema(reflect.TypeOf(e.Body))}},
+			}
+		}
+		if e.Chunk {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}}},
+			}
+		}
+		if e.Upload {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content: map[string]*MediaType{"multipart/form-data": {Schema: &Schema{
+					Type: "object",
+					Properties: map[string]*Schema{
+						"file":        {Type: "string", Format: "binary", Description: "The gcode, gzipped gcode, binary gcode or a sliced 3MF"},
+						"folder_id":   {Type: "string", Description: "Folder to put the file in"},
+						"tags":        {Type: "string", Description: "Comma separated tags"},
+						"new_version": {Type: "string", Enum: []string{"true", "false"}, Description: "true to add a version to the file with the same name in the same folder"},
+					},
+					Required: []string{"file"},
+				}}},
+			}
+		}
+		switch {
+		case e.Image:
+			op.Responses["200"] = &Response{
+				Description: "The image",
+				Content: map[string]*MediaType{
+					"image/png":  {Schema: &Schema{Type: "string", Format: "binary"}},
+					"image/jpeg": {Schema: &Schema{Type: "string", Format: "binary"}},
+				},
+			}
+		case e.Download:
+			op.Responses["200"] = &Response{
+				Description: "The file, with Content-Encoding zstd or gzip when Accept-Encoding allows it",
+				Content:     map[string]*MediaType{"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}}},
+			}
+		case e.Result != nil:
+			op.Responses["200"] = &Response{
+				Description: "OK",
+				Content: map[string]*MediaType{"application/json": {Schema: &Schema{
+					Type:       "object",
+					Properties: map[string]*Schema{"payload": doc.schema(reflect.TypeOf(e.Result))},
+				}}},
+			}
+		default:
+			op.Responses["200"] = &Response{Description: "OK"}
+		}
+		if doc.Paths[e.Path] == nil {
+			doc.Paths[e.Path] = map[string]*Operation{}
+		}
+		doc.Paths[e.Path][strings.ToLower(e.Method)] = op
+	}
+	return doc
+}
+
+// pathParams are the names of the {params} in a path
+func pathParams(path string) []string {
+	result := []string{}
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			result = append(result, strings.Trim(seg, "{}"))
+		}
+	}
+	return result
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawType     = reflect.TypeOf(json.RawMessage{})
+	jsonType    = reflect.TypeOf(types.JSON{})
+	nullPkgPath = "github.com/volatiletech/null/v8"
+)
+
+// schema describes a Go type the way encoding/json writes it. Named structs become components.
+func (doc *OpenAPIDoc) schema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawType || t == jsonType:
+		return &Schema{}
+	case t.PkgPath() == nullPkgPath:
+		return nullSchema(t)
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := doc.schema(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: doc.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: doc.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return doc.structSchema(t)
+		}
+		if _, ok := doc.Components.Schemas[t.Name()]; !ok {
+			// Placeholder first, in case the struct refers to itself
+			doc.Components.Schemas[t.Name()] = &Schema{}
+			s := doc.structSchema(t)
+			s.Required = requiredFields[t.Name()]
+			for field, values := range fieldEnums[t.Name()] {
+				s.Properties[field].Enum = values
+			}
+			doc.Components.Schemas[t.Name()] = s
+		}
+		return &Schema{Ref: schemaRefPrefix + t.Name()}
+	}
+	return &Schema{}
+}
+
+// structSchema lists the fields encoding/json would write
+func (doc *OpenAPIDoc) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded := doc.structSchema(f.Type)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = doc.schema(f.Type)
+	}
+	return s
+}
+
+// nullSchema describes the nullable types from volatiletech/null
+func nullSchema(t reflect.Type) *Schema {
+	s := &Schema{Nullable: true}
+	switch {
+	case t.Name() == "Time":
+		s.Type, s.Format = "string", "date-time"
+	case t.Name() == "Bool":
+		s.Type = "boolean"
+	case strings.HasPrefix(t.Name(), "Int") || strings.HasPrefix(t.Name(), "Uint"):
+		s.Type = "integer"
+	case strings.HasPrefix(t.Name(), "Float"):
+		s.Type = "number"
+	case t.Name() == "JSON":
+	case t.Name() == "Bytes":
+		s.Type, s.Format = "string", "byte"
+	default:
+		s.Type = "string"
+	}
+	return s
+}
+
+// checkRoutes warns about routes missing from the document, so it doesn't fall behind
+func checkRoutes(r chi.Router, doc *OpenAPIDoc) {
+	missing := []string{}
+	chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if _, ok := doc.Paths[route][strings.ToLower(method)]; !ok {
+			missing = append(missing, method+" "+route)
+		}
+		return nil
+	})
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		log.Warnw("Routes missing from the OpenAPI document", "routes", missing)
+	}
+}
+
+func (c *Controller) openAPI(w http.ResponseWriter, r *http.Request) (int, error) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(c.spec)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
@@ -2,14 +2,18 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"go-3dprint/codec"
 	"go-3dprint/db"
 	"go-3dprint/messages"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,7 +23,6 @@ import (
 	"github.com/go-chi/chi/middleware"
 	"github.com/gofrs/uuid"
 	"github.com/ninja-software/terror"
-	"github.com/volatiletech/sqlboiler/v4/boil"
 	"go.uber.org/zap"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wsjson"
@@ -27,6 +30,9 @@ import (
 
 var log *zap.SugaredLogger
 
+// AgentSendTimeout is how long a command waits for the agent's connection to take it
+const AgentSendTimeout = 10 * time.Second
+
 func init() {
 	logger, _ := zap.NewDevelopment()
 	defer logger.Sync()
@@ -38,73 +44,193 @@ type Controller struct {
 	Host       string
 	Aggregator chan *messages.AsyncCommand
 	Sessions   map[string]*Session
+	Jobs       map[string]*Job // Latest file loaded onto each printer, keyed by printer ID
+	spec       *OpenAPIDoc     // Served to clients and used to validate their requests
 	*sync.Mutex
 }
 
-// Session holds two channels for bidirectional communication
+// Session holds two channels for bidirectional communication.
+// Sessions are keyed by the printer's id, so a printer keeps its session across reconnects.
 type Session struct {
-	Info   *messages.AgentInfo
-	Agent  chan *messages.AsyncCommand
-	Server chan *messages.AsyncCommand
+	Info    *messages.AgentInfo
+	Hello   *messages.PayloadHello // What the agent told us during the handshake
+	Printer *db.Printer            // The printer the agent's token belongs to
+	TokenID string                 // The token the agent connected with
+	Agent   chan *messages.AsyncCommand
+	Server  chan *messages.AsyncCommand
+	close   context.CancelFunc // Ends the agent's connection
+	done    <-chan struct{}    // Closed once the agent's connection has ended
 }
 
-// Routes for the master server
-func Routes(serverHost string) chi.Router {
+// send hands msg to the agent's connection. It gives up if the agent goes away, the request is
+// abandoned or the connection is stuck, rather than leave the request waiting for good.
+func (s *Session) send(ctx context.Context, msg *messages.AsyncCommand) error {
+	timer := time.NewTimer(AgentSendTimeout)
+	defer timer.Stop()
+	select {
+	case s.Agent <- msg:
+		return nil
+	case <-s.done:
+		return NewAPIError(http.StatusServiceUnavailable, CodeAgentDisconnected, "printer's agent disconnected", nil)
+	case <-ctx.Done():
+		return NewAPIError(http.StatusServiceUnavailable, CodeAgentUnresponsive, "request ended before the printer's agent took the command", nil)
+	case <-timer.C:
+		return NewAPIError(http.StatusServiceUnavailable, CodeAgentUnresponsive, "printer's agent didn't take the command in time", nil)
+	}
+}
+
+// Routes for the master server. Browsers may call the API from allowedOrigins,
+// or only from serverHost when none are given. With agentCerts, agents must connect
+// with a client certificate.
+func Routes(serverHost string, allowedOrigins []string, agentCerts bool) chi.Router {
 	c := &Controller{
 		Host:     serverHost,
 		Sessions: map[string]*Session{},
+		Jobs:     map[string]*Job{},
+		spec:     NewOpenAPI(),
 		Mutex:    &sync.Mutex{},
 	}
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
+	r.Use(Recover)
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: false,
+		AllowedOrigins:   corsOrigins(serverHost, allowedOrigins),
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", UploadOffsetHeader},
+		ExposedHeaders:   []string{"Link", TotalCountHeader, UploadOffsetHeader},
+		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 	r.Route("/api", func(r chi.Router) {
 
-		r.HandleFunc("/websocket", WithError(c.websocketHandler))
-		r.Get("/printer/sessions", WithError(c.printerSessions))
-		r.Get("/printer/info", WithError(c.printerInfo))
-
-		r.Post("/command/levelbedtest", WithError(c.commandLevelBedTest))
-		r.Post("/command/autohome", WithError(c.commandAutoHome))
-		r.Post("/command/unlock", WithError(c.commandUnlock))
-
-		r.Post("/command/load", WithError(c.commandLoad))
-		r.Post("/command/start", WithError(c.commandStart))
-		r.Post("/command/pause", WithError(c.commandPause))
-		r.Post("/command/cancel", WithError(c.commandCancel))
-
-		r.Get("/gcodes", WithError(c.gcodesList))
-		r.Post("/gcodes/upload", WithError(c.gcodesUpload))
-		r.Get("/gcodes/download", WithError(c.gcodesDownload))
+		// Agents authenticate with their token during the handshake
+		r.Group(func(r chi.Router) {
+			if agentCerts {
+				r.Use(requireClientCert)
+			}
+			r.Get("/websocket", WithError(c.websocketHandler))
+		})
+		r.Get("/openapi.json", WithError(c.openAPI))
+		r.With(c.validate).Post("/auth/login", WithError(c.authLogin))
+		r.Post("/auth/logout", WithError(c.authLogout))
+
+		r.Group(func(r chi.Router) {
+			r.Use(RequireRole(RoleViewer), c.validate)
+			r.Get("/auth/me", WithError(c.authMe))
+			r.Post("/auth/tokens/issue", WithError(c.apiTokensIssue))
+			r.Post("/auth/tokens/revoke", WithError(c.apiTokensRevoke))
+
+			r.Get("/printer/sessions", WithError(c.printerSessions))
+			r.Get("/printer/info", WithError(c.printerInfo))
+
+			r.Get("/gcodes", WithError(c.gcodesList))
+			r.Get("/gcodes/download", WithError(c.gcodesDownload))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(RequireRole(RoleOperator), c.validate)
+			r.Post("/command/levelbedtest", WithError(c.commandLevelBedTest))
+			r.Post("/command/autohome", WithError(c.commandAutoHome))
+			r.Post("/command/unlock", WithError(c.commandUnlock))
+			r.Post("/command/emergency-stop", WithError(c.commandEmergencyStop))
+
+			r.Post("/command/load", WithError(c.commandLoad))
+			r.Post("/command/start", WithError(c.commandStart))
+			r.Post("/command/pause", WithError(c.commandPause))
+			r.Post("/command/cancel", WithError(c.commandCancel))
+			r.Post("/command/resume", WithError(c.commandResume))
+			r.Post("/command/prompt", WithError(c.commandPrompt))
+			r.Post("/command/filament/load", WithError(c.commandLoadFilament))
+			r.Post("/command/filament/unload", WithError(c.commandUnloadFilament))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(RequireRole(RoleAdmin), c.validate)
+			r.Get("/printers", WithError(c.printersList))
+			r.Post("/printers", WithError(c.printersCreate))
+			r.Get("/printers/tokens", WithError(c.tokensList))
+			r.Post("/printers/tokens/issue", WithError(c.tokensIssue))
+			r.Post("/printers/tokens/revoke", WithError(c.tokensRevoke))
+
+			r.Get("/audit", WithError(c.auditList))
+
+			r.Get("/users", WithError(c.usersList))
+			r.Post("/users", WithError(c.usersCreate))
+
+			r.Post("/gcodes/upload", WithError(c.gcodesUpload))
+		})
+
+		// Resource routes, the routes above are kept for existing clients
+		r.Route("/v2", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(RequireRole(RoleViewer), c.validate)
+				r.Get("/printers", WithError(c.v2PrintersList))
+				r.Get("/printers/{id}", WithError(c.v2PrinterGet))
+				r.Get("/printers/{id}/jobs", WithError(c.v2JobsList))
+
+				r.Get("/gcodes", WithError(c.gcodesList))
+				r.Get("/gcodes/{id}", WithError(c.v2GcodeGet))
+				r.Get("/gcodes/{id}/content", WithError(c.v2GcodeContent))
+				r.Get("/gcodes/{id}/thumbnail", WithError(c.v2GcodeThumbnail))
+				r.Get("/gcodes/{id}/versions", WithError(c.v2VersionsList))
+				r.Get("/gcodes/{id}/diff", WithError(c.v2VersionsDiff))
+
+				r.Get("/folders", WithError(c.v2FoldersList))
+			})
+
+			// Agents download the jobs they're sent from here
+			r.With(c.requireViewerOrJobAgent(), c.validate).Get("/gcodes/{id}/versions/{version}/content", WithError(c.v2VersionContent))
+
+			r.Group(func(r chi.Router) {
+				r.Use(RequireRole(RoleOperator), c.validate)
+				r.Post("/printers/{id}/jobs", WithError(c.v2JobsCreate))
+				r.Post("/printers/{id}/commands", WithError(c.v2CommandsCreate))
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(RequireRole(RoleAdmin), c.validate)
+				r.Post("/printers", WithError(c.printersCreate))
+				r.Post("/gcodes", WithError(c.gcodesUpload))
+				r.Patch("/gcodes/{id}", WithError(c.v2GcodeUpdate))
+				r.Delete("/gcodes/{id}", WithError(c.v2GcodeDelete))
+				r.Post("/gcodes/{id}/restore", WithError(c.v2GcodeRestore))
+				r.Post("/gcodes/purge", WithError(c.v2GcodesPurge))
+
+				r.Post("/uploads", WithError(c.v2UploadsCreate))
+				r.Get("/uploads/{id}", WithError(c.v2UploadGet))
+				r.Patch("/uploads/{id}", WithError(c.v2UploadChunk))
+				r.Post("/uploads/{id}/complete", WithError(c.v2UploadComplete))
+				r.Delete("/uploads/{id}", WithError(c.v2UploadDelete))
+
+				r.Post("/folders", WithError(c.v2FoldersCreate))
+				r.Patch("/folders/{id}", WithError(c.v2FolderUpdate))
+				r.Delete("/folders/{id}", WithError(c.v2FolderDelete))
+			})
+		})
 	})
 
+	checkRoutes(r, c.spec)
 	return r
 }
 
-func (c *Controller) printerInfo(w http.ResponseWriter, r *http.Request) (int, error) {
-	sessionID := r.URL.Query().Get("session_id")
-	if sessionID == "" {
-		return http.StatusBadRequest, terror.New(errors.New("session id not provided"), "")
+// corsOrigins is who browsers may call the API from, the server's own origin unless others are configured
+func corsOrigins(serverHost string, allowedOrigins []string) []string {
+	if len(allowedOrigins) > 0 {
+		return allowedOrigins
 	}
-	currentSession := c.Sessions[sessionID]
-	resp := &APIResponse{}
-	b, err := json.Marshal(currentSession.Info)
-	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+	u, err := url.Parse(serverHost)
+	if err != nil || u.Host == "" {
+		return []string{}
 	}
-	resp.Payload = b
-	err = json.NewEncoder(w).Encode(resp)
-	if sessionID == "" {
-		return http.StatusBadRequest, terror.New(err, "")
+	return []string{u.Scheme + "://" + u.Host}
+}
+
+func (c *Controller) printerInfo(w http.ResponseWriter, r *http.Request) (int, error) {
+	chs, err := c.session(r.URL.Query().Get("session_id"))
+	if err != nil {
+		return http.StatusNotFound, err
 	}
-	return http.StatusOK, nil
+	return writePayload(w, chs.Info)
 }
 
 // APIResponse generic container for api response
@@ -114,18 +240,62 @@ type APIResponse struct {
 
 func (c *Controller) printerSessions(w http.ResponseWriter, r *http.Request) (int, error) {
 	result := []string{}
+	c.Lock()
 	for id := range c.Sessions {
 		result = append(result, id)
 	}
-	b, err := json.Marshal(result)
+	c.Unlock()
+	return writePayload(w, result)
+}
+
+// session finds the session for a printer. A printer that's registered but has no session
+// is one whose agent isn't connected, anything else is unknown.
+func (c *Controller) session(sessionID string) (*Session, error) {
+	if sessionID == "" {
+		return nil, errBadRequest(errors.New("session id not provided"))
+	}
+	c.Lock()
+	chs, ok := c.Sessions[sessionID]
+	c.Unlock()
+	if ok {
+		return chs, nil
+	}
+	notFound := NewAPIError(http.StatusNotFound, CodeSessionNotFound, "session not found", nil)
+	if _, err := uuid.FromString(sessionID); err != nil {
+		return nil, notFound
+	}
+	exists, err := db.Printers(db.PrinterWhere.ID.EQ(sessionID), db.PrinterWhere.DeletedAt.IsNull()).ExistsG()
 	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+		return nil, errInternal(err)
 	}
-	err = json.NewEncoder(w).Encode(&APIResponse{Payload: b})
+	if !exists {
+		return nil, notFound
+	}
+	return nil, NewAPIError(http.StatusServiceUnavailable, CodeAgentDisconnected, "printer's agent is not connected", nil)
+}
+
+// commandSession finds the session for a command, which needs the printer to be connected to its agent
+func (c *Controller) commandSession(sessionID string) (*Session, error) {
+	chs, err := c.session(sessionID)
 	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+		return nil, err
 	}
-	return http.StatusOK, nil
+	if chs.Info.Status == messages.StatusDisconnected {
+		return nil, NewAPIError(http.StatusServiceUnavailable, CodePrinterDisconnected, "printer is not connected to its agent", nil)
+	}
+	return chs, nil
+}
+
+// idleSession finds the session for a command that can't run while the printer is printing
+func (c *Controller) idleSession(sessionID string) (*Session, error) {
+	chs, err := c.commandSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if chs.Info.Busy {
+		return nil, NewAPIError(http.StatusConflict, CodePrinterBusy, "printer is busy", nil)
+	}
+	return chs, nil
 }
 
 // LoadCommand instructs printer on session ID to download file ID into memory
@@ -141,22 +311,12 @@ func (c *Controller) commandLoad(w http.ResponseWriter, r *http.Request) (int, e
 		return http.StatusBadRequest, terror.New(err, "")
 	}
 	if req.SessionID == "" || req.FileID == "" {
-		fmt.Printf("%+v", req)
 		return http.StatusBadRequest, terror.New(errors.New("session id or file id not provided"), "")
 	}
-
-	payload := &messages.PayloadLoadFile{
-		ID:  req.FileID,
-		URL: fmt.Sprintf("%s/api/gcodes/download?file_id=%s", c.Host, req.FileID),
-	}
-
-	b, err := json.Marshal(payload)
+	_, err = c.loadJob(r, req.SessionID, req.FileID, 0)
 	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+		return http.StatusInternalServerError, err
 	}
-	chs := c.Sessions[req.SessionID]
-	chs.Agent <- &messages.AsyncCommand{RequestID: uuid.Must(uuid.NewV4()).String(), MessageType: messages.TypeCommand, RequestType: messages.CommandLoad, Payload: b}
-
 	w.Write([]byte("OK"))
 	return http.StatusOK, nil
 }
@@ -173,60 +333,119 @@ type StartRequest struct {
 }
 
 func (c *Controller) commandStart(w http.ResponseWriter, r *http.Request) (int, error) {
-	req := &SessionRequest{}
-	err := json.NewDecoder(r.Body).Decode(req)
-	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
-	}
-	if req.SessionID == "" {
-		fmt.Printf("%+v", req)
-		return http.StatusBadRequest, terror.New(errors.New("session id or file id not provided"), "")
-	}
-	chs := c.Sessions[req.SessionID]
-	chs.Agent <- &messages.AsyncCommand{RequestID: uuid.Must(uuid.NewV4()).String(), MessageType: messages.TypeCommand, RequestType: messages.CommandStart}
-	return http.StatusOK, nil
+	return c.sessionCommand(r, CommandStart)
 }
 func (c *Controller) commandPause(w http.ResponseWriter, r *http.Request) (int, error) {
-	// c.AsyncCh <- &messages.AsyncCommand{RequestID: uuid.Must(uuid.NewV4()).String(), Type: messages.CommandPause}
-	return http.StatusOK, nil
+	return c.sessionCommand(r, CommandPause)
+}
+func (c *Controller) commandResume(w http.ResponseWriter, r *http.Request) (int, error) {
+	return c.sessionCommand(r, CommandResume)
 }
 func (c *Controller) commandCancel(w http.ResponseWriter, r *http.Request) (int, error) {
-	// c.AsyncCh <- &messages.AsyncCommand{RequestID: uuid.Must(uuid.NewV4()).String(), Type: messages.CommandCancel}
-	return http.StatusOK, nil
+	return c.sessionCommand(r, CommandCancel)
+}
+func (c *Controller) commandLoadFilament(w http.ResponseWriter, r *http.Request) (int, error) {
+	return c.sessionCommand(r, CommandFilamentLoad)
 }
-func (c *Controller) gcodesList(w http.ResponseWriter, r *http.Request) (int, error) {
-	result, err := db.Gcodes().AllG()
+func (c *Controller) commandUnloadFilament(w http.ResponseWriter, r *http.Request) (int, error) {
+	return c.sessionCommand(r, CommandFilamentUnload)
+}
+
+// PromptRequest answers the prompt showing on the printer
+type PromptRequest struct {
+	SessionID string `json:"sessionId"`
+	Choice    int    `json:"choice"`
+}
+
+func (c *Controller) commandPrompt(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &PromptRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
 	if err != nil {
 		return http.StatusBadRequest, terror.New(err, "")
 	}
-	b, err := json.Marshal(result)
+	err = c.runCommand(r, req.SessionID, &CommandRequest{Command: CommandPrompt, Choice: &req.Choice})
 	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+		return http.StatusInternalServerError, err
 	}
+	return http.StatusOK, nil
+}
 
-	err = json.NewEncoder(w).Encode(&APIResponse{Payload: b})
+// sessionCommand runs a command without arguments on the printer named in the request body.
+// It keeps the old /command routes working on top of /v2/printers/{id}/commands.
+func (c *Controller) sessionCommand(r *http.Request, command CommandName) (int, error) {
+	req := &SessionRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
 	if err != nil {
 		return http.StatusBadRequest, terror.New(err, "")
 	}
+	err = c.runCommand(r, req.SessionID, &CommandRequest{Command: command})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
 	return http.StatusOK, nil
 }
+
+// findGcode looks up a gcode file, a 404 when there isn't one or it has been deleted
+func findGcode(fileID string) (*db.Gcode, error) {
+	gc, err := db.Gcodes(db.GcodeWhere.ID.EQ(fileID), db.GcodeWhere.DeletedAt.IsNull()).OneG()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, NewAPIError(http.StatusNotFound, CodeFileNotFound, "file not found", err)
+	}
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return gc, nil
+}
+
 func (c *Controller) gcodesDownload(w http.ResponseWriter, r *http.Request) (int, error) {
 	fileID := r.URL.Query().Get("file_id")
 	if fileID == "" {
 		return http.StatusBadRequest, terror.New(errors.New("no file_id"), "")
 	}
-	gc, err := db.FindGcodeG(fileID)
+	gc, err := findGcode(fileID)
 	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+		return http.StatusNotFound, err
 	}
-	blob, err := db.FindBlobG(gc.BlobID)
+	return writeGcode(w, r, gc)
+}
+
+// writeGcode sends the current version of the gcode's file as an attachment
+func writeGcode(w http.ResponseWriter, r *http.Request, gc *db.Gcode) (int, error) {
+	return writeBlob(w, r, gc.Name, gc.BlobID)
+}
+
+// writeBlob sends the blob as an attachment with the file's name. It goes as it's stored when the
+// client accepts the blob's codec, gzipped when the client only accepts gzip, and uncompressed otherwise.
+func writeBlob(w http.ResponseWriter, r *http.Request, name, blobID string) (int, error) {
+	blob, err := db.FindBlobG(blobID)
 	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	data := blob.Data
+	encoding := blob.Codec
+	accept := r.Header.Get("Accept-Encoding")
+	if !codec.Accepts(accept, encoding) {
+		data, err = blobData(blob)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		encoding = codec.Identity
+		if codec.Accepts(accept, codec.Gzip) {
+			data, err = codec.Encode(codec.Gzip, data)
+			if err != nil {
+				return http.StatusInternalServerError, terror.New(err, "")
+			}
+			encoding = codec.Gzip
+		}
 	}
-	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.html"`, gc.Name))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.html"`, name))
 	w.Header().Set("Content-Type", "application/octet-stream")
-	w.Header().Set("Content-Length", strconv.Itoa(len(blob.Data)))
-	w.Write(blob.Data)
+	w.Header().Set("Vary", "Accept-Encoding")
+	if encoding != codec.Identity {
+		w.Header().Set("Content-Encoding", encoding)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
 	return http.StatusOK, nil
 }
 func (c *Controller) gcodesUpload(w http.ResponseWriter, r *http.Request) (int, error) {
@@ -236,29 +455,34 @@ func (c *Controller) gcodesUpload(w http.ResponseWriter, r *http.Request) (int,
 		return http.StatusBadRequest, terror.New(err, "failed to parse multipart message")
 	}
 	file, header, err := r.FormFile("file")
-	defer file.Close()
 	if err != nil {
 		return http.StatusBadRequest, terror.New(err, "")
 	}
+	defer file.Close()
 	b, err := ioutil.ReadAll(file)
 	if err != nil {
 		return http.StatusBadRequest, terror.New(err, "")
 	}
-	blob := &db.Blob{Data: b, FileName: header.Filename, FileSizeBytes: header.Size}
-	err = blob.InsertG(boil.Infer())
-	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+	upload := &Upload{
+		Name:       header.Filename,
+		Data:       b,
+		FolderID:   r.FormValue("folder_id"),
+		Tags:       strings.Split(r.FormValue("tags"), ","),
+		NewVersion: r.FormValue("new_version") == "true",
+		UploadedBy: PrincipalFromContext(r.Context()).Actor(),
 	}
-	gcode := &db.Gcode{
-		Name:   header.Filename,
-		BlobID: blob.ID,
+	if upload.FolderID != "" {
+		_, err = findFolder(upload.FolderID)
+		if err != nil {
+			return http.StatusNotFound, err
+		}
 	}
-	err = gcode.InsertG(boil.Infer())
+	gcode, err := StoreGcode(r.Context(), upload)
 	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
+		return http.StatusInternalServerError, terror.New(err, "")
 	}
-
-	return http.StatusOK, nil
+	c.audit(r, "", AuditFileUpload, map[string]interface{}{"file_id": gcode.ID, "name": gcode.Name, "size": header.Size, "version": gcode.Version})
+	return writePayload(w, gcode)
 }
 
 func (c *Controller) websocketHandler(w http.ResponseWriter, r *http.Request) (int, error) {
@@ -267,58 +491,148 @@ func (c *Controller) websocketHandler(w http.ResponseWriter, r *http.Request) (i
 		return http.StatusBadRequest, terror.New(err, "")
 	}
 	defer wsconn.Close(websocket.StatusNormalClosure, "Unknown")
-	sessionID := uuid.Must(uuid.NewV4()).String()
 	agentChan := make(chan *messages.AsyncCommand)
 	serverChan := make(chan *messages.AsyncCommand)
 
 	fmt.Println("New connection request")
+	hello, token, err := c.handshake(r.Context(), wsconn)
+	if err != nil {
+		// Response has already been hijacked by the websocket, nothing to write
+		terror.Echo(err)
+		return http.StatusOK, nil
+	}
+	printer := token.R.Printer
+	sessionID := printer.ID
+
+	// The session ends when the agent's connection does, so it can be picked up again on reconnect
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	currentSession := &Session{
+		Info:    &messages.AgentInfo{Busy: false, Status: messages.StatusUnknown},
+		Hello:   hello,
+		Printer: printer,
+		TokenID: token.ID,
+		Agent:   agentChan,
+		Server:  serverChan,
+		close:   cancel,
+		done:    ctx.Done(),
+	}
 	c.Lock()
-	c.Sessions[sessionID] = &Session{&messages.AgentInfo{Busy: false, Status: messages.StatusUnknown}, agentChan, serverChan}
-	currentSession := c.Sessions[sessionID]
+	if old, ok := c.Sessions[sessionID]; ok {
+		// The printer reconnected before we noticed it had gone, the old connection is dead
+		log.Infow("Replacing session", "session_id", sessionID)
+		old.close()
+	}
+	c.Sessions[sessionID] = currentSession
 	c.Unlock()
 	defer func() {
 		c.Lock()
-		delete(c.Sessions, sessionID)
+		if c.Sessions[sessionID] == currentSession {
+			delete(c.Sessions, sessionID)
+		}
 		fmt.Println("Session removed")
 		c.Unlock()
 	}()
 	fmt.Println("Session established")
 
 	go func() {
+		defer cancel()
 		for {
 			// Handle messages coming in from Agent to be processed
 			time.Sleep(500 * time.Millisecond)
 			result := &messages.AsyncCommand{}
-			err := wsjson.Read(r.Context(), wsconn, result)
+			err := wsjson.Read(ctx, wsconn, result)
 			if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
 				fmt.Println("websocket closed")
-				continue
+				return
 			}
 			if err != nil {
 				fmt.Println(err)
-				continue
+				return
 			}
-			// fmt.Println(string(result.Payload))
-			agentInfo := &messages.AgentInfo{}
-			err = json.Unmarshal(result.Payload, agentInfo)
+			payload, err := messages.Decode(result)
 			if err != nil {
 				fmt.Println(err)
 				continue
 			}
-			currentSession.Info = agentInfo
-
+			switch p := payload.(type) {
+			case *messages.AgentInfo:
+				currentSession.Info = p
+			default:
+				fmt.Println("unhandled message from agent:", result.RequestType)
+			}
 		}
 	}()
 	for {
 		select {
 		case msg := <-agentChan:
 			// Handle messages to be forwarded to Agent
-			err = writeTimeout(r.Context(), 100*time.Second, wsconn, msg)
+			err = writeTimeout(ctx, 100*time.Second, wsconn, msg)
 			if err != nil {
-				return http.StatusBadRequest, terror.New(err, "")
+				// The connection is hijacked, so there's no response to write the error to
+				terror.Echo(err)
+				return http.StatusOK, nil
 			}
+		case <-ctx.Done():
+			return http.StatusOK, nil
+		}
+	}
+}
+
+// handshake waits for the agent's hello and accepts or rejects it, returning the token it authenticated with
+func (c *Controller) handshake(ctx context.Context, wsconn *websocket.Conn) (*messages.PayloadHello, *db.AgentToken, error) {
+	readCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	result := &messages.AsyncCommand{}
+	err := wsjson.Read(readCtx, wsconn, result)
+	if err != nil {
+		return nil, nil, terror.New(err, "")
+	}
+
+	hello := &messages.PayloadHello{}
+	token := &db.AgentToken{}
+	if result.RequestType != messages.InfoHello {
+		err = fmt.Errorf("expected %s, got %s", messages.InfoHello, result.RequestType)
+	} else {
+		payload, decodeErr := messages.Decode(result)
+		if decodeErr != nil {
+			err = decodeErr
+		} else {
+			hello = payload.(*messages.PayloadHello)
+			err = messages.CheckCompatible(hello)
+			if err == nil {
+				token, err = AuthenticateAgent(hello.Token)
+			}
+		}
+	}
+	if err != nil {
+		log.Warnw("Rejecting agent", "err", err, "agent_version", hello.AgentVersion, "protocol_version", hello.ProtocolVersion)
+		reject, encodeErr := messages.Encode(messages.TypeInfo, messages.InfoHelloReject, &messages.PayloadHelloReject{
+			ProtocolVersion: messages.ProtocolVersion,
+			Reason:          err.Error(),
+		})
+		if encodeErr == nil {
+			writeTimeout(ctx, 5*time.Second, wsconn, reject)
 		}
+		wsconn.Close(websocket.StatusPolicyViolation, "handshake rejected")
+		return nil, nil, terror.New(err, "")
+	}
+
+	printer := token.R.Printer
+	sessionID := printer.ID
+	ack, err := messages.Encode(messages.TypeInfo, messages.InfoHelloAck, &messages.PayloadHelloAck{
+		ProtocolVersion: messages.ProtocolVersion,
+		SessionID:       sessionID,
+	})
+	if err != nil {
+		return nil, nil, terror.New(err, "")
 	}
+	err = writeTimeout(ctx, 5*time.Second, wsconn, ack)
+	if err != nil {
+		return nil, nil, terror.New(err, "")
+	}
+	log.Infow("Agent connected", "session_id", sessionID, "printer", printer.Name, "name", hello.Name, "agent_version", hello.AgentVersion, "firmware", hello.Firmware.Name)
+	return hello, token, nil
 }
 
 func writeTimeout(ctx context.Context, timeout time.Duration, c *websocket.Conn, v interface{}) error {
@@ -336,22 +650,15 @@ func (c *Controller) commandLevelBedTest(w http.ResponseWriter, r *http.Request)
 
 // AutoHome will send level bed command
 func (c *Controller) commandAutoHome(w http.ResponseWriter, r *http.Request) (int, error) {
-	req := &SessionRequest{}
-	err := json.NewDecoder(r.Body).Decode(req)
-	if err != nil {
-		return http.StatusBadRequest, terror.New(err, "")
-	}
-	if req.SessionID == "" {
-		fmt.Printf("%+v", req)
-		return http.StatusBadRequest, terror.New(errors.New("session id not provided"), "")
-	}
-	chs := c.Sessions[req.SessionID]
-	chs.Agent <- &messages.AsyncCommand{RequestID: uuid.Must(uuid.NewV4()).String(), MessageType: messages.TypeCommand, RequestType: messages.CommandAutoHome}
-	return http.StatusOK, nil
+	return c.sessionCommand(r, CommandAutoHome)
 }
 
-// commandUnlock will unlock its mutex
+// commandUnlock resets a halted printer
 func (c *Controller) commandUnlock(w http.ResponseWriter, r *http.Request) (int, error) {
-	// c.AsyncCh <- &messages.AsyncCommand{RequestID: uuid.Must(uuid.NewV4()).String(), Type: messages.UnlockPrinter}
-	return http.StatusOK, nil
+	return c.sessionCommand(r, CommandUnlock)
+}
+
+// commandEmergencyStop halts the printer, the agent handles it ahead of anything else
+func (c *Controller) commandEmergencyStop(w http.ResponseWriter, r *http.Request) (int, error) {
+	return c.sessionCommand(r, CommandEmergencyStop)
 }
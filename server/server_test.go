@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"go-3dprint/messages"
+	"net/http"
+	"testing"
+)
+
+func TestSessionSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		taken      bool // The connection reads the command
+		agentGone  bool
+		requestEnd bool
+		wantStatus int
+	}{
+		{name: "taken", taken: true},
+		{name: "agent disconnected", agentGone: true, wantStatus: http.StatusServiceUnavailable},
+		{name: "request ended", requestEnd: true, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan struct{})
+			s := &Session{Agent: make(chan *messages.AsyncCommand), done: done}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.taken {
+				go func() { <-s.Agent }()
+			}
+			if tt.agentGone {
+				close(done)
+			}
+			if tt.requestEnd {
+				cancel()
+			}
+			err := s.send(ctx, &messages.AsyncCommand{})
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Status != tt.wantStatus {
+				t.Fatalf("got error %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
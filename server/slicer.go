@@ -0,0 +1,76 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// SettingAdded is a setting only the newer version has
+const SettingAdded = "added"
+
+// SettingRemoved is a setting only the older version has
+const SettingRemoved = "removed"
+
+// SettingChanged is a setting whose value differs between the versions
+const SettingChanged = "changed"
+
+// SettingChange is one difference between two files' slicer settings
+type SettingChange struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+// SlicerSettings reads the settings slicers leave in comments. PrusaSlicer, SuperSlicer and Orca write
+// "; key = value" lines, Cura writes ";key:value" lines in the header before the first command.
+func SlicerSettings(data []byte) map[string]string {
+	settings := map[string]string{}
+	header := true
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, ";") {
+			header = false
+			continue
+		}
+		comment := strings.TrimSpace(strings.TrimPrefix(line, ";"))
+		if i := strings.Index(comment, " = "); i > 0 {
+			settings[strings.TrimSpace(comment[:i])] = strings.TrimSpace(comment[i+3:])
+			continue
+		}
+		if header {
+			if i := strings.Index(comment, ":"); i > 0 {
+				settings[strings.TrimSpace(comment[:i])] = strings.TrimSpace(comment[i+1:])
+			}
+		}
+	}
+	return settings
+}
+
+// DiffSettings lists what changed from one set of settings to another, by key
+func DiffSettings(from, to map[string]string) []*SettingChange {
+	result := []*SettingChange{}
+	for k, v := range from {
+		nv, ok := to[k]
+		switch {
+		case !ok:
+			result = append(result, &SettingChange{Key: k, Change: SettingRemoved, From: v})
+		case nv != v:
+			result = append(result, &SettingChange{Key: k, Change: SettingChanged, From: v, To: nv})
+		}
+	}
+	for k, v := range to {
+		if _, ok := from[k]; !ok {
+			result = append(result, &SettingChange{Key: k, Change: SettingAdded, To: v})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSlicerSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]string
+	}{
+		{
+			name: "prusaslicer",
+			data: "; generated by PrusaSlicer 2.6.0\nG28\nG1 X10 ; move = fast\n; layer_height = 0.2\n; filament_type = PLA;PETG\n",
+			want: map[string]string{"layer_height": "0.2", "filament_type": "PLA;PETG"},
+		},
+		{
+			name: "cura header",
+			data: ";FLAVOR:Marlin\n;TIME:3600\n;Filament used: 1.5m\n\n;Generated with Cura_SteamEngine 5.4.0\nG28\n;LAYER:0\n",
+			want: map[string]string{"FLAVOR": "Marlin", "TIME": "3600", "Filament used": "1.5m"},
+		},
+		{
+			name: "later settings win",
+			data: "; infill = 10%\n; infill = 20%\n",
+			want: map[string]string{"infill": "20%"},
+		},
+		{
+			name: "comments that aren't settings",
+			data: ";\n; = nothing\n;:nothing\n; just a note\n",
+			want: map[string]string{},
+		},
+		{
+			name: "no comments",
+			data: "G28\nG1 X10\n",
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SlicerSettings([]byte(tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		from map[string]string
+		to   map[string]string
+		want []*SettingChange
+	}{
+		{
+			name: "the same",
+			from: map[string]string{"layer_height": "0.2"},
+			to:   map[string]string{"layer_height": "0.2"},
+			want: []*SettingChange{},
+		},
+		{
+			name: "added, removed and changed in key order",
+			from: map[string]string{"layer_height": "0.2", "brim": "5", "infill": "10%"},
+			to:   map[string]string{"layer_height": "0.3", "infill": "10%", "support": "1"},
+			want: []*SettingChange{
+				{Key: "brim", Change: SettingRemoved, From: "5"},
+				{Key: "layer_height", Change: SettingChanged, From: "0.2", To: "0.3"},
+				{Key: "support", Change: SettingAdded, To: "1"},
+			},
+		},
+		{
+			name: "from nothing",
+			to:   map[string]string{"infill": "10%"},
+			want: []*SettingChange{{Key: "infill", Change: SettingAdded, To: "10%"}},
+		},
+		{
+			name: "changed to empty",
+			from: map[string]string{"start_gcode": "G28"},
+			to:   map[string]string{"start_gcode": ""},
+			want: []*SettingChange{{Key: "start_gcode", Change: SettingChanged, From: "G28"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffSettings(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ninja-software/terror"
+)
+
+// SelfSignedDuration is how long a generated certificate is valid for
+const SelfSignedDuration = 2 * 365 * 24 * time.Hour
+
+// TLSOptions configures HTTPS for the server. It's served plain when no certificate is given.
+type TLSOptions struct {
+	CertFile     string
+	KeyFile      string
+	SelfSigned   bool     // Generate the certificate and key at CertFile and KeyFile if they don't exist
+	Hosts        []string // Names and addresses the generated certificate is for
+	ClientCAFile string   // Require agents to present a certificate signed by one of these
+}
+
+// Enabled reports whether the server should use TLS
+func (o *TLSOptions) Enabled() bool {
+	return o.CertFile != "" || o.KeyFile != ""
+}
+
+// ClientCerts reports whether agents must present a client certificate
+func (o *TLSOptions) ClientCerts() bool {
+	return o.Enabled() && o.ClientCAFile != ""
+}
+
+// Config loads the certificate, generating it first if asked to
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	if o.CertFile == "" || o.KeyFile == "" {
+		return nil, terror.New(errors.New("tls_cert and tls_key must be given together"), "")
+	}
+	if o.SelfSigned {
+		err := o.ensureSelfSigned()
+		if err != nil {
+			return nil, err
+		}
+	}
+	cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if o.ClientCAFile != "" {
+		b, err := ioutil.ReadFile(o.ClientCAFile)
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, terror.New(fmt.Errorf("%s: no certificates found", o.ClientCAFile), "")
+		}
+		config.ClientCAs = pool
+		// Browsers don't have certificates, only the agent's websocket requires one, see requireClientCert
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
+
+// ensureSelfSigned writes a new certificate and key unless there already are both.
+// The certificate is kept across restarts so agents given it as their CA bundle keep trusting the server.
+func (o *TLSOptions) ensureSelfSigned() error {
+	certExists, err := fileExists(o.CertFile)
+	if err != nil {
+		return err
+	}
+	keyExists, err := fileExists(o.KeyFile)
+	if err != nil {
+		return err
+	}
+	if certExists && keyExists {
+		return nil
+	}
+	if certExists {
+		log.Warnw("Certificate has no key, generating a new one", "cert", o.CertFile, "key", o.KeyFile)
+	}
+	certPEM, keyPEM, err := SelfSignedCertificate(o.Hosts)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(o.KeyFile, keyPEM, 0600)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	err = ioutil.WriteFile(o.CertFile, certPEM, 0644)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	block, _ := pem.Decode(certPEM)
+	sum := sha256.Sum256(block.Bytes)
+	log.Infow("Generated self-signed certificate, give it to agents as their CA bundle", "cert", o.CertFile, "hosts", o.Hosts, "sha256", hex.EncodeToString(sum[:]))
+	return nil
+}
+
+// SelfSignedCertificate creates a certificate for the hosts that signs itself, so it can be trusted directly.
+// It's returned PEM encoded along with its key.
+func SelfSignedCertificate(hosts []string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, terror.New(err, "")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, terror.New(err, "")
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"go-3dprint"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(SelfSignedDuration),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	if len(hosts) > 0 {
+		template.Subject.CommonName = hosts[0]
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, terror.New(err, "")
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, terror.New(err, "")
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+func fileExists(name string) (bool, error) {
+	_, err := os.Stat(name)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, terror.New(err, "")
+}
+
+// requireClientCert turns away connections that didn't present a certificate. The TLS handshake
+// has already verified any that were given against the client CAs.
+func requireClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			writeError(w, NewAPIError(http.StatusForbidden, CodeForbidden, "client certificate required", nil))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
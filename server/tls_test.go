@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnsureSelfSigned(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string // Files already there before the server starts
+		wantKept bool
+	}{
+		{name: "neither", wantKept: false},
+		{name: "certificate without its key", existing: []string{"cert.pem"}, wantKept: false},
+		{name: "key without its certificate", existing: []string{"key.pem"}, wantKept: false},
+		{name: "both", existing: []string{"cert.pem", "key.pem"}, wantKept: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "tls")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			o := &TLSOptions{CertFile: filepath.Join(dir, "cert.pem"), KeyFile: filepath.Join(dir, "key.pem"), Hosts: []string{"localhost"}}
+			for _, name := range tt.existing {
+				err = ioutil.WriteFile(filepath.Join(dir, name), []byte("existing"), 0600)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			err = o.ensureSelfSigned()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cert, err := ioutil.ReadFile(o.CertFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kept := bytes.Equal(cert, []byte("existing"))
+			if kept != tt.wantKept {
+				t.Fatalf("kept is %v, want %v", kept, tt.wantKept)
+			}
+			if kept {
+				return
+			}
+			_, err = tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+			if err != nil {
+				t.Errorf("generated pair doesn't load: %v", err)
+			}
+		})
+	}
+}
+
+func TestRequireClientCert(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *tls.ConnectionState
+		want  int
+	}{
+		{name: "plain connection", want: http.StatusForbidden},
+		{name: "no certificate", state: &tls.ConnectionState{}, want: http.StatusForbidden},
+		{name: "certificate", state: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}, want: http.StatusOK},
+	}
+	handler := requireClientCert(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/websocket", nil)
+			r.TLS = tt.state
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"go-3dprint/db"
+	"net/http"
+	"time"
+
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// ErrUnknownAgent is returned when an agent's token doesn't belong to any printer
+var ErrUnknownAgent = errors.New("unknown or revoked agent token")
+
+// hashToken is what's stored for a token, the token itself is only shown when it's issued
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newToken is a random token for a client to present, only its hash is stored
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", terror.New(err, "")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreatePrinter registers a printer that agents can be issued tokens for
+func CreatePrinter(name string) (*db.Printer, error) {
+	if name == "" {
+		return nil, terror.New(errors.New("printer name is required"), "")
+	}
+	printer := &db.Printer{Name: name}
+	err := printer.InsertG(boil.Infer())
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return printer, nil
+}
+
+// FindPrinterByName looks up a printer by the name it was registered with
+func FindPrinterByName(name string) (*db.Printer, error) {
+	printer, err := db.Printers(db.PrinterWhere.Name.EQ(name), db.PrinterWhere.DeletedAt.IsNull()).OneG()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return printer, nil
+}
+
+// IssueAgentToken creates a new token for the printer. The token is returned once and only its hash kept.
+func IssueAgentToken(printerID string) (string, *db.AgentToken, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", nil, err
+	}
+	agentToken := &db.AgentToken{PrinterID: printerID, TokenHash: hashToken(token)}
+	err = agentToken.InsertG(boil.Infer())
+	if err != nil {
+		return "", nil, terror.New(err, "")
+	}
+	return token, agentToken, nil
+}
+
+// TokenInfo describes an issued token without giving it away
+type TokenInfo struct {
+	ID         string    `json:"id"`
+	PrinterID  string    `json:"printerId"`
+	LastUsedAt null.Time `json:"lastUsedAt"`
+	RevokedAt  null.Time `json:"revokedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ListAgentTokens lists the tokens issued for the printer, revoked ones included
+func ListAgentTokens(printerID string) ([]*TokenInfo, error) {
+	tokens, err := db.AgentTokens(db.AgentTokenWhere.PrinterID.EQ(printerID), qm.OrderBy(db.AgentTokenColumns.CreatedAt)).AllG()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	result := []*TokenInfo{}
+	for _, t := range tokens {
+		result = append(result, &TokenInfo{
+			ID:         t.ID,
+			PrinterID:  t.PrinterID,
+			LastUsedAt: t.LastUsedAt,
+			RevokedAt:  t.RevokedAt,
+			CreatedAt:  t.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// RevokeAgentToken stops the token being accepted from now on
+func RevokeAgentToken(tokenID string) error {
+	agentToken, err := db.FindAgentTokenG(tokenID)
+	if err != nil {
+		return terror.New(err, "")
+	}
+	agentToken.RevokedAt = null.TimeFrom(time.Now())
+	_, err = agentToken.UpdateG(boil.Whitelist(db.AgentTokenColumns.RevokedAt))
+	if err != nil {
+		return terror.New(err, "")
+	}
+	return nil
+}
+
+// AuthenticateAgent finds an agent's token, with the printer it was issued for loaded
+func AuthenticateAgent(token string) (*db.AgentToken, error) {
+	if token == "" {
+		return nil, ErrUnknownAgent
+	}
+	agentToken, err := db.AgentTokens(
+		db.AgentTokenWhere.TokenHash.EQ(hashToken(token)),
+		db.AgentTokenWhere.RevokedAt.IsNull(),
+		qm.Load(db.AgentTokenRels.Printer),
+	).OneG()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUnknownAgent
+	}
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	printer := agentToken.R.Printer
+	if printer == nil || printer.DeletedAt.Valid {
+		return nil, ErrUnknownAgent
+	}
+	agentToken.LastUsedAt = null.TimeFrom(time.Now())
+	_, err = agentToken.UpdateG(boil.Whitelist(db.AgentTokenColumns.LastUsedAt))
+	if err != nil {
+		log.Warnw("Could not record token use", "err", err)
+	}
+	return agentToken, nil
+}
+
+// PrinterRequest registers a printer
+type PrinterRequest struct {
+	Name string `json:"name"`
+}
+
+// TokenRequest issues a token for a printer or revokes one
+type TokenRequest struct {
+	PrinterID string `json:"printerId"`
+	TokenID   string `json:"tokenId"`
+}
+
+// IssuedToken is returned once when a token is issued, the token can't be recovered afterwards
+type IssuedToken struct {
+	ID        string `json:"id"`
+	PrinterID string `json:"printerId,omitempty"`
+	Token     string `json:"token"`
+}
+
+func (c *Controller) printersList(w http.ResponseWriter, r *http.Request) (int, error) {
+	result, err := db.Printers(db.PrinterWhere.DeletedAt.IsNull()).AllG()
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return writePayload(w, result)
+}
+
+func (c *Controller) tokensList(w http.ResponseWriter, r *http.Request) (int, error) {
+	printerID := r.URL.Query().Get("printer_id")
+	if printerID == "" {
+		return http.StatusBadRequest, terror.New(errors.New("printer_id is required"), "")
+	}
+	tokens, err := ListAgentTokens(printerID)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return writePayload(w, tokens)
+}
+
+func (c *Controller) printersCreate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &PrinterRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	printer, err := CreatePrinter(req.Name)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	return writePayload(w, printer)
+}
+
+func (c *Controller) tokensIssue(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &TokenRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	if req.PrinterID == "" {
+		return http.StatusBadRequest, terror.New(errors.New("printerId is required"), "")
+	}
+	token, agentToken, err := IssueAgentToken(req.PrinterID)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return writePayload(w, &IssuedToken{ID: agentToken.ID, PrinterID: agentToken.PrinterID, Token: token})
+}
+
+func (c *Controller) tokensRevoke(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &TokenRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	if req.TokenID == "" {
+		return http.StatusBadRequest, terror.New(errors.New("tokenId is required"), "")
+	}
+	err = RevokeAgentToken(req.TokenID)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	// Drop the agent using it, it won't get back in
+	c.Lock()
+	for _, s := range c.Sessions {
+		if s.TokenID == req.TokenID {
+			s.close()
+		}
+	}
+	c.Unlock()
+	return http.StatusOK, nil
+}
+
+// writePayload writes v wrapped in an APIResponse
+func writePayload(w http.ResponseWriter, v interface{}) (int, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(&APIResponse{Payload: b})
+	if err != nil {
+		// Headers have gone out already, all that can be done is log it
+		terror.Echo(err)
+	}
+	return http.StatusOK, nil
+}
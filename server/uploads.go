@@ -0,0 +1,294 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-3dprint/db"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// UploadOffsetHeader carries where a chunk starts, and where the upload is up to in responses
+const UploadOffsetHeader = "Upload-Offset"
+
+// MaxChunkSize is the most a single chunk can carry
+const MaxChunkSize = 16 << 20
+
+// MaxUploadSize is the largest file a resumable upload can be for. The chunks are put back
+// together in memory to be stored, so this is also how much memory completing one can take.
+const MaxUploadSize = 256 << 20
+
+// UploadExpiry is how long an upload can go without a chunk before it's thrown away
+const UploadExpiry = 24 * time.Hour
+
+// CodeOffsetMismatch is a chunk that doesn't start where the upload is up to
+const CodeOffsetMismatch ErrorCode = "offset_mismatch"
+
+// CodeChecksumMismatch is a completed upload that doesn't hash to what the client said it would
+const CodeChecksumMismatch ErrorCode = "checksum_mismatch"
+
+// UploadRequest starts a resumable upload
+type UploadRequest struct {
+	Name       string   `json:"name"`
+	SizeBytes  int64    `json:"size_bytes"`
+	SHA256     string   `json:"sha256,omitempty"` // Hex, checked when the upload completes
+	FolderID   string   `json:"folder_id,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	NewVersion bool     `json:"new_version,omitempty"` // As for the new_version field of a single request upload
+}
+
+// CreateUpload starts a resumable upload, clearing out any that have expired
+func CreateUpload(req *UploadRequest, createdBy string) (*db.Upload, error) {
+	_, err := db.Uploads(db.UploadWhere.ExpiresAt.LT(time.Now())).DeleteAllG()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errBadRequest(errors.New("name must not be empty"))
+	}
+	if req.SizeBytes < 1 || req.SizeBytes > MaxUploadSize {
+		return nil, errBadRequest(fmt.Errorf("size_bytes must be between 1 and %d", int64(MaxUploadSize)))
+	}
+	upload := &db.Upload{
+		Name:       name,
+		SizeBytes:  req.SizeBytes,
+		Tags:       normaliseTags(req.Tags),
+		NewVersion: req.NewVersion,
+		CreatedBy:  createdBy,
+		ExpiresAt:  time.Now().Add(UploadExpiry),
+	}
+	if req.SHA256 != "" {
+		sum, err := hex.DecodeString(req.SHA256)
+		if err != nil || len(sum) != sha256.Size {
+			return nil, errBadRequest(errors.New("sha256 must be a hex encoded SHA-256 digest"))
+		}
+		upload.Sha256 = null.StringFrom(strings.ToLower(req.SHA256))
+	}
+	if req.FolderID != "" {
+		_, err = findFolder(req.FolderID)
+		if err != nil {
+			return nil, err
+		}
+		upload.FolderID = null.StringFrom(req.FolderID)
+	}
+	err = upload.InsertG(boil.Infer())
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return upload, nil
+}
+
+// findUpload looks up an upload that hasn't expired, a 404 when there isn't one.
+// Uploads belong to whoever started them, nobody else can see or add to them.
+func findUpload(exec boil.Executor, uploadID, createdBy string, mods ...qm.QueryMod) (*db.Upload, error) {
+	mods = append(mods,
+		db.UploadWhere.ID.EQ(uploadID),
+		db.UploadWhere.CreatedBy.EQ(createdBy),
+		db.UploadWhere.ExpiresAt.GT(time.Now()),
+	)
+	upload, err := db.Uploads(mods...).One(exec)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, NewAPIError(http.StatusNotFound, CodeNotFound, "upload not found or expired", err)
+	}
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return upload, nil
+}
+
+// offsetMismatch tells the client where to carry on from
+func offsetMismatch(upload *db.Upload) error {
+	return NewAPIError(http.StatusConflict, CodeOffsetMismatch, fmt.Sprintf("upload is at offset %d", upload.ReceivedBytes), nil)
+}
+
+// AppendChunk adds data to the upload, which must start where the upload is up to
+func AppendChunk(ctx context.Context, uploadID, createdBy string, offset int64, data []byte) (*db.Upload, error) {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	defer tx.Rollback()
+
+	// Lock the upload so chunks sent twice at once can't both land
+	upload, err := findUpload(tx, uploadID, createdBy, qm.For("UPDATE"))
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.ReceivedBytes {
+		return upload, offsetMismatch(upload)
+	}
+	if int64(len(data)) > upload.SizeBytes-upload.ReceivedBytes {
+		return upload, errBadRequest(fmt.Errorf("chunk runs past the end of the %d byte upload", upload.SizeBytes))
+	}
+	if len(data) == 0 {
+		return upload, nil
+	}
+	chunk := &db.UploadChunk{UploadID: upload.ID, StartOffset: offset, Data: data}
+	err = chunk.Insert(tx, boil.Infer())
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	upload.ReceivedBytes += int64(len(data))
+	upload.ExpiresAt = time.Now().Add(UploadExpiry)
+	upload.UpdatedAt = time.Now()
+	_, err = upload.Update(tx, boil.Whitelist(db.UploadColumns.ReceivedBytes, db.UploadColumns.ExpiresAt, db.UploadColumns.UpdatedAt))
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return upload, nil
+}
+
+// CompleteUpload puts the chunks back together, checks them against the size and hash the client gave,
+// and stores the file. An upload that fails the hash check is thrown away, the data in it is no good.
+func CompleteUpload(ctx context.Context, uploadID, createdBy string) (*db.Gcode, *db.Upload, error) {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, terror.New(err, "")
+	}
+	defer tx.Rollback()
+
+	// Lock the upload so completing it twice at once can't store the file twice,
+	// the second one finds it gone once the first commits
+	upload, err := findUpload(tx, uploadID, createdBy, qm.For("UPDATE"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if upload.ReceivedBytes != upload.SizeBytes {
+		return nil, upload, NewAPIError(http.StatusConflict, CodeOffsetMismatch, fmt.Sprintf("upload has %d of %d bytes", upload.ReceivedBytes, upload.SizeBytes), nil)
+	}
+	chunks, err := db.UploadChunks(db.UploadChunkWhere.UploadID.EQ(upload.ID), qm.OrderBy(db.UploadChunkColumns.StartOffset)).All(tx)
+	if err != nil {
+		return nil, upload, terror.New(err, "")
+	}
+	data := make([]byte, 0, upload.SizeBytes)
+	for _, c := range chunks {
+		if c.StartOffset != int64(len(data)) {
+			return nil, upload, errInternal(fmt.Errorf("upload %s has a gap at %d", upload.ID, len(data)))
+		}
+		data = append(data, c.Data...)
+	}
+	if int64(len(data)) != upload.SizeBytes {
+		return nil, upload, errInternal(fmt.Errorf("upload %s has %d bytes stored, expected %d", upload.ID, len(data), upload.SizeBytes))
+	}
+
+	if upload.Sha256.Valid {
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != upload.Sha256.String {
+			_, err = upload.Delete(tx)
+			if err != nil {
+				return nil, upload, terror.New(err, "")
+			}
+			err = tx.Commit()
+			if err != nil {
+				return nil, upload, terror.New(err, "")
+			}
+			return nil, upload, NewAPIError(http.StatusUnprocessableEntity, CodeChecksumMismatch, "uploaded data does not match sha256, start the upload again", nil)
+		}
+	}
+	gc, err := storeGcode(tx, &Upload{
+		Name:       upload.Name,
+		Data:       data,
+		FolderID:   upload.FolderID.String,
+		Tags:       upload.Tags,
+		NewVersion: upload.NewVersion,
+		UploadedBy: upload.CreatedBy,
+	})
+	if err != nil {
+		return nil, upload, err
+	}
+	_, err = upload.Delete(tx)
+	if err != nil {
+		return nil, upload, terror.New(err, "")
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, upload, terror.New(err, "")
+	}
+	return gc, upload, nil
+}
+
+// writeUpload sends where the upload is up to, in the header as well as the body
+func writeUpload(w http.ResponseWriter, upload *db.Upload) (int, error) {
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(upload.ReceivedBytes, 10))
+	return writePayload(w, upload)
+}
+
+func (c *Controller) v2UploadsCreate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &UploadRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	upload, err := CreateUpload(req, PrincipalFromContext(r.Context()).Actor())
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return writeUpload(w, upload)
+}
+
+func (c *Controller) v2UploadGet(w http.ResponseWriter, r *http.Request) (int, error) {
+	upload, err := findUpload(boil.GetDB(), chi.URLParam(r, "id"), PrincipalFromContext(r.Context()).Actor())
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	return writeUpload(w, upload)
+}
+
+func (c *Controller) v2UploadChunk(w http.ResponseWriter, r *http.Request) (int, error) {
+	offset, err := strconv.ParseInt(r.Header.Get(UploadOffsetHeader), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(fmt.Errorf("%s header must be a number", UploadOffsetHeader), "")
+	}
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxChunkSize))
+	if err != nil {
+		return http.StatusRequestEntityTooLarge, terror.New(fmt.Errorf("chunks can't be bigger than %d bytes: %w", MaxChunkSize, err), "")
+	}
+	upload, err := AppendChunk(r.Context(), chi.URLParam(r, "id"), PrincipalFromContext(r.Context()).Actor(), offset, data)
+	if upload != nil {
+		w.Header().Set(UploadOffsetHeader, strconv.FormatInt(upload.ReceivedBytes, 10))
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return writeUpload(w, upload)
+}
+
+func (c *Controller) v2UploadComplete(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, upload, err := CompleteUpload(r.Context(), chi.URLParam(r, "id"), PrincipalFromContext(r.Context()).Actor())
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	c.audit(r, "", AuditFileUpload, map[string]interface{}{"file_id": gc.ID, "name": gc.Name, "size": upload.SizeBytes, "version": gc.Version, "upload_id": upload.ID})
+	return writePayload(w, gc)
+}
+
+func (c *Controller) v2UploadDelete(w http.ResponseWriter, r *http.Request) (int, error) {
+	upload, err := findUpload(boil.GetDB(), chi.URLParam(r, "id"), PrincipalFromContext(r.Context()).Actor())
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	_, err = upload.DeleteG()
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return http.StatusOK, nil
+}
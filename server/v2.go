@@ -0,0 +1,151 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"go-3dprint/db"
+	"go-3dprint/messages"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/gofrs/uuid"
+	"github.com/ninja-software/terror"
+)
+
+// PrinterResource is a registered printer along with what its agent last reported
+type PrinterResource struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	CreatedAt time.Time           `json:"created_at"`
+	Connected bool                `json:"connected"` // Its agent has a session
+	Info      *messages.AgentInfo `json:"info"`      // Nil while the agent isn't connected
+	Job       *Job                `json:"job"`       // Nil until a file has been loaded
+}
+
+// printerResource fills in the printer's session and job
+func (c *Controller) printerResource(printer *db.Printer) *PrinterResource {
+	result := &PrinterResource{ID: printer.ID, Name: printer.Name, CreatedAt: printer.CreatedAt}
+	c.Lock()
+	if chs, ok := c.Sessions[printer.ID]; ok {
+		result.Connected = true
+		result.Info = chs.Info
+	}
+	c.Unlock()
+	result.Job = c.job(printer.ID)
+	return result
+}
+
+// findPrinter looks up a registered printer, a 404 when there isn't one
+func findPrinter(printerID string) (*db.Printer, error) {
+	notFound := NewAPIError(http.StatusNotFound, CodeNotFound, "printer not found", nil)
+	if _, err := uuid.FromString(printerID); err != nil {
+		return nil, notFound
+	}
+	printer, err := db.Printers(db.PrinterWhere.ID.EQ(printerID), db.PrinterWhere.DeletedAt.IsNull()).OneG()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound
+	}
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return printer, nil
+}
+
+func (c *Controller) v2PrintersList(w http.ResponseWriter, r *http.Request) (int, error) {
+	printers, err := db.Printers(db.PrinterWhere.DeletedAt.IsNull()).AllG()
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	result := []*PrinterResource{}
+	for _, p := range printers {
+		result = append(result, c.printerResource(p))
+	}
+	return writePayload(w, result)
+}
+
+func (c *Controller) v2PrinterGet(w http.ResponseWriter, r *http.Request) (int, error) {
+	printer, err := findPrinter(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	return writePayload(w, c.printerResource(printer))
+}
+
+func (c *Controller) v2JobsList(w http.ResponseWriter, r *http.Request) (int, error) {
+	printer, err := findPrinter(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	result := []*Job{}
+	if job := c.job(printer.ID); job != nil {
+		result = append(result, job)
+	}
+	return writePayload(w, result)
+}
+
+func (c *Controller) v2JobsCreate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &JobRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	job, err := c.loadJob(r, chi.URLParam(r, "id"), req.FileID, req.Version)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return writePayload(w, job)
+}
+
+func (c *Controller) v2CommandsCreate(w http.ResponseWriter, r *http.Request) (int, error) {
+	req := &CommandRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return http.StatusBadRequest, terror.New(err, "")
+	}
+	err = c.runCommand(r, chi.URLParam(r, "id"), req)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func (c *Controller) v2GcodeGet(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	return writePayload(w, gc)
+}
+
+func (c *Controller) v2GcodeContent(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	return writeGcode(w, r, gc)
+}
+
+func (c *Controller) v2GcodeThumbnail(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	if !gc.ThumbnailBlobID.Valid {
+		return http.StatusNotFound, NewAPIError(http.StatusNotFound, CodeNotFound, "file has no thumbnail", nil)
+	}
+	blob, err := db.FindBlobG(gc.ThumbnailBlobID.String)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	data, err := blobData(blob)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	w.Header().Set("Content-Type", blob.MimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+	return http.StatusOK, nil
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CodeInvalidRequest is a request that doesn't match the OpenAPI document
+const CodeInvalidRequest ErrorCode = "invalid_request"
+
+// maxValidatedBody is the largest JSON body read to validate it
+const maxValidatedBody = 1 << 20
+
+// operation finds the operation for the request, nil when it isn't in the document
+func (doc *OpenAPIDoc) operation(method, path string) *Operation {
+	method = strings.ToLower(method)
+	if op, ok := doc.Paths[path][method]; ok {
+		return op
+	}
+	segs := strings.Split(path, "/")
+	for template, ops := range doc.Paths {
+		if !strings.Contains(template, "{") {
+			continue
+		}
+		op, ok := ops[method]
+		if !ok {
+			continue
+		}
+		tsegs := strings.Split(template, "/")
+		if len(tsegs) != len(segs) {
+			continue
+		}
+		match := true
+		for i := range tsegs {
+			if !strings.HasPrefix(tsegs[i], "{") && tsegs[i] != segs[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return op
+		}
+	}
+	return nil
+}
+
+// validate checks requests against the OpenAPI document before they reach the handler.
+// Requests for routes the document doesn't know are let through, the router deals with them.
+func (c *Controller) validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := c.spec.operation(r.Method, r.URL.Path)
+		if op == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		err := c.spec.validateRequest(op, r)
+		if err != nil {
+			writeError(w, NewAPIError(http.StatusBadRequest, CodeInvalidRequest, err.Error(), err))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateRequest checks the query and header parameters and JSON body. The body is put back for the handler to read.
+func (doc *OpenAPIDoc) validateRequest(op *Operation, r *http.Request) error {
+	q := r.URL.Query()
+	for _, p := range op.Parameters {
+		var v string
+		switch p.In {
+		case "query":
+			v = q.Get(p.Name)
+		case "header":
+			v = r.Header.Get(p.Name)
+		default:
+			continue
+		}
+		if v == "" {
+			if p.Required {
+				return fmt.Errorf("%s parameter %s is required", p.In, p.Name)
+			}
+			continue
+		}
+		err := validateParam(p.Schema, v)
+		if err != nil {
+			return fmt.Errorf("%s parameter %s %w", p.In, p.Name, err)
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		// Multipart uploads and chunks are checked by the handler as it reads them
+		return nil
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r.Body, maxValidatedBody+1))
+	if err != nil {
+		return fmt.Errorf("body could not be read: %w", err)
+	}
+	if len(b) > maxValidatedBody {
+		return fmt.Errorf("body is larger than %d bytes", maxValidatedBody)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if len(bytes.TrimSpace(b)) == 0 {
+		if op.RequestBody.Required {
+			return errors.New("body is required")
+		}
+		return nil
+	}
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	if err != nil {
+		return fmt.Errorf("body is not valid JSON: %w", err)
+	}
+	return doc.validateValue(media.Schema, v, "body")
+}
+
+// validateParam checks a query parameter can be read as its schema's type
+func validateParam(s *Schema, v string) error {
+	switch s.Type {
+	case "integer":
+		if _, err := strconv.Atoi(v); err != nil {
+			return errors.New("must be an integer")
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return errors.New("must be a number")
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(v); err != nil {
+			return errors.New("must be true or false")
+		}
+	case "string":
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				return errors.New("must be an RFC 3339 time")
+			}
+		}
+	}
+	return checkEnum(s, v)
+}
+
+func checkEnum(s *Schema, v string) error {
+	if len(s.Enum) == 0 {
+		return nil
+	}
+	for _, e := range s.Enum {
+		if e == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(s.Enum, ", "))
+}
+
+// validateValue checks a decoded JSON value against the schema. Unknown properties are allowed.
+func (doc *OpenAPIDoc) validateValue(s *Schema, v interface{}, path string) error {
+	if s.Ref != "" {
+		s = doc.Components.Schemas[s.RefName()]
+	}
+	if v == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s must not be null", path)
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s.%s is required", path, name)
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				prop = s.AdditionalProperties
+			}
+			if prop == nil {
+				continue
+			}
+			err := doc.validateValue(prop, obj[name], path+"."+name)
+			if err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+		for i, item := range arr {
+			err := doc.validateValue(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", path)
+		}
+		err := checkEnum(s, str)
+		if err != nil {
+			return fmt.Errorf("%s %w", path, err)
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s must be an integer", path)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s must be a number", path)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be true or false", path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// widgetDoc describes a single operation covering the kinds of checks validate makes
+func widgetDoc() *OpenAPIDoc {
+	return &OpenAPIDoc{
+		Paths: map[string]map[string]*Operation{
+			"/api/widgets/{id}": {
+				"post": {
+					Parameters: []*Parameter{
+						{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+						{Name: "limit", In: "query", Schema: &Schema{Type: "integer"}},
+						{Name: "since", In: "query", Schema: &Schema{Type: "string", Format: "date-time"}},
+						{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}},
+						{Name: "X-Mode", In: "header", Required: true, Schema: &Schema{Type: "string", Enum: []string{"fast", "slow"}}},
+					},
+					RequestBody: &RequestBody{
+						Required: true,
+						Content:  map[string]*MediaType{"application/json": {Schema: &Schema{Ref: schemaRefPrefix + "Widget"}}},
+					},
+				},
+			},
+		},
+		Components: &Components{Schemas: map[string]*Schema{
+			"Widget": {
+				Type:     "object",
+				Required: []string{"name"},
+				Properties: map[string]*Schema{
+					"name":   {Type: "string"},
+					"count":  {Type: "integer"},
+					"weight": {Type: "number"},
+					"kind":   {Type: "string", Enum: []string{"round", "square"}},
+					"tags":   {Type: "array", Items: &Schema{Type: "string"}},
+					"note":   {Type: "string", Nullable: true},
+				},
+				AdditionalProperties: &Schema{Type: "boolean"},
+			},
+		}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		mode    string
+		body    string
+		wantErr string
+	}{
+		{name: "valid", path: "/api/widgets/1?limit=5&since=2026-10-19T12:00:00Z&dry_run=true", body: `{"name":"a","count":2,"weight":1.5,"kind":"round","tags":["x"],"note":null,"extra":true}`},
+		{name: "route outside the document", path: "/api/other", body: "not json"},
+		{name: "method outside the document", method: http.MethodGet, path: "/api/widgets/1"},
+		{name: "integer query", path: "/api/widgets/1?limit=five", body: `{"name":"a"}`, wantErr: "query parameter limit must be an integer"},
+		{name: "date-time query", path: "/api/widgets/1?since=yesterday", body: `{"name":"a"}`, wantErr: "must be an RFC 3339 time"},
+		{name: "boolean query", path: "/api/widgets/1?dry_run=perhaps", body: `{"name":"a"}`, wantErr: "must be true or false"},
+		{name: "missing header", path: "/api/widgets/1", mode: "-", body: `{"name":"a"}`, wantErr: "header parameter X-Mode is required"},
+		{name: "header not in enum", path: "/api/widgets/1", mode: "medium", body: `{"name":"a"}`, wantErr: "must be one of fast, slow"},
+		{name: "missing body", path: "/api/widgets/1", wantErr: "body is required"},
+		{name: "not json", path: "/api/widgets/1", body: `{"name":`, wantErr: "body is not valid JSON"},
+		{name: "not an object", path: "/api/widgets/1", body: `[]`, wantErr: "body must be an object"},
+		{name: "missing property", path: "/api/widgets/1", body: `{}`, wantErr: "body.name is required"},
+		{name: "null property", path: "/api/widgets/1", body: `{"name":null}`, wantErr: "body.name must not be null"},
+		{name: "fractional integer", path: "/api/widgets/1", body: `{"name":"a","count":1.5}`, wantErr: "body.count must be an integer"},
+		{name: "string number", path: "/api/widgets/1", body: `{"name":"a","weight":"1"}`, wantErr: "body.weight must be a number"},
+		{name: "property not in enum", path: "/api/widgets/1", body: `{"name":"a","kind":"oval"}`, wantErr: "body.kind must be one of round, square"},
+		{name: "array item", path: "/api/widgets/1", body: `{"name":"a","tags":["x",1]}`, wantErr: "body.tags[1] must be a string"},
+		{name: "additional property", path: "/api/widgets/1", body: `{"name":"a","extra":"yes"}`, wantErr: "body.extra must be true or false"},
+		{name: "too big", path: "/api/widgets/1", body: `{"name":"` + strings.Repeat("a", maxValidatedBody) + `"}`, wantErr: "body is larger than"},
+	}
+	c := &Controller{spec: widgetDoc()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerBody string
+			handler := c.validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				handlerBody = string(b)
+			}))
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			r := httptest.NewRequest(method, tt.path, strings.NewReader(tt.body))
+			switch tt.mode {
+			case "":
+				r.Header.Set("X-Mode", "fast")
+			case "-":
+			default:
+				r.Header.Set("X-Mode", tt.mode)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if tt.wantErr != "" {
+				if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.wantErr) {
+					t.Fatalf("got %d %s, want 400 with %q", w.Code, w.Body.String(), tt.wantErr)
+				}
+				return
+			}
+			if w.Code != http.StatusOK {
+				t.Fatalf("got %d %s", w.Code, w.Body.String())
+			}
+			if handlerBody != tt.body {
+				t.Errorf("handler read %q, want the body put back as %q", handlerBody, tt.body)
+			}
+		})
+	}
+}
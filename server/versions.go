@@ -0,0 +1,378 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-3dprint/codec"
+	"go-3dprint/db"
+	"go-3dprint/gcodefile"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/ninja-software/terror"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+// StoreCodec is how new blobs are compressed
+const StoreCodec = codec.Zstd
+
+// GcodeMimeType is the type gcode blobs are stored as
+const GcodeMimeType = "text/x-gcode"
+
+// thumbnailMimeTypes are the types of the thumbnail formats browsers can show
+var thumbnailMimeTypes = map[string]string{"png": "image/png", "jpg": "image/jpeg"}
+
+// Upload is a gcode file being added to the library
+type Upload struct {
+	Name       string // Gzip is unpacked and .gz dropped, binary gcode and 3MF are unpacked to .gcode
+	Data       []byte
+	FolderID   string // Empty for the root
+	Tags       []string
+	NewVersion bool   // Add a version to the live file with the same name in the same folder, if there is one
+	UploadedBy string // Actor for the version history
+}
+
+// StoreGcode saves an upload, reusing the blob of any identical content already stored. Binary gcode and
+// 3MF archives are stored as plain gcode, with their metadata and largest thumbnail kept on the file.
+// Uploading a file's current content again as a new version changes nothing.
+func StoreGcode(ctx context.Context, u *Upload) (*db.Gcode, error) {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	defer tx.Rollback()
+	gc, err := storeGcode(tx, u)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return gc, nil
+}
+
+// storeGcode is StoreGcode inside the caller's transaction
+func storeGcode(tx boil.Executor, u *Upload) (*db.Gcode, error) {
+	if strings.HasSuffix(strings.ToLower(u.Name), ".gz") || codec.IsGzip(u.Data) {
+		data, err := codec.Decode(codec.Gzip, bytes.NewReader(u.Data), MaxUploadSize)
+		if err != nil {
+			return nil, errBadRequest(fmt.Errorf("unpacking gzipped upload: %w", err))
+		}
+		u.Data = data
+		if strings.HasSuffix(strings.ToLower(u.Name), ".gz") {
+			u.Name = u.Name[:len(u.Name)-len(".gz")]
+		}
+	}
+	file, err := gcodefile.Decode(u.Data, MaxUploadSize)
+	if err != nil {
+		return nil, errBadRequest(fmt.Errorf("unpacking %s: %w", u.Name, err))
+	}
+	u.Data = file.Gcode
+	u.Name = gcodefile.GcodeName(u.Name, file.Format)
+	metadata, err := json.Marshal(file.Metadata)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+
+	blob, err := findOrCreateBlob(tx, u.Name, GcodeMimeType, u.Data)
+	if err != nil {
+		return nil, err
+	}
+	folderID := null.NewString(u.FolderID, u.FolderID != "")
+
+	var gc *db.Gcode
+	if u.NewVersion {
+		// Nothing to lock while the file doesn't exist yet, so uploads of the same name wait on each other
+		// here, or both would find no file and both add one
+		_, err = tx.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", u.FolderID+"/"+u.Name)
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		mods := []qm.QueryMod{
+			db.GcodeWhere.Name.EQ(u.Name),
+			db.GcodeWhere.DeletedAt.IsNull(),
+			db.GcodeWhere.FolderID.IsNull(),
+			qm.OrderBy(db.GcodeColumns.CreatedAt + " DESC"),
+			qm.For("UPDATE"),
+		}
+		if folderID.Valid {
+			mods[2] = db.GcodeWhere.FolderID.EQ(folderID)
+		}
+		gc, err = db.Gcodes(mods...).One(tx)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, terror.New(err, "")
+		}
+	}
+
+	if gc != nil && gc.BlobID == blob.ID {
+		return gc, nil
+	}
+	thumbnailID := null.String{}
+	if thumb := file.Largest(); thumb != nil {
+		t, err := findOrCreateBlob(tx, u.Name+"."+thumb.Format, thumbnailMimeTypes[thumb.Format], thumb.Data)
+		if err != nil {
+			return nil, err
+		}
+		thumbnailID = null.StringFrom(t.ID)
+	}
+
+	if gc != nil {
+		oldThumbnailID := gc.ThumbnailBlobID
+		gc.Version++
+		gc.BlobID = blob.ID
+		gc.Format = file.Format
+		gc.Metadata = metadata
+		gc.ThumbnailBlobID = thumbnailID
+		gc.UpdatedAt = time.Now()
+		_, err = gc.Update(tx, boil.Whitelist(
+			db.GcodeColumns.Version, db.GcodeColumns.BlobID, db.GcodeColumns.Format,
+			db.GcodeColumns.Metadata, db.GcodeColumns.ThumbnailBlobID, db.GcodeColumns.UpdatedAt,
+		))
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		if oldThumbnailID.Valid && oldThumbnailID != thumbnailID {
+			err = deleteUnusedBlobs(tx, []interface{}{oldThumbnailID.String})
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		gc = &db.Gcode{
+			Name:            u.Name,
+			BlobID:          blob.ID,
+			FolderID:        folderID,
+			Tags:            normaliseTags(u.Tags),
+			Version:         1,
+			Format:          file.Format,
+			Metadata:        metadata,
+			ThumbnailBlobID: thumbnailID,
+		}
+		err = gc.Insert(tx, boil.Infer())
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+	}
+
+	version := &db.GcodeVersion{GcodeID: gc.ID, Version: gc.Version, BlobID: blob.ID, UploadedBy: u.UploadedBy}
+	err = version.Insert(tx, boil.Infer())
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return gc, nil
+}
+
+// findOrCreateBlob reuses the blob holding the same content, if there is one. New blobs are compressed
+// with StoreCodec, images are already compressed and are stored as they are. The hash and size are of
+// the uncompressed data.
+func findOrCreateBlob(exec boil.Executor, name, mimeType string, data []byte) (*db.Blob, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	blob, err := findBlob(exec, hash)
+	if err == nil {
+		return blob, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, terror.New(err, "")
+	}
+	storeCodec := StoreCodec
+	if strings.HasPrefix(mimeType, "image/") {
+		storeCodec = codec.Identity
+	}
+	stored, err := codec.Encode(storeCodec, data)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	blob = &db.Blob{Data: stored, Codec: storeCodec, FileName: name, MimeType: mimeType, FileSizeBytes: int64(len(data)), Sha256: hash}
+	// The same content uploaded twice at once both get here, only one insert lands and both use its blob
+	err = blob.Upsert(exec, false, []string{db.BlobColumns.Sha256}, boil.None(), boil.Infer())
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	if blob.ID != "" {
+		return blob, nil
+	}
+	blob, err = findBlob(exec, hash)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return blob, nil
+}
+
+// findBlob looks up the blob holding the content with the hash, without its data
+func findBlob(exec boil.Executor, hash string) (*db.Blob, error) {
+	return db.Blobs(qm.Select(db.BlobColumns.ID), db.BlobWhere.Sha256.EQ(hash)).One(exec)
+}
+
+// blobData is the blob's content, uncompressed
+func blobData(blob *db.Blob) ([]byte, error) {
+	data, err := codec.Decode(blob.Codec, bytes.NewReader(blob.Data), 0)
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return data, nil
+}
+
+// VersionInfo is one version in a file's history
+type VersionInfo struct {
+	Version    int       `json:"version"`
+	SHA256     string    `json:"sha256"`
+	SizeBytes  int64     `json:"size_bytes"`
+	UploadedBy string    `json:"uploaded_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	Current    bool      `json:"current"` // The one the file downloads and prints by default
+}
+
+// ListVersions returns the file's history, newest first
+func ListVersions(gc *db.Gcode) ([]*VersionInfo, error) {
+	versions, err := db.GcodeVersions(db.GcodeVersionWhere.GcodeID.EQ(gc.ID), qm.OrderBy(db.GcodeVersionColumns.Version+" DESC")).AllG()
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	blobIDs := []interface{}{}
+	for _, v := range versions {
+		blobIDs = append(blobIDs, v.BlobID)
+	}
+	blobs := map[string]*db.Blob{}
+	if len(blobIDs) > 0 {
+		// Leave the data behind, only the size and hash are needed
+		rows, err := db.Blobs(
+			qm.Select(db.BlobColumns.ID, db.BlobColumns.Sha256, db.BlobColumns.FileSizeBytes),
+			qm.WhereIn(db.BlobColumns.ID+" IN ?", blobIDs...),
+		).AllG()
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		for _, b := range rows {
+			blobs[b.ID] = b
+		}
+	}
+	result := []*VersionInfo{}
+	for _, v := range versions {
+		info := &VersionInfo{Version: v.Version, UploadedBy: v.UploadedBy, CreatedAt: v.CreatedAt, Current: v.Version == gc.Version}
+		if b, ok := blobs[v.BlobID]; ok {
+			info.SHA256 = b.Sha256
+			info.SizeBytes = b.FileSizeBytes
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// findVersion looks up one version of a file, a 404 when it doesn't have it
+func findVersion(gcodeID string, version int) (*db.GcodeVersion, error) {
+	v, err := db.GcodeVersions(db.GcodeVersionWhere.GcodeID.EQ(gcodeID), db.GcodeVersionWhere.Version.EQ(version)).OneG()
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, NewAPIError(http.StatusNotFound, CodeNotFound, fmt.Sprintf("version %d not found", version), err)
+	}
+	if err != nil {
+		return nil, terror.New(err, "")
+	}
+	return v, nil
+}
+
+// versionParam reads a version number from the query or URL
+func versionParam(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 {
+		return 0, errBadRequest(fmt.Errorf("version must be a positive number, got %s", s))
+	}
+	return v, nil
+}
+
+// SettingsDiff is how the slicer settings changed between two versions of a file
+type SettingsDiff struct {
+	From    int              `json:"from"`
+	To      int              `json:"to"`
+	Changes []*SettingChange `json:"changes"`
+}
+
+// DiffVersions compares the slicer settings embedded in two versions of a file
+func DiffVersions(gc *db.Gcode, from, to int) (*SettingsDiff, error) {
+	settings := []map[string]string{}
+	for _, n := range []int{from, to} {
+		v, err := findVersion(gc.ID, n)
+		if err != nil {
+			return nil, err
+		}
+		blob, err := db.FindBlobG(v.BlobID)
+		if err != nil {
+			return nil, terror.New(err, "")
+		}
+		data, err := blobData(blob)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, SlicerSettings(data))
+	}
+	return &SettingsDiff{From: from, To: to, Changes: DiffSettings(settings[0], settings[1])}, nil
+}
+
+func (c *Controller) v2VersionsList(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	result, err := ListVersions(gc)
+	if err != nil {
+		return http.StatusInternalServerError, terror.New(err, "")
+	}
+	return writePayload(w, result)
+}
+
+func (c *Controller) v2VersionContent(w http.ResponseWriter, r *http.Request) (int, error) {
+	n, err := versionParam(chi.URLParam(r, "version"))
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	v, err := findVersion(gc.ID, n)
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	return writeBlob(w, r, gc.Name, v.BlobID)
+}
+
+func (c *Controller) v2VersionsDiff(w http.ResponseWriter, r *http.Request) (int, error) {
+	gc, err := findGcode(chi.URLParam(r, "id"))
+	if err != nil {
+		return http.StatusNotFound, err
+	}
+	to := gc.Version
+	if s := r.URL.Query().Get("to"); s != "" {
+		to, err = versionParam(s)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+	from := to - 1
+	if s := r.URL.Query().Get("from"); s != "" {
+		from, err = versionParam(s)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+	if from < 1 {
+		return http.StatusBadRequest, terror.New(errors.New("there is no earlier version to compare with"), "")
+	}
+	result, err := DiffVersions(gc, from, to)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return writePayload(w, result)
+}
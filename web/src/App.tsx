@@ -11,7 +11,7 @@ import Typography from "@material-ui/core/Typography"
 import Box from "@material-ui/core/Box"
 import Link from "@material-ui/core/Link"
 import { DropzoneArea } from "material-ui-dropzone"
-import { Button, Card, CardActions, CardContent, CardHeader, Divider, IconButton, List, ListItem, Menu, MenuItem, Paper } from "@material-ui/core"
+import { Button, Card, CardActions, CardContent, CardHeader, Divider, IconButton, List, ListItem, Menu, MenuItem, Paper, TextField } from "@material-ui/core"
 import { Action, useMutation, useQuery } from "react-fetching-library"
 import { createClient, ClientContextProvider } from "react-fetching-library"
 import { Frown, Pause, Printer, StopCircle } from "react-feather"
@@ -31,26 +31,85 @@ function App() {
 	return (
 		<ClientContextProvider client={client}>
 			<Container maxWidth="sm">
-				<Box display={"flex"} flexDirection={"column"}>
-					<Box margin={2}>
-						<Sessions setSession={setSession} />
+				<RequireLogin>
+					<Box display={"flex"} flexDirection={"column"}>
+						<Box margin={2}>
+							<Sessions setSession={setSession} />
+						</Box>
+						{sessionId && (
+							<>
+								<Box margin={2}>
+									<ControlPanel sessionId={sessionId} />
+								</Box>
+								<Box margin={2}>
+									<Files sessionId={sessionId} />
+								</Box>
+							</>
+						)}
 					</Box>
-					{sessionId && (
-						<>
-							<Box margin={2}>
-								<ControlPanel sessionId={sessionId} />
-							</Box>
-							<Box margin={2}>
-								<Files sessionId={sessionId} />
-							</Box>
-						</>
-					)}
-				</Box>
+				</RequireLogin>
 			</Container>
 		</ClientContextProvider>
 	)
 }
 
+interface User {
+	id: string
+	email: string
+	role: string
+}
+interface LoginRequest {
+	email: string
+	password: string
+}
+const loginRequester = (payload: LoginRequest): Action => {
+	return { endpoint: "/api/auth/login", method: "POST", responseType: "json", body: payload }
+}
+const RequireLogin = (props: { children: React.ReactNode }) => {
+	const { loading, payload, error, query } = useQuery<APIResponse<User>>({ endpoint: "/api/auth/me", method: "GET", responseType: "json" }, true)
+	if (loading) {
+		return <Skeleton variant="text" />
+	}
+	if (error || !payload || !payload.payload) {
+		return (
+			<Box margin={2}>
+				<Login onLogin={() => query()} />
+			</Box>
+		)
+	}
+	return <>{props.children}</>
+}
+const Login = (props: { onLogin: () => void }) => {
+	const [email, setEmail] = React.useState("")
+	const [password, setPassword] = React.useState("")
+	const [err, setErr] = React.useState<string | undefined>()
+	const { mutate: login } = useMutation<APIResponse<User>, {}, LoginRequest>(loginRequester)
+	return (
+		<Card>
+			<CardHeader title={"Log in"} />
+			<CardContent>
+				{err && <Alert severity="error">{err}</Alert>}
+				<TextField fullWidth label="Email" value={email} onChange={(e) => setEmail(e.target.value)} />
+				<TextField fullWidth label="Password" type="password" value={password} onChange={(e) => setPassword(e.target.value)} />
+			</CardContent>
+			<CardActions>
+				<Button
+					onClick={async () => {
+						const { error } = await login({ email, password })
+						if (error) {
+							setErr("Wrong email or password")
+							return
+						}
+						props.onLogin()
+					}}
+				>
+					Log in
+				</Button>
+			</CardActions>
+		</Card>
+	)
+}
+
 interface PrinterInfo {
 	busy: boolean
 	status: string